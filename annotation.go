@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/tzmfreedom/land/ast"
+	"github.com/tzmfreedom/land/parser"
+)
+
+// JavaAnnotation is the Java annotation an Apex annotation is emitted as.
+type JavaAnnotation struct {
+	Name string
+	// Import is the ImportClasses key of the runtime annotation, or empty
+	// for annotations in java.lang.
+	Import string
+	// Elements maps lowercased Apex parameter names to the Java element
+	// names, since Java annotations are case-sensitive.
+	Elements map[string]string
+}
+
+// JavaAnnotations maps lowercased Apex annotation names to Java. Annotations
+// not listed are emitted as comments.
+var JavaAnnotations = map[string]JavaAnnotation{
+	"auraenabled": {"AuraEnabled", "auraenabled", map[string]string{
+		"cacheable":    "cacheable",
+		"continuation": "continuation",
+	}},
+	"deprecated": {"Deprecated", "", nil},
+	"future": {"Future", "future", map[string]string{
+		"callout": "callout",
+	}},
+	"invocablemethod": {"InvocableMethod", "invocablemethod", map[string]string{
+		"label":               "label",
+		"description":         "description",
+		"callout":             "callout",
+		"category":            "category",
+		"configurationeditor": "configurationEditor",
+		"iconname":            "iconName",
+	}},
+	"invocablevariable": {"InvocableVariable", "invocablevariable", map[string]string{
+		"label":       "label",
+		"description": "description",
+		"required":    "required",
+	}},
+	"istest": {"IsTest", "istest", map[string]string{
+		"seealldata": "seeAllData",
+		"isparallel": "isParallel",
+		"oninstall":  "onInstall",
+	}},
+	"readonly":     {"ReadOnly", "readonly", nil},
+	"remoteaction": {"RemoteAction", "remoteaction", nil},
+	"testsetup":    {"TestSetup", "testsetup", nil},
+	"testvisible":  {"TestVisible", "testvisible", nil},
+}
+
+// annotationParameters reads the parameters of every annotation in the
+// parse tree, which the AST builder drops, keyed by annotation location.
+// A named parameter is returned as an assignment to its name.
+func annotationParameters(tree antlr.Tree, src string) map[ast.Location][]ast.Node {
+	parameters := map[ast.Location][]ast.Node{}
+	builder := &ast.Builder{Source: src}
+	var walk func(t antlr.Tree)
+	walk = func(t antlr.Tree) {
+		if a, ok := t.(*parser.AnnotationContext); ok {
+			params := []ast.Node{}
+			if pairs := a.ElementValuePairs(); pairs != nil {
+				for _, p := range pairs.(*parser.ElementValuePairsContext).AllElementValuePair() {
+					pair := p.(*parser.ElementValuePairContext)
+					value := elementValue(pair.ElementValue(), builder)
+					if value == nil {
+						continue
+					}
+					params = append(params, &ast.BinaryOperator{
+						Op:       "=",
+						Left:     &ast.Name{Value: []string{pair.ApexIdentifier().GetText()}},
+						Right:    value,
+						Location: newLocation(pair, src),
+					})
+				}
+			} else if value := elementValue(a.ElementValue(), builder); value != nil {
+				params = append(params, value)
+			}
+			parameters[*newLocation(a, src)] = params
+		}
+		for _, child := range t.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(tree)
+	return parameters
+}
+
+func elementValue(ctx parser.IElementValueContext, builder *ast.Builder) ast.Node {
+	if ctx == nil {
+		return nil
+	}
+	e := ctx.(*parser.ElementValueContext).Expression()
+	if e == nil {
+		return nil
+	}
+	return e.Accept(builder).(ast.Node)
+}
+
+// attachAnnotationParameters sets the parameters returned by
+// annotationParameters on the annotations of n and its members.
+func attachAnnotationParameters(n ast.Node, parameters map[ast.Location][]ast.Node) {
+	set := func(annotations []*ast.Annotation) {
+		for _, a := range annotations {
+			a.Parameters = parameters[*a.Location]
+			for _, p := range a.Parameters {
+				p.SetParent(a)
+			}
+		}
+	}
+	switch decl := n.(type) {
+	case *ast.ClassDeclaration:
+		set(decl.Annotations)
+		for _, d := range decl.Declarations {
+			attachAnnotationParameters(d, parameters)
+		}
+	case *ast.InterfaceDeclaration:
+		set(decl.Annotations)
+		for _, m := range decl.Methods {
+			attachAnnotationParameters(m, parameters)
+		}
+	case *ast.MethodDeclaration:
+		set(decl.Annotations)
+	case *ast.ConstructorDeclaration:
+		set(decl.Annotations)
+	case *ast.FieldDeclaration:
+		set(decl.Annotations)
+	case *ast.PropertyDeclaration:
+		set(decl.Annotations)
+	}
+}
+
+// annotationParameter returns the name of an annotation parameter and its
+// value. Unnamed parameters are named value, as in Java.
+func annotationParameter(n ast.Node) (string, ast.Node) {
+	if op, ok := n.(*ast.BinaryOperator); ok && op.Op == "=" {
+		if name, ok := op.Left.(*ast.Name); ok && len(name.Value) == 1 {
+			return name.Value[0], op.Right
+		}
+	}
+	return "value", n
+}
+
+func (v *Generator) VisitAnnotation(n *ast.Annotation) (interface{}, error) {
+	annotation, ok := JavaAnnotations[strings.ToLower(n.Name)]
+	name := annotation.Name
+	if !ok {
+		name = n.Name
+	}
+	parameters := []string{}
+	for _, p := range n.Parameters {
+		key, value := annotationParameter(p)
+		if ok {
+			element, known := annotation.Elements[strings.ToLower(key)]
+			if !known {
+				// the Java annotation has no element to take it
+				construct := fmt.Sprintf("parameter %s of @%s", key, n.Name)
+				if _, err := v.leaveOut(construct, nodeLocation(p)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			key = element
+		}
+		r, err := value.Accept(v)
+		if err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, fmt.Sprintf("%s = %s", key, r.(string)))
+	}
+	if len(parameters) == 1 && strings.HasPrefix(parameters[0], "value = ") {
+		parameters[0] = strings.TrimPrefix(parameters[0], "value = ")
+	}
+	src := "@" + name
+	if len(parameters) != 0 {
+		src += fmt.Sprintf("(%s)", strings.Join(parameters, ", "))
+	}
+	if !ok {
+		// Apex annotations without a Java counterpart are kept for reference.
+		return "// " + src, nil
+	}
+	return src, nil
+}
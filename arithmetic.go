@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// Null-safe arithmetic routes operators on boxed Apex primitives through
+// ApexOperator, so a null operand raises System.NullPointerException the way
+// Apex does instead of failing inside Java's auto-unboxing.
+
+var arithmeticOperators = map[string]string{
+	"+": "add",
+	"-": "subtract",
+	"*": "multiply",
+	"/": "divide",
+	"%": "mod",
+}
+
+var compoundAssignmentOperators = map[string]string{
+	"+=": "+",
+	"-=": "-",
+	"*=": "*",
+	"/=": "/",
+	"%=": "%",
+}
+
+var incrementOperators = map[string]string{
+	"++": "increment",
+	"--": "decrement",
+}
+
+// isDecimalArithmetic reports whether n is a compound assignment to a
+// Decimal or an arithmetic operator on a Decimal and an Integer, Long or
+// Decimal. Java has no operators on BigDecimal, so these go through
+// ApexOperator in every mode. A Double operand makes the result a Double,
+// which Java operators handle.
+func (v *Generator) isDecimalArithmetic(n *ast.BinaryOperator) bool {
+	lt, rt := typeName(v.typeOf(n.Left)), typeName(v.typeOf(n.Right))
+	if _, ok := compoundAssignmentOperators[n.Op]; ok {
+		return lt == "decimal"
+	}
+	if _, ok := arithmeticOperators[n.Op]; !ok {
+		return false
+	}
+	exact := func(t string) bool { return t == "integer" || t == "long" || t == "decimal" }
+	return lt == "decimal" && exact(rt) || rt == "decimal" && exact(lt)
+}
+
+// nullSafeBinaryOperator returns the helper call for an arithmetic or
+// compound assignment operator, or false if n is not one.
+func (v *Generator) nullSafeBinaryOperator(n *ast.BinaryOperator, l, r string) (string, bool) {
+	if op, ok := compoundAssignmentOperators[n.Op]; ok {
+		return fmt.Sprintf(
+			"%s = %s",
+			l,
+			v.arithmetic(op, n.Left, n.Right, l, r, v.typeOf(n.Left)),
+		), true
+	}
+	if _, ok := arithmeticOperators[n.Op]; ok {
+		return v.arithmetic(n.Op, n.Left, n.Right, l, r, nil), true
+	}
+	return "", false
+}
+
+// arithmetic emits op on the generated operands l and r. A cast to the
+// wider operand type (or to target, for compound assignments) is added when
+// the operand types differ and the helper falls back to its Number overload.
+func (v *Generator) arithmetic(op string, left, right ast.Node, l, r string, target *ast.TypeRef) string {
+	lt := v.typeOf(left)
+	rt := v.typeOf(right)
+	// Outside null-safe mode decimal literals are Java doubles, which would
+	// select the Number overload.
+	if literal, ok := decimalLiteral(lt, left); ok {
+		l = literal
+	}
+	if literal, ok := decimalLiteral(rt, right); ok {
+		r = literal
+	}
+	if op == "+" {
+		if isStringType(lt) || isStringType(rt) {
+			return fmt.Sprintf("ApexOperator.concat(%s, %s)", l, r)
+		}
+		if lt == nil && rt == nil {
+			return fmt.Sprintf("ApexOperator.plus(%s, %s)", l, r)
+		}
+	}
+	call := fmt.Sprintf("ApexOperator.%s(%s, %s)", arithmeticOperators[op], l, r)
+	if !isNumericType(lt) || !isNumericType(rt) || typeName(lt) == typeName(rt) {
+		return call
+	}
+	if target == nil {
+		target = widerNumericType(lt, rt)
+	}
+	return fmt.Sprintf("(%s) %s", javaTypeName(target), call)
+}
+
+// nullSafeUnaryOperator returns the helper call for ++ and -- on Decimals
+// and for unary minus used inside an expression. ++ and -- on the other
+// numeric types stay Java operators, which fail on null as Apex does.
+func (v *Generator) nullSafeUnaryOperator(n *ast.UnaryOperator, val string) (string, bool) {
+	if method, ok := incrementOperators[n.Op]; ok {
+		if typeName(v.typeOf(n.Expression)) != "decimal" {
+			return "", false
+		}
+		v.importClass("apexoperator")
+		assign := fmt.Sprintf("%s = ApexOperator.%s(%s)", val, method, val)
+		if n.IsPrefix {
+			return fmt.Sprintf("(%s)", assign), true
+		}
+		// The value of a postfix operator is the one before the update,
+		// which BigDecimal arithmetic restores exactly.
+		undo := "decrement"
+		if n.Op == "--" {
+			undo = "increment"
+		}
+		return fmt.Sprintf("ApexOperator.%s(%s)", undo, assign), true
+	}
+	if n.Op == "-" {
+		v.importClass("apexoperator")
+		return fmt.Sprintf("ApexOperator.negate(%s)", val), true
+	}
+	return "", false
+}
+
+// isPrefix reports whether the operator of n precedes its operand. land
+// sets IsPrefix only for ++ and --, whose other operators are prefixes.
+func isPrefix(n *ast.UnaryOperator) bool {
+	_, increment := incrementOperators[n.Op]
+	return n.IsPrefix || !increment
+}
+
+// incrementStatement emits ++ and -- on a Decimal used as a statement,
+// where the value of the expression is discarded. Java has no ++ and -- on
+// BigDecimal, so this applies in every mode.
+func (v *Generator) incrementStatement(n *ast.UnaryOperator) (string, bool) {
+	method, ok := incrementOperators[n.Op]
+	if !ok || typeName(v.typeOf(n.Expression)) != "decimal" {
+		return "", false
+	}
+	r, err := n.Expression.Accept(v)
+	if err != nil {
+		panic(err)
+	}
+	v.importClass("apexoperator")
+	return fmt.Sprintf("%s = ApexOperator.%s(%s)", r.(string), method, r.(string)), true
+}
+
+// decimalLiteral returns the numeric literal n, or its negation, as a
+// BigDecimal if t is Decimal, since Java does not convert numeric literals
+// to BigDecimal. It reports false otherwise.
+func decimalLiteral(t *ast.TypeRef, n ast.Node) (string, bool) {
+	if typeName(t) != "decimal" {
+		return "", false
+	}
+	sign := ""
+	if u, ok := n.(*ast.UnaryOperator); ok && u.Op == "-" {
+		sign = "-"
+		n = u.Expression
+	}
+	switch l := n.(type) {
+	case *ast.IntegerLiteral:
+		return fmt.Sprintf("new BigDecimal(\"%s%d\")", sign, l.Value), true
+	case *ast.DoubleLiteral:
+		return fmt.Sprintf("new BigDecimal(\"%s%s\")", sign, strconv.FormatFloat(l.Value, 'f', -1, 64)), true
+	}
+	return "", false
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+func isFutureMethod(n *ast.MethodDeclaration) bool {
+	return hasAnnotation(n.Annotations, "future")
+}
+
+// futureMethod returns a @future method as a method queueing a call of its
+// body, which is moved to a private method named after it, on the runtime's
+// AsyncApex executor.
+func (v *Generator) futureMethod(n *ast.MethodDeclaration, method *javaMethod) javaNode {
+	body := n.Name + "Future"
+	names := make([]string, len(n.Parameters))
+	for i, p := range n.Parameters {
+		names[i] = p.Name
+	}
+	future := &javaMethod{
+		javaHeader: method.javaHeader,
+		ReturnType: "void",
+		Name:       n.Name,
+		Parameters: method.Parameters,
+		Body:       newBlock(fmt.Sprintf("AsyncApex.future(%s, () -> %s(%s))", javaString(n.Name), body, strings.Join(names, ", "))),
+	}
+	return javaNodes{future, &javaMethod{
+		javaHeader: javaHeader{Modifiers: []string{"private", "static"}},
+		ReturnType: "void",
+		Name:       body,
+		Parameters: method.Parameters,
+		Body:       method.Body,
+	}}
+}
+
+// implementedType returns the interface a class implements as emitted.
+// Java requires the type argument of Database.Batchable to match the
+// parameter of execute, which Apex does not, so it is taken from execute.
+func implementedType(n *ast.ClassDeclaration, impl *ast.TypeRef) *ast.TypeRef {
+	name := apexTypeName(impl.Name)
+	if len(name) != 2 || strings.ToLower(name[0]) != "database" || strings.ToLower(name[1]) != "batchable" {
+		return impl
+	}
+	for _, d := range n.Declarations {
+		m, ok := d.(*ast.MethodDeclaration)
+		if !ok || strings.ToLower(m.Name) != "execute" || len(m.Parameters) != 2 {
+			continue
+		}
+		scope := m.Parameters[1].TypeRef
+		if typeName(scope) == "list" && len(scope.Parameters) == 1 {
+			return &ast.TypeRef{Name: impl.Name, Parameters: scope.Parameters, Location: impl.Location, Parent: impl.Parent}
+		}
+	}
+	return impl
+}
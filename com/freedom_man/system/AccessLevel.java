@@ -0,0 +1,7 @@
+package com.freedom_man.system;
+
+// AccessLevel is the mode of database operations. The runtime does not
+// enforce object and field permissions, so both modes behave the same.
+public enum AccessLevel {
+    SYSTEM_MODE, USER_MODE
+}
@@ -0,0 +1,346 @@
+package com.freedom_man.system;
+
+import java.math.BigDecimal;
+import java.math.MathContext;
+import java.util.Iterator;
+
+public class ApexOperator {
+    // Apex == : Strings compare case-insensitively, numbers by value across
+    // Integer/Long/Double/Decimal, collections element by element, and
+    // everything else through equals(), which compares sObjects field by
+    // field. Converted code compares Ids with equalsId.
+    public static boolean equals(Object l, Object r) {
+        return equals(l, r, true);
+    }
+
+    // equalsCaseSensitive is equals with Strings, including the ones in
+    // collections, compared case-sensitively, as System.assertEquals does.
+    public static boolean equalsCaseSensitive(Object l, Object r) {
+        return equals(l, r, false);
+    }
+
+    private static boolean equals(Object l, Object r, boolean ignoreCase) {
+        if (l == null || r == null) {
+            return l == r;
+        }
+        if (l instanceof String && r instanceof String) {
+            return ignoreCase ? ((String) l).equalsIgnoreCase((String) r) : l.equals(r);
+        }
+        if (l instanceof Number && r instanceof Number) {
+            return toDecimal((Number) l).compareTo(toDecimal((Number) r)) == 0;
+        }
+        if (l instanceof java.util.List && r instanceof java.util.List) {
+            java.util.List<?> ll = (java.util.List<?>) l;
+            java.util.List<?> rl = (java.util.List<?>) r;
+            if (ll.size() != rl.size()) {
+                return false;
+            }
+            Iterator<?> li = ll.iterator();
+            Iterator<?> ri = rl.iterator();
+            while (li.hasNext()) {
+                if (!equals(li.next(), ri.next(), ignoreCase)) {
+                    return false;
+                }
+            }
+            return true;
+        }
+        if (l instanceof java.util.Map && r instanceof java.util.Map) {
+            java.util.Map<?, ?> lm = (java.util.Map<?, ?>) l;
+            java.util.Map<?, ?> rm = (java.util.Map<?, ?>) r;
+            if (lm.size() != rm.size()) {
+                return false;
+            }
+            for (java.util.Map.Entry<?, ?> e : lm.entrySet()) {
+                if (!rm.containsKey(e.getKey()) || !equals(e.getValue(), rm.get(e.getKey()), ignoreCase)) {
+                    return false;
+                }
+            }
+            return true;
+        }
+        return l.equals(r);
+    }
+
+    // Apex == on Ids: case-sensitive, and the 15 and 18 character forms of
+    // an Id are equal.
+    public static boolean equalsId(String l, String r) {
+        if (l == null || r == null) {
+            return l == r;
+        }
+        return id15(l).equals(id15(r));
+    }
+
+    private static String id15(String id) {
+        return id.length() == 18 ? id.substring(0, 15) : id;
+    }
+
+    // Apex < and > on Strings: case-insensitive, and a null String sorts
+    // before any non-null value.
+    public static int compare(String l, String r) {
+        if (l == null || r == null) {
+            if (l == r) {
+                return 0;
+            }
+            return l == null ? -1 : 1;
+        }
+        return l.compareToIgnoreCase(r);
+    }
+
+    // Apex < and > on Dates, Datetimes and Times: null sorts before any
+    // non-null value.
+    public static <T extends Comparable<T>> int compare(T l, T r) {
+        if (l == null || r == null) {
+            if (l == r) {
+                return 0;
+            }
+            return l == null ? -1 : 1;
+        }
+        return l.compareTo(r);
+    }
+
+    public static Integer add(Integer l, Integer r) {
+        return (Integer) arithmetic('+', l, r);
+    }
+
+    public static Long add(Long l, Long r) {
+        return (Long) arithmetic('+', l, r);
+    }
+
+    public static Double add(Double l, Double r) {
+        return (Double) arithmetic('+', l, r);
+    }
+
+    public static BigDecimal add(BigDecimal l, BigDecimal r) {
+        return (BigDecimal) arithmetic('+', l, r);
+    }
+
+    public static Number add(Number l, Number r) {
+        return arithmetic('+', l, r);
+    }
+
+    public static Integer subtract(Integer l, Integer r) {
+        return (Integer) arithmetic('-', l, r);
+    }
+
+    public static Long subtract(Long l, Long r) {
+        return (Long) arithmetic('-', l, r);
+    }
+
+    public static Double subtract(Double l, Double r) {
+        return (Double) arithmetic('-', l, r);
+    }
+
+    public static BigDecimal subtract(BigDecimal l, BigDecimal r) {
+        return (BigDecimal) arithmetic('-', l, r);
+    }
+
+    public static Number subtract(Number l, Number r) {
+        return arithmetic('-', l, r);
+    }
+
+    public static Integer multiply(Integer l, Integer r) {
+        return (Integer) arithmetic('*', l, r);
+    }
+
+    public static Long multiply(Long l, Long r) {
+        return (Long) arithmetic('*', l, r);
+    }
+
+    public static Double multiply(Double l, Double r) {
+        return (Double) arithmetic('*', l, r);
+    }
+
+    public static BigDecimal multiply(BigDecimal l, BigDecimal r) {
+        return (BigDecimal) arithmetic('*', l, r);
+    }
+
+    public static Number multiply(Number l, Number r) {
+        return arithmetic('*', l, r);
+    }
+
+    public static Integer divide(Integer l, Integer r) {
+        return (Integer) arithmetic('/', l, r);
+    }
+
+    public static Long divide(Long l, Long r) {
+        return (Long) arithmetic('/', l, r);
+    }
+
+    public static Double divide(Double l, Double r) {
+        return (Double) arithmetic('/', l, r);
+    }
+
+    public static BigDecimal divide(BigDecimal l, BigDecimal r) {
+        return (BigDecimal) arithmetic('/', l, r);
+    }
+
+    public static Number divide(Number l, Number r) {
+        return arithmetic('/', l, r);
+    }
+
+    public static Integer mod(Integer l, Integer r) {
+        return (Integer) arithmetic('%', l, r);
+    }
+
+    public static Long mod(Long l, Long r) {
+        return (Long) arithmetic('%', l, r);
+    }
+
+    public static Double mod(Double l, Double r) {
+        return (Double) arithmetic('%', l, r);
+    }
+
+    public static BigDecimal mod(BigDecimal l, BigDecimal r) {
+        return (BigDecimal) arithmetic('%', l, r);
+    }
+
+    public static Number mod(Number l, Number r) {
+        return arithmetic('%', l, r);
+    }
+
+    // increment and decrement implement ++ and -- on Decimals, which Java
+    // has no operators for.
+    public static BigDecimal increment(BigDecimal v) {
+        return add(v, BigDecimal.valueOf(1));
+    }
+
+    public static BigDecimal decrement(BigDecimal v) {
+        return add(v, BigDecimal.valueOf(-1));
+    }
+
+    public static Integer negate(Integer v) {
+        return multiply(v, Integer.valueOf(-1));
+    }
+
+    public static Long negate(Long v) {
+        return multiply(v, Long.valueOf(-1));
+    }
+
+    public static Double negate(Double v) {
+        return multiply(v, Double.valueOf(-1));
+    }
+
+    public static BigDecimal negate(BigDecimal v) {
+        return multiply(v, BigDecimal.valueOf(-1));
+    }
+
+    // String concatenation renders null operands as "null", as Apex does.
+    public static String concat(Object l, Object r) {
+        return toString(l) + toString(r);
+    }
+
+    // plus is used when neither operand type is known at conversion time.
+    // Its result takes the type the context expects, such as Integer in
+    // Integer i = ApexOperator.plus(a, b). A number added to an operand that
+    // is not a number throws TypeException. A result of another type throws
+    // ClassCastException where it is used, which converted try statements
+    // translate to TypeException.
+    @SuppressWarnings("unchecked")
+    public static <T> T plus(Object l, Object r) {
+        if (l instanceof String || r instanceof String) {
+            return (T) concat(l, r);
+        }
+        if (l instanceof Number || r instanceof Number) {
+            if (!isNumberOrNull(l) || !isNumberOrNull(r)) {
+                throw new TypeException("Arithmetic expressions must use numeric arguments");
+            }
+            return (T) arithmetic('+', (Number) l, (Number) r);
+        }
+        return (T) concat(l, r);
+    }
+
+    private static boolean isNumberOrNull(Object o) {
+        return o == null || o instanceof Number;
+    }
+
+    static String toString(Object o) {
+        if (o == null) {
+            return "null";
+        }
+        if (o instanceof BigDecimal) {
+            return ((BigDecimal) o).toPlainString();
+        }
+        return o.toString();
+    }
+
+    static Number arithmetic(char op, Number l, Number r) {
+        if (l == null || r == null) {
+            throw new NullPointerException();
+        }
+        int rank = Math.max(rank(l), rank(r));
+        switch (rank) {
+        case 0: {
+            int a = l.intValue();
+            int b = r.intValue();
+            switch (op) {
+            case '+': return a + b;
+            case '-': return a - b;
+            case '*': return a * b;
+            case '/': checkDivisor(b == 0); return a / b;
+            default: checkDivisor(b == 0); return a % b;
+            }
+        }
+        case 1: {
+            long a = l.longValue();
+            long b = r.longValue();
+            switch (op) {
+            case '+': return a + b;
+            case '-': return a - b;
+            case '*': return a * b;
+            case '/': checkDivisor(b == 0); return a / b;
+            default: checkDivisor(b == 0); return a % b;
+            }
+        }
+        case 2: {
+            double a = l.doubleValue();
+            double b = r.doubleValue();
+            switch (op) {
+            case '+': return a + b;
+            case '-': return a - b;
+            case '*': return a * b;
+            case '/': checkDivisor(b == 0); return a / b;
+            default: checkDivisor(b == 0); return a % b;
+            }
+        }
+        default: {
+            BigDecimal a = toDecimal(l);
+            BigDecimal b = toDecimal(r);
+            switch (op) {
+            case '+': return a.add(b);
+            case '-': return a.subtract(b);
+            case '*': return a.multiply(b);
+            case '/': checkDivisor(b.signum() == 0); return a.divide(b, MathContext.DECIMAL128);
+            default: checkDivisor(b.signum() == 0); return a.remainder(b);
+            }
+        }
+        }
+    }
+
+    static int rank(Number n) {
+        if (n instanceof BigDecimal) {
+            return 3;
+        }
+        if (n instanceof Double || n instanceof Float) {
+            return 2;
+        }
+        if (n instanceof Long) {
+            return 1;
+        }
+        return 0;
+    }
+
+    static void checkDivisor(boolean zero) {
+        if (zero) {
+            throw new MathException("Divide by 0");
+        }
+    }
+
+    static BigDecimal toDecimal(Number n) {
+        if (n instanceof BigDecimal) {
+            return (BigDecimal) n;
+        }
+        if (n instanceof Double || n instanceof Float) {
+            return BigDecimal.valueOf(n.doubleValue());
+        }
+        return BigDecimal.valueOf(n.longValue());
+    }
+}
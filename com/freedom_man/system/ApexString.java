@@ -0,0 +1,599 @@
+package com.freedom_man.system;
+
+import java.text.MessageFormat;
+import java.util.regex.Pattern;
+
+// ApexString implements the methods of the Apex String class. Calls on
+// String values are converted to calls of the static method of the same
+// name with the receiver as the first argument, e.g. s.left(3) becomes
+// ApexString.left(s, 3), which throw NullPointerException on a null
+// receiver as Apex does. The static Apex String methods keep their
+// arguments.
+public class ApexString {
+    private static String check(String s) {
+        if (s == null) {
+            throw new NullPointerException();
+        }
+        return s;
+    }
+
+    // Static methods
+
+    public static Boolean isBlank(String s) {
+        return s == null || s.trim().isEmpty();
+    }
+
+    public static Boolean isNotBlank(String s) {
+        return !isBlank(s);
+    }
+
+    public static Boolean isEmpty(String s) {
+        return s == null || s.isEmpty();
+    }
+
+    public static Boolean isNotEmpty(String s) {
+        return !isEmpty(s);
+    }
+
+    public static String escapeSingleQuotes(String s) {
+        return check(s).replace("'", "\\'");
+    }
+
+    // format replaces {0}, {1}, ... in pattern with the arguments, using
+    // java.text.MessageFormat like Apex does.
+    public static String format(String pattern, java.util.List<?> arguments) {
+        return MessageFormat.format(check(pattern), arguments.toArray());
+    }
+
+    public static String fromCharArray(java.util.List<Integer> chars) {
+        StringBuilder sb = new StringBuilder();
+        for (Integer c : chars) {
+            sb.appendCodePoint(c);
+        }
+        return sb.toString();
+    }
+
+    // join joins the elements of an iterable with separator, writing null
+    // elements as empty strings.
+    public static String join(Iterable<?> values, String separator) {
+        StringBuilder sb = new StringBuilder();
+        boolean first = true;
+        for (Object value : values) {
+            if (!first && separator != null) {
+                sb.append(separator);
+            }
+            first = false;
+            if (value != null) {
+                sb.append(ApexOperator.toString(value));
+            }
+        }
+        return sb.toString();
+    }
+
+    public static String valueOf(Object value) {
+        return ApexOperator.toString(value);
+    }
+
+    // valueOf returns a Datetime in the time zone of the running user as
+    // yyyy-MM-dd HH:mm:ss, unlike its toString, which is in GMT.
+    public static String valueOf(Datetime value) {
+        return value == null ? "null" : value.format("yyyy-MM-dd HH:mm:ss");
+    }
+
+    public static String valueOfGmt(Datetime value) {
+        return value == null ? "null" : value.formatGmt("yyyy-MM-dd HH:mm:ss");
+    }
+
+    // Instance methods
+
+    public static String abbreviate(String s, Integer maxWidth) {
+        return abbreviate(s, maxWidth, 0);
+    }
+
+    public static String abbreviate(String s, Integer maxWidth, Integer offset) {
+        check(s);
+        if (s.length() <= maxWidth) {
+            return s;
+        }
+        if (maxWidth < 4) {
+            throw new StringException("Minimum abbreviation width is 4");
+        }
+        int start = Math.max(0, Math.min(offset, s.length()));
+        if (start <= 4) {
+            return s.substring(0, maxWidth - 3) + "...";
+        }
+        if (maxWidth < 7) {
+            throw new StringException("Minimum abbreviation width with offset is 7");
+        }
+        if (start + maxWidth - 3 < s.length()) {
+            return "..." + abbreviate(s.substring(start), maxWidth - 3, 0);
+        }
+        return "..." + s.substring(s.length() - (maxWidth - 3));
+    }
+
+    public static String capitalize(String s) {
+        check(s);
+        if (s.isEmpty()) {
+            return s;
+        }
+        int first = s.codePointAt(0);
+        return new StringBuilder().appendCodePoint(Character.toTitleCase(first)).append(s.substring(Character.charCount(first))).toString();
+    }
+
+    public static String center(String s, Integer size) {
+        return center(s, size, " ");
+    }
+
+    public static String center(String s, Integer size, String padding) {
+        check(s);
+        int pads = size - s.length();
+        if (pads <= 0) {
+            return s;
+        }
+        return rightPad(leftPad(s, s.length() + pads / 2, padding), size, padding);
+    }
+
+    public static Integer charAt(String s, Integer index) {
+        check(s);
+        if (index < 0 || index >= s.length()) {
+            throw new StringException("Char index out of bounds: " + index);
+        }
+        return (int) s.charAt(index);
+    }
+
+    public static Integer compareTo(String s, String other) {
+        return check(s).compareTo(check(other));
+    }
+
+    public static Boolean contains(String s, String substring) {
+        return check(s).contains(check(substring));
+    }
+
+    public static Boolean containsAny(String s, String chars) {
+        check(s);
+        for (char c : check(chars).toCharArray()) {
+            if (s.indexOf(c) >= 0) {
+                return true;
+            }
+        }
+        return false;
+    }
+
+    public static Boolean containsIgnoreCase(String s, String substring) {
+        return check(s).toLowerCase().contains(check(substring).toLowerCase());
+    }
+
+    public static Boolean containsNone(String s, String chars) {
+        return !containsAny(s, chars);
+    }
+
+    public static Boolean containsOnly(String s, String chars) {
+        check(chars);
+        for (char c : check(s).toCharArray()) {
+            if (chars.indexOf(c) < 0) {
+                return false;
+            }
+        }
+        return true;
+    }
+
+    public static Boolean containsWhitespace(String s) {
+        for (char c : check(s).toCharArray()) {
+            if (Character.isWhitespace(c)) {
+                return true;
+            }
+        }
+        return false;
+    }
+
+    public static Integer countMatches(String s, String substring) {
+        check(s);
+        if (substring == null || substring.isEmpty()) {
+            return 0;
+        }
+        int count = 0;
+        for (int i = s.indexOf(substring); i >= 0; i = s.indexOf(substring, i + substring.length())) {
+            count++;
+        }
+        return count;
+    }
+
+    public static String deleteWhitespace(String s) {
+        StringBuilder sb = new StringBuilder();
+        for (char c : check(s).toCharArray()) {
+            if (!Character.isWhitespace(c)) {
+                sb.append(c);
+            }
+        }
+        return sb.toString();
+    }
+
+    // difference returns the part of other from where it starts to differ
+    // from s.
+    public static String difference(String s, String other) {
+        check(s);
+        if (other == null) {
+            return "";
+        }
+        int i = 0;
+        while (i < s.length() && i < other.length() && s.charAt(i) == other.charAt(i)) {
+            i++;
+        }
+        return other.substring(i);
+    }
+
+    public static Boolean endsWith(String s, String suffix) {
+        return check(s).endsWith(check(suffix));
+    }
+
+    public static Boolean endsWithIgnoreCase(String s, String suffix) {
+        return check(s).toLowerCase().endsWith(check(suffix).toLowerCase());
+    }
+
+    // equals compares case-sensitively, unlike the == operator.
+    public static Boolean equals(String s, Object other) {
+        return check(s).equals(other);
+    }
+
+    public static Boolean equalsIgnoreCase(String s, String other) {
+        return check(s).equalsIgnoreCase(other);
+    }
+
+    public static List<Integer> getChars(String s) {
+        List<Integer> chars = new List<Integer>();
+        for (char c : check(s).toCharArray()) {
+            chars.add((int) c);
+        }
+        return chars;
+    }
+
+    public static Integer hashCode(String s) {
+        return check(s).hashCode();
+    }
+
+    public static Integer indexOf(String s, String substring) {
+        return check(s).indexOf(check(substring));
+    }
+
+    public static Integer indexOf(String s, String substring, Integer from) {
+        return check(s).indexOf(check(substring), from);
+    }
+
+    public static Integer indexOfIgnoreCase(String s, String substring) {
+        return check(s).toLowerCase().indexOf(check(substring).toLowerCase());
+    }
+
+    public static Integer indexOfIgnoreCase(String s, String substring, Integer from) {
+        return check(s).toLowerCase().indexOf(check(substring).toLowerCase(), from);
+    }
+
+    public static Boolean isAllLowerCase(String s) {
+        return !check(s).isEmpty() && s.chars().allMatch(Character::isLowerCase);
+    }
+
+    public static Boolean isAllUpperCase(String s) {
+        return !check(s).isEmpty() && s.chars().allMatch(Character::isUpperCase);
+    }
+
+    public static Boolean isAlpha(String s) {
+        return !check(s).isEmpty() && s.chars().allMatch(Character::isLetter);
+    }
+
+    public static Boolean isAlphaSpace(String s) {
+        return check(s).chars().allMatch(c -> Character.isLetter(c) || c == ' ');
+    }
+
+    public static Boolean isAlphanumeric(String s) {
+        return !check(s).isEmpty() && s.chars().allMatch(Character::isLetterOrDigit);
+    }
+
+    public static Boolean isAlphanumericSpace(String s) {
+        return check(s).chars().allMatch(c -> Character.isLetterOrDigit(c) || c == ' ');
+    }
+
+    public static Boolean isNumeric(String s) {
+        return !check(s).isEmpty() && s.chars().allMatch(Character::isDigit);
+    }
+
+    public static Boolean isNumericSpace(String s) {
+        return check(s).chars().allMatch(c -> Character.isDigit(c) || c == ' ');
+    }
+
+    public static Boolean isWhitespace(String s) {
+        return check(s).chars().allMatch(Character::isWhitespace);
+    }
+
+    public static Integer lastIndexOf(String s, String substring) {
+        return check(s).lastIndexOf(check(substring));
+    }
+
+    public static Integer lastIndexOf(String s, String substring, Integer end) {
+        return check(s).lastIndexOf(check(substring), end);
+    }
+
+    public static Integer lastIndexOfIgnoreCase(String s, String substring) {
+        return check(s).toLowerCase().lastIndexOf(check(substring).toLowerCase());
+    }
+
+    public static String left(String s, Integer length) {
+        check(s);
+        if (length < 0) {
+            return "";
+        }
+        return s.substring(0, Math.min(length, s.length()));
+    }
+
+    public static String leftPad(String s, Integer length) {
+        return leftPad(s, length, " ");
+    }
+
+    public static String leftPad(String s, Integer length, String padding) {
+        check(s);
+        if (padding == null || padding.isEmpty()) {
+            padding = " ";
+        }
+        StringBuilder pad = new StringBuilder();
+        while (pad.length() < length - s.length()) {
+            pad.append(padding.charAt(pad.length() % padding.length()));
+        }
+        return pad + s;
+    }
+
+    public static Integer length(String s) {
+        return check(s).length();
+    }
+
+    // mid returns length characters from start, clamping both to the
+    // string.
+    public static String mid(String s, Integer start, Integer length) {
+        check(s);
+        if (length < 0 || start > s.length()) {
+            return "";
+        }
+        int from = Math.max(0, start);
+        return s.substring(from, Math.min(s.length(), from + length));
+    }
+
+    public static String normalizeSpace(String s) {
+        return check(s).trim().replaceAll("\\s+", " ");
+    }
+
+    public static String remove(String s, String substring) {
+        check(s);
+        return substring == null || substring.isEmpty() ? s : s.replace(substring, "");
+    }
+
+    public static String removeEnd(String s, String suffix) {
+        return suffix != null && check(s).endsWith(suffix) ? s.substring(0, s.length() - suffix.length()) : check(s);
+    }
+
+    public static String removeEndIgnoreCase(String s, String suffix) {
+        return suffix != null && endsWithIgnoreCase(s, suffix) ? s.substring(0, s.length() - suffix.length()) : s;
+    }
+
+    public static String removeStart(String s, String prefix) {
+        return prefix != null && check(s).startsWith(prefix) ? s.substring(prefix.length()) : check(s);
+    }
+
+    public static String removeStartIgnoreCase(String s, String prefix) {
+        return prefix != null && startsWithIgnoreCase(s, prefix) ? s.substring(prefix.length()) : s;
+    }
+
+    public static String repeat(String s, Integer times) {
+        return repeat(s, "", times);
+    }
+
+    public static String repeat(String s, String separator, Integer times) {
+        check(s);
+        StringBuilder sb = new StringBuilder();
+        for (int i = 0; i < times; i++) {
+            if (i != 0) {
+                sb.append(separator);
+            }
+            sb.append(s);
+        }
+        return sb.toString();
+    }
+
+    public static String replace(String s, String target, String replacement) {
+        return check(s).replace(check(target), check(replacement));
+    }
+
+    public static String replaceAll(String s, String regex, String replacement) {
+        return check(s).replaceAll(check(regex), check(replacement));
+    }
+
+    public static String replaceFirst(String s, String regex, String replacement) {
+        return check(s).replaceFirst(check(regex), check(replacement));
+    }
+
+    public static String reverse(String s) {
+        return new StringBuilder(check(s)).reverse().toString();
+    }
+
+    public static String right(String s, Integer length) {
+        check(s);
+        if (length < 0) {
+            return "";
+        }
+        return s.substring(s.length() - Math.min(length, s.length()));
+    }
+
+    public static String rightPad(String s, Integer length) {
+        return rightPad(s, length, " ");
+    }
+
+    public static String rightPad(String s, Integer length, String padding) {
+        check(s);
+        if (padding == null || padding.isEmpty()) {
+            padding = " ";
+        }
+        StringBuilder sb = new StringBuilder(s);
+        for (int i = 0; sb.length() < length; i++) {
+            sb.append(padding.charAt(i % padding.length()));
+        }
+        return sb.toString();
+    }
+
+    public static List<String> split(String s, String regex) {
+        return split(s, regex, 0);
+    }
+
+    public static List<String> split(String s, String regex, Integer limit) {
+        List<String> parts = new List<String>();
+        for (String part : check(s).split(check(regex), limit)) {
+            parts.add(part);
+        }
+        return parts;
+    }
+
+    // splitByCharacterType splits where the type of character changes:
+    // lower case, upper case, digits, white space and the others.
+    public static List<String> splitByCharacterType(String s) {
+        return splitByCharacterType(check(s), false);
+    }
+
+    // splitByCharacterTypeCamelCase keeps an upper case letter with the
+    // lower case letters following it, as in "fooBar" -> "foo", "Bar".
+    public static List<String> splitByCharacterTypeCamelCase(String s) {
+        return splitByCharacterType(check(s), true);
+    }
+
+    private static List<String> splitByCharacterType(String s, boolean camelCase) {
+        List<String> parts = new List<String>();
+        if (s.isEmpty()) {
+            return parts;
+        }
+        int start = 0;
+        int type = Character.getType(s.charAt(0));
+        for (int i = 1; i < s.length(); i++) {
+            int t = Character.getType(s.charAt(i));
+            if (t == type) {
+                continue;
+            }
+            if (camelCase && t == Character.LOWERCASE_LETTER && type == Character.UPPERCASE_LETTER) {
+                if (i - 1 != start) {
+                    parts.add(s.substring(start, i - 1));
+                    start = i - 1;
+                }
+            } else {
+                parts.add(s.substring(start, i));
+                start = i;
+            }
+            type = t;
+        }
+        parts.add(s.substring(start));
+        return parts;
+    }
+
+    public static Boolean startsWith(String s, String prefix) {
+        return check(s).startsWith(check(prefix));
+    }
+
+    public static Boolean startsWithIgnoreCase(String s, String prefix) {
+        return check(s).toLowerCase().startsWith(check(prefix).toLowerCase());
+    }
+
+    private static final Pattern HTML_TAG = Pattern.compile("<[^>]*>");
+
+    public static String stripHtmlTags(String s) {
+        return HTML_TAG.matcher(check(s)).replaceAll("").trim();
+    }
+
+    public static String substring(String s, Integer start) {
+        return check(s).substring(start);
+    }
+
+    public static String substring(String s, Integer start, Integer end) {
+        return check(s).substring(start, end);
+    }
+
+    public static String substringAfter(String s, String separator) {
+        check(s);
+        if (separator == null) {
+            return "";
+        }
+        int i = s.indexOf(separator);
+        return i < 0 ? "" : s.substring(i + separator.length());
+    }
+
+    public static String substringAfterLast(String s, String separator) {
+        check(s);
+        if (separator == null || separator.isEmpty()) {
+            return "";
+        }
+        int i = s.lastIndexOf(separator);
+        return i < 0 || i == s.length() - separator.length() ? "" : s.substring(i + separator.length());
+    }
+
+    public static String substringBefore(String s, String separator) {
+        check(s);
+        if (separator == null) {
+            return s;
+        }
+        int i = s.indexOf(separator);
+        return i < 0 ? s : s.substring(0, i);
+    }
+
+    public static String substringBeforeLast(String s, String separator) {
+        check(s);
+        if (separator == null || separator.isEmpty()) {
+            return s;
+        }
+        int i = s.lastIndexOf(separator);
+        return i < 0 ? s : s.substring(0, i);
+    }
+
+    public static String substringBetween(String s, String tag) {
+        return substringBetween(s, tag, tag);
+    }
+
+    // substringBetween returns the text between the first open and the
+    // close following it, or null if there is none.
+    public static String substringBetween(String s, String open, String close) {
+        check(s);
+        if (open == null || close == null) {
+            return null;
+        }
+        int start = s.indexOf(open);
+        if (start < 0) {
+            return null;
+        }
+        int end = s.indexOf(close, start + open.length());
+        return end < 0 ? null : s.substring(start + open.length(), end);
+    }
+
+    public static String swapCase(String s) {
+        StringBuilder sb = new StringBuilder();
+        for (char c : check(s).toCharArray()) {
+            if (Character.isUpperCase(c)) {
+                sb.append(Character.toLowerCase(c));
+            } else if (Character.isLowerCase(c)) {
+                sb.append(Character.toUpperCase(c));
+            } else {
+                sb.append(c);
+            }
+        }
+        return sb.toString();
+    }
+
+    public static String toLowerCase(String s) {
+        return check(s).toLowerCase();
+    }
+
+    public static String toUpperCase(String s) {
+        return check(s).toUpperCase();
+    }
+
+    public static String trim(String s) {
+        return check(s).trim();
+    }
+
+    public static String uncapitalize(String s) {
+        check(s);
+        if (s.isEmpty()) {
+            return s;
+        }
+        return Character.toLowerCase(s.charAt(0)) + s.substring(1);
+    }
+}
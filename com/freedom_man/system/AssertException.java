@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class AssertException extends Exception {
+    public AssertException() {
+        super();
+    }
+
+    public AssertException(String message) {
+        super(message);
+    }
+
+    public AssertException(Exception cause) {
+        super(cause);
+    }
+
+    public AssertException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
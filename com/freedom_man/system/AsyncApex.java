@@ -0,0 +1,115 @@
+package com.freedom_man.system;
+
+import java.util.ArrayDeque;
+import java.util.Deque;
+import java.util.Iterator;
+import java.util.function.Consumer;
+
+// AsyncApex runs asynchronous Apex in process, one job at a time in the
+// order the jobs were queued. Future methods, queueable, batch and
+// scheduled jobs are queued with an AsyncApexJob record and run by run,
+// which Test.stopTest calls, as Apex runs the jobs a test starts when it
+// stops testing. Code outside tests calls run itself.
+public class AsyncApex {
+    private static class Job {
+        final AsyncApexJob record;
+        final Consumer<AsyncApexJob> body;
+
+        Job(AsyncApexJob record, Consumer<AsyncApexJob> body) {
+            this.record = record;
+            this.body = body;
+        }
+    }
+
+    private static final Deque<Job> queue = new ArrayDeque<Job>();
+    private static boolean running;
+
+    // future queues the body of a @future method. Converted future methods
+    // call it with a lambda calling the original body.
+    public static void future(String methodName, Runnable body) {
+        if (System.future || System.batch) {
+            throw new AsyncException("Future method cannot be called from a future or batch method: " + methodName);
+        }
+        Limits.futureCall();
+        enqueue("Future", methodName, record -> {
+            boolean previous = System.future;
+            System.future = true;
+            try {
+                body.run();
+            } finally {
+                System.future = previous;
+            }
+        });
+    }
+
+    // enqueue queues a job and returns the Id of its AsyncApexJob record.
+    static String enqueue(String jobType, String methodName, Consumer<AsyncApexJob> body) {
+        AsyncApexJob record = new AsyncApexJob();
+        record.JobType = jobType;
+        record.MethodName = methodName;
+        record.Status = "Queued";
+        record.JobItemsProcessed = 0;
+        record.TotalJobItems = 0;
+        record.NumberOfErrors = 0;
+        DataStore.insert(record);
+        queue.add(new Job(record, body));
+        return record.Id;
+    }
+
+    // run runs the queued jobs, and the jobs they queue, until none are
+    // left. An exception thrown by a job fails it and is rethrown.
+    public static void run() {
+        if (running) {
+            return;
+        }
+        running = true;
+        try {
+            while (!queue.isEmpty()) {
+                execute(queue.poll());
+            }
+        } finally {
+            running = false;
+        }
+    }
+
+    // execute runs a job in its own transaction, with the asynchronous
+    // limits.
+    private static void execute(Job job) {
+        AsyncApexJob record = job.record;
+        record.Status = "Processing";
+        DataStore.update(record);
+        Limits.begin(true);
+        try {
+            job.body.accept(record);
+            record.Status = "Completed";
+        } catch (RuntimeException e) {
+            record.Status = "Failed";
+            record.NumberOfErrors++;
+            record.ExtendedStatus = e.getMessage();
+            throw e;
+        } finally {
+            Limits.end();
+            record.CompletedDate = Datetime.now();
+            DataStore.update(record);
+        }
+    }
+
+    // abort removes a queued job, reporting whether there was one.
+    static boolean abort(String jobId) {
+        for (Iterator<Job> it = queue.iterator(); it.hasNext(); ) {
+            Job job = it.next();
+            if (job.record.Id.equals(jobId)) {
+                it.remove();
+                job.record.Status = "Aborted";
+                DataStore.update(job.record);
+                return true;
+            }
+        }
+        return false;
+    }
+
+    static void reset() {
+        queue.clear();
+        running = false;
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class AsyncException extends Exception {
+    public AsyncException() {
+        super();
+    }
+
+    public AsyncException(String message) {
+        super(message);
+    }
+
+    public AsyncException(Exception cause) {
+        super(cause);
+    }
+
+    public AsyncException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,15 @@
+package com.freedom_man.system;
+
+import java.lang.annotation.ElementType;
+import java.lang.annotation.Retention;
+import java.lang.annotation.RetentionPolicy;
+import java.lang.annotation.Target;
+
+// AuraEnabled exposes a method or property to Lightning components.
+@Retention(RetentionPolicy.RUNTIME)
+@Target({ElementType.METHOD, ElementType.FIELD})
+public @interface AuraEnabled {
+    boolean cacheable() default false;
+
+    boolean continuation() default false;
+}
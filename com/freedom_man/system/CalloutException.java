@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class CalloutException extends Exception {
+    public CalloutException() {
+        super();
+    }
+
+    public CalloutException(String message) {
+        super(message);
+    }
+
+    public CalloutException(Exception cause) {
+        super(cause);
+    }
+
+    public CalloutException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
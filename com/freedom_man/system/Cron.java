@@ -0,0 +1,196 @@
+package com.freedom_man.system;
+
+import java.time.DayOfWeek;
+import java.time.LocalDate;
+import java.time.LocalTime;
+import java.time.ZonedDateTime;
+import java.util.BitSet;
+
+// Cron is an Apex cron expression: seconds, minutes, hours, day of month,
+// month, day of week and an optional year. Either the day of month or the
+// day of week is ?. Besides lists, ranges and increments, the day of month
+// takes L, LW and nW, and the day of week nL and n#k, as in Apex.
+class Cron {
+    private static final String[] MONTHS = {"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"};
+    private static final String[] WEEKDAYS = {"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"};
+
+    private final BitSet seconds;
+    private final BitSet minutes;
+    private final BitSet hours;
+    private final BitSet months;
+    private final BitSet years;
+
+    private BitSet days;
+    private boolean lastDay;
+    private boolean lastWeekdayOfMonth;
+    private int nearestWeekday;
+
+    private BitSet weekdays;
+    private int lastWeekday;
+    private int nthWeekday;
+    private int nth;
+
+    Cron(String expression) {
+        String[] fields = expression == null ? new String[0] : expression.trim().toUpperCase().split("\\s+");
+        if (fields.length < 6 || fields.length > 7) {
+            throw new StringException("Unexpected end of expression.");
+        }
+        if (fields[3].equals("?") == fields[5].equals("?")) {
+            throw new StringException("Support for specifying both a day-of-week AND a day-of-month parameter is not implemented.");
+        }
+        seconds = range(fields[0], 0, 59, null);
+        minutes = range(fields[1], 0, 59, null);
+        hours = range(fields[2], 0, 23, null);
+        dayOfMonth(fields[3]);
+        months = range(fields[4], 1, 12, MONTHS);
+        dayOfWeek(fields[5]);
+        years = range(fields.length == 7 ? fields[6] : "*", 1970, 2099, null);
+    }
+
+    private void dayOfMonth(String field) {
+        if (field.equals("L")) {
+            lastDay = true;
+        } else if (field.equals("LW")) {
+            lastWeekdayOfMonth = true;
+        } else if (field.endsWith("W")) {
+            nearestWeekday = number(field.substring(0, field.length() - 1), 1, 31, null);
+        } else {
+            days = range(field, 1, 31, null);
+        }
+    }
+
+    private void dayOfWeek(String field) {
+        int hash = field.indexOf('#');
+        if (hash >= 0) {
+            nthWeekday = number(field.substring(0, hash), 1, 7, WEEKDAYS);
+            nth = number(field.substring(hash + 1), 1, 5, null);
+        } else if (field.equals("L")) {
+            weekdays = range("7", 1, 7, WEEKDAYS);
+        } else if (field.endsWith("L")) {
+            lastWeekday = number(field.substring(0, field.length() - 1), 1, 7, WEEKDAYS);
+        } else {
+            weekdays = range(field, 1, 7, WEEKDAYS);
+        }
+    }
+
+    // range reads a field of values, ranges and increments separated by
+    // commas. * and ? are every value.
+    private static BitSet range(String field, int min, int max, String[] names) {
+        BitSet set = new BitSet();
+        for (String part : field.split(",")) {
+            int step = 1;
+            int slash = part.indexOf('/');
+            if (slash >= 0) {
+                step = number(part.substring(slash + 1), 1, max, null);
+                part = part.substring(0, slash);
+            }
+            int from;
+            int to;
+            int dash = part.indexOf('-');
+            if (part.equals("*") || part.equals("?")) {
+                from = min;
+                to = max;
+            } else if (dash > 0) {
+                from = number(part.substring(0, dash), min, max, names);
+                to = number(part.substring(dash + 1), min, max, names);
+            } else {
+                from = number(part, min, max, names);
+                to = slash >= 0 ? max : from;
+            }
+            for (int i = from; i <= to; i += step) {
+                set.set(i);
+            }
+        }
+        return set;
+    }
+
+    private static int number(String s, int min, int max, String[] names) {
+        if (names != null) {
+            for (int i = 0; i < names.length; i++) {
+                if (names[i].equals(s)) {
+                    return min + i;
+                }
+            }
+        }
+        try {
+            int n = Integer.parseInt(s);
+            if (n >= min && n <= max) {
+                return n;
+            }
+        } catch (NumberFormatException e) {
+            // reported below
+        }
+        throw new StringException("Invalid cron expression value: " + s);
+    }
+
+    // next returns the first time after the given one the expression
+    // matches in the time zone of the running user, or null if there is
+    // none.
+    Datetime next(Datetime after) {
+        ZonedDateTime start = after.value.atZone(Datetime.userZone()).plusSeconds(1).withNano(0);
+        for (LocalDate day = start.toLocalDate(); day.getYear() <= 2099; day = day.plusDays(1)) {
+            if (!matches(day)) {
+                continue;
+            }
+            LocalTime time = firstTime(day.equals(start.toLocalDate()) ? start.toLocalTime() : LocalTime.MIDNIGHT);
+            if (time != null) {
+                return new Datetime(ZonedDateTime.of(day, time, start.getZone()).toInstant());
+            }
+        }
+        return null;
+    }
+
+    private LocalTime firstTime(LocalTime from) {
+        for (int h = hours.nextSetBit(from.getHour()); h >= 0; h = hours.nextSetBit(h + 1)) {
+            boolean sameHour = h == from.getHour();
+            for (int m = minutes.nextSetBit(sameHour ? from.getMinute() : 0); m >= 0; m = minutes.nextSetBit(m + 1)) {
+                int s = seconds.nextSetBit(sameHour && m == from.getMinute() ? from.getSecond() : 0);
+                if (s >= 0) {
+                    return LocalTime.of(h, m, s);
+                }
+            }
+        }
+        return null;
+    }
+
+    private boolean matches(LocalDate day) {
+        return years.get(day.getYear()) && months.get(day.getMonthValue()) && matchesDayOfMonth(day) && matchesDayOfWeek(day);
+    }
+
+    private boolean matchesDayOfMonth(LocalDate day) {
+        int length = day.lengthOfMonth();
+        if (lastDay) {
+            return day.getDayOfMonth() == length;
+        }
+        if (lastWeekdayOfMonth) {
+            return day.equals(weekdayNear(day.withDayOfMonth(length)));
+        }
+        if (nearestWeekday > 0) {
+            return day.equals(weekdayNear(day.withDayOfMonth(Math.min(nearestWeekday, length))));
+        }
+        return days == null || days.get(day.getDayOfMonth());
+    }
+
+    // weekdayNear returns the weekday nearest to day in the same month.
+    private static LocalDate weekdayNear(LocalDate day) {
+        if (day.getDayOfWeek() == DayOfWeek.SATURDAY) {
+            return day.getDayOfMonth() == 1 ? day.plusDays(2) : day.minusDays(1);
+        }
+        if (day.getDayOfWeek() == DayOfWeek.SUNDAY) {
+            return day.getDayOfMonth() == day.lengthOfMonth() ? day.minusDays(2) : day.plusDays(1);
+        }
+        return day;
+    }
+
+    private boolean matchesDayOfWeek(LocalDate day) {
+        // 1 is Sunday in Apex
+        int weekday = day.getDayOfWeek().getValue() % 7 + 1;
+        if (nthWeekday > 0) {
+            return weekday == nthWeekday && (day.getDayOfMonth() - 1) / 7 + 1 == nth;
+        }
+        if (lastWeekday > 0) {
+            return weekday == lastWeekday && day.getDayOfMonth() + 7 > day.lengthOfMonth();
+        }
+        return weekdays == null || weekdays.get(weekday);
+    }
+}
@@ -0,0 +1,144 @@
+package com.freedom_man.system;
+
+import java.util.LinkedHashMap;
+import java.util.Map;
+
+// DataStore holds the records written by DML, standing in for the org's
+// database. Tests start from an empty store, see Test.reset.
+public class DataStore {
+    private static final Map<String, Map<String, SObject>> records = new LinkedHashMap<String, Map<String, SObject>>();
+    private static final Map<String, SObject> deleted = new LinkedHashMap<String, SObject>();
+    private static int sequence;
+
+    public static void insert(SObject record) {
+        if (record.Id != null) {
+            throw new DmlException("Insert failed. First exception on row 0 with id " + record.Id + "; first error: INVALID_FIELD_FOR_INSERT_UPDATE, cannot specify Id in an insert call: [Id]");
+        }
+        record.Id = newId(record);
+        record.CreatedDate = Datetime.now();
+        record.LastModifiedDate = record.CreatedDate;
+        record.markSaved();
+        table(record).put(record.Id, record.copy());
+    }
+
+    // update sets the fields the code set on the record, to null as well, on
+    // the stored one, so the fields the record was queried or built without
+    // keep their values. Related records are not updated, as in Apex.
+    public static void update(SObject record) {
+        if (record.Id == null) {
+            throw new DmlException("Update failed. First exception on row 0; first error: MISSING_ARGUMENT, Id not specified in an update call: []");
+        }
+        SObject stored = table(record).get(record.Id);
+        if (stored == null) {
+            throw new DmlException("Update failed. First exception on row 0 with id " + record.Id + "; first error: ENTITY_IS_DELETED, entity is deleted: []");
+        }
+        record.LastModifiedDate = Datetime.now();
+        for (java.util.Map.Entry<String, Object> e : record.changedFields().entrySet()) {
+            if (!(e.getValue() instanceof SObject) && !(e.getValue() instanceof java.util.List)) {
+                stored.put(e.getKey(), e.getValue());
+            }
+        }
+        record.markSaved();
+    }
+
+    public static void upsert(SObject record) {
+        if (record.Id == null) {
+            insert(record);
+        } else {
+            update(record);
+        }
+    }
+
+    // upsert matches the record against the stored ones by the value of
+    // externalIdField instead of its Id.
+    public static void upsert(SObject record, String externalIdField) {
+        Object key = field(record, externalIdField);
+        if (key != null) {
+            for (SObject stored : table(record).values()) {
+                if (ApexOperator.equals(key, field(stored, externalIdField))) {
+                    record.Id = stored.Id;
+                    update(record);
+                    return;
+                }
+            }
+        }
+        record.Id = null;
+        insert(record);
+    }
+
+    public static void delete(SObject record) {
+        if (record.Id == null || table(record).remove(record.Id) == null) {
+            throw new DmlException("Delete failed. First exception on row 0 with id " + record.Id + "; first error: ENTITY_IS_DELETED, entity is deleted: []");
+        }
+        deleted.put(record.Id, record.copy());
+    }
+
+    public static void undelete(SObject record) {
+        SObject restored = record.Id == null ? null : deleted.remove(record.Id);
+        if (restored == null) {
+            throw new DmlException("Undelete failed. First exception on row 0 with id " + record.Id + "; first error: UNDELETE_FAILED, Entity is not in the recycle bin: []");
+        }
+        table(restored).put(restored.Id, restored);
+    }
+
+    // records returns copies of the stored records of the SObject type.
+    public static java.util.List<SObject> records(String type) {
+        java.util.List<SObject> result = new java.util.ArrayList<SObject>();
+        Map<String, SObject> table = records.get(type.toLowerCase());
+        if (table != null) {
+            for (SObject record : table.values()) {
+                SObject copy = record.copy();
+                copy.markSaved();
+                result.add(copy);
+            }
+        }
+        return result;
+    }
+
+    // readableRecords returns copies of the stored records of the SObject
+    // type the running code can read. Code running with sharing as the user
+    // of System.runAs reads the records that user owns and the ones without
+    // an owner; otherwise all records are read.
+    static java.util.List<SObject> readableRecords(String type) {
+        java.util.List<SObject> result = records(type);
+        User user = System.runningUser();
+        if (user == null || Sharing.Mode.running() != Sharing.Mode.WITH) {
+            return result;
+        }
+        result.removeIf(record -> {
+            Object owner = record.getField("OwnerId");
+            return owner != null && !ApexOperator.equalsId((String) owner, user.Id);
+        });
+        return result;
+    }
+
+    public static void reset() {
+        records.clear();
+        deleted.clear();
+        sequence = 0;
+    }
+
+    static Object field(SObject record, String name) {
+        return record.value(name);
+    }
+
+    static String typeName(SObject record) {
+        return record.getClass().getSimpleName();
+    }
+
+    private static Map<String, SObject> table(SObject record) {
+        String type = typeName(record).toLowerCase();
+        Map<String, SObject> table = records.get(type);
+        if (table == null) {
+            table = new LinkedHashMap<String, SObject>();
+            records.put(type, table);
+        }
+        return table;
+    }
+
+    // newId returns an 18 character Id starting with the key prefix of the
+    // record's SObject type.
+    private static String newId(SObject record) {
+        return String.format("%s%015d", record.getSObjectType().getDescribe().getKeyPrefix(), ++sequence);
+    }
+}
@@ -1,7 +1,232 @@
 package com.freedom_man.system;
 
 public class Database {
-    public static List<SObject> query(String s) {
-        return new List<SObject>();
+    // Batchable is Database.Batchable. start returns a QueryLocator or an
+    // Iterable of the records the batch job processes.
+    public interface Batchable<T> {
+        Object start(BatchableContext context);
+
+        void execute(BatchableContext context, List<T> scope);
+
+        void finish(BatchableContext context);
+    }
+
+    public interface BatchableContext {
+        String getJobId();
+
+        String getChildJobId();
+    }
+
+    // Stateful marks batch jobs whose member variables keep their values
+    // between chunks. Batch jobs run on a single instance, so all of them
+    // do.
+    public interface Stateful {
+    }
+
+    public interface AllowsCallouts {
+    }
+
+    public interface RaisesPlatformEvents {
+    }
+
+    // QueryLocator holds the records of a query for a batch job.
+    public static class QueryLocator implements Iterable<SObject> {
+        private final String query;
+        private final java.util.List<SObject> records;
+
+        QueryLocator(String query, java.util.List<SObject> records) {
+            this.query = query;
+            this.records = records;
+        }
+
+        public String getQuery() {
+            return query;
+        }
+
+        @Override
+        public java.util.Iterator<SObject> iterator() {
+            return records.iterator();
+        }
+    }
+
+    // getQueryLocator takes the result of an inline query, which is
+    // converted to a call of query.
+    public static QueryLocator getQueryLocator(java.util.List<? extends SObject> records) {
+        return new QueryLocator(null, new java.util.ArrayList<SObject>(records));
+    }
+
+    public static QueryLocator getQueryLocator(String query) {
+        java.util.List<SObject> records = Soql.execute(query);
+        Limits.queryLocator(records.size());
+        return new QueryLocator(query, records);
+    }
+
+    public static String executeBatch(Batchable<?> batch) {
+        return executeBatch(batch, 200);
+    }
+
+    // executeBatch queues the batch job, which passes the records from
+    // start to execute in chunks of scope records. A chunk whose execute
+    // throws counts as an error of the job, which goes on with the next
+    // chunk, as in Apex.
+    public static <T> String executeBatch(Batchable<T> batch, Integer scope) {
+        if (scope == null || scope < 1) {
+            throw new AsyncException("The batch scope must be greater than 0");
+        }
+        return AsyncApex.enqueue("BatchApex", null, job -> runBatch(batch, scope, job));
+    }
+
+    @SuppressWarnings("unchecked")
+    private static <T> void runBatch(Batchable<T> batch, int scope, AsyncApexJob job) {
+        boolean previous = System.batch;
+        System.batch = true;
+        try {
+            BatchableContext context = new BatchableContext() {
+                @Override
+                public String getJobId() {
+                    return job.Id;
+                }
+
+                @Override
+                public String getChildJobId() {
+                    return null;
+                }
+            };
+            java.util.List<T> records = new java.util.ArrayList<T>();
+            for (Object record : (Iterable<?>) batch.start(context)) {
+                records.add((T) record);
+            }
+            job.TotalJobItems = (records.size() + scope - 1) / scope;
+            for (int i = 0; i < records.size(); i += scope) {
+                List<T> chunk = new List<T>();
+                chunk.addAll(records.subList(i, Math.min(records.size(), i + scope)));
+                // Each chunk is a transaction of its own.
+                Limits.begin(true);
+                try {
+                    batch.execute(context, chunk);
+                } catch (RuntimeException e) {
+                    job.NumberOfErrors++;
+                    job.ExtendedStatus = e.getMessage();
+                } finally {
+                    Limits.end();
+                }
+                job.JobItemsProcessed++;
+            }
+            batch.finish(context);
+        } finally {
+            System.batch = previous;
+        }
+    }
+
+    // query runs a SOQL query against the DataStore. Bind variables are
+    // written as ? in the query and passed in order after it.
+    @SuppressWarnings("unchecked")
+    public static <T extends SObject> List<T> query(String soql, Object... binds) {
+        List<T> result = new List<T>();
+        for (SObject record : Soql.execute(soql, binds)) {
+            result.add((T) record);
+        }
+        Limits.query(result.size());
+        return result;
+    }
+
+    // queryRow runs a query assigned to a single record, which throws
+    // QueryException unless it returns exactly one row, as in Apex.
+    public static <T extends SObject> T queryRow(String soql, Object... binds) {
+        List<T> rows = query(soql, binds);
+        if (rows.isEmpty()) {
+            throw new QueryException("List has no rows for assignment to SObject");
+        }
+        if (rows.size() > 1) {
+            throw new QueryException("List has more than 1 row for assignment to SObject");
+        }
+        return rows.get(0);
+    }
+
+    // countQuery runs a SELECT COUNT() query and returns the number of rows
+    // it matches.
+    public static Integer countQuery(String soql, Object... binds) {
+        java.util.List<SObject> records = Soql.execute(soql, binds);
+        Limits.query(records.size());
+        return records.size();
+    }
+
+    // query runs a dynamic query, whose :name bind variables take the
+    // values of binds. Converted calls of Database.query pass the local
+    // variables and parameters in scope.
+    @SuppressWarnings("unchecked")
+    public static <T extends SObject> List<T> query(String soql, java.util.Map<String, Object> binds) {
+        List<T> result = new List<T>();
+        for (SObject record : Soql.execute(soql, binds)) {
+            result.add((T) record);
+        }
+        Limits.query(result.size());
+        return result;
+    }
+
+    public static <T extends SObject> List<T> queryWithBinds(String soql, java.util.Map<String, Object> binds, AccessLevel accessLevel) {
+        return query(soql, binds);
+    }
+
+    // binds returns the map of bind variables of names and values given in
+    // turn.
+    public static java.util.Map<String, Object> binds(Object... namesAndValues) {
+        java.util.Map<String, Object> binds = new java.util.HashMap<String, Object>();
+        for (int i = 0; i + 1 < namesAndValues.length; i += 2) {
+            binds.put((String) namesAndValues[i], namesAndValues[i + 1]);
+        }
+        return binds;
+    }
+
+    public static void insert(Object records) {
+        for (SObject record : records(records)) {
+            DataStore.insert(record);
+        }
+    }
+
+    public static void update(Object records) {
+        for (SObject record : records(records)) {
+            DataStore.update(record);
+        }
+    }
+
+    public static void upsert(Object records) {
+        for (SObject record : records(records)) {
+            DataStore.upsert(record);
+        }
+    }
+
+    public static void upsert(Object records, String externalIdField) {
+        for (SObject record : records(records)) {
+            DataStore.upsert(record, externalIdField);
+        }
+    }
+
+    public static void delete(Object records) {
+        for (SObject record : records(records)) {
+            DataStore.delete(record);
+        }
+    }
+
+    public static void undelete(Object records) {
+        for (SObject record : records(records)) {
+            DataStore.undelete(record);
+        }
+    }
+
+    // records accepts the operand of a DML statement, a single record or a
+    // list of them, and counts the statement against the DML limits.
+    @SuppressWarnings("unchecked")
+    private static java.util.List<SObject> records(Object records) {
+        java.util.List<SObject> list;
+        if (records instanceof SObject) {
+            list = java.util.Collections.singletonList((SObject) records);
+        } else if (records instanceof java.util.List) {
+            list = (java.util.List<SObject>) records;
+        } else {
+            throw new NullPointerException();
+        }
+        Limits.dml(list.size());
+        return list;
     }
 }
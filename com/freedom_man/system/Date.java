@@ -0,0 +1,143 @@
+package com.freedom_man.system;
+
+import java.text.SimpleDateFormat;
+import java.time.DayOfWeek;
+import java.time.LocalDate;
+import java.time.ZoneId;
+import java.time.format.DateTimeParseException;
+import java.time.temporal.ChronoUnit;
+import java.time.temporal.TemporalAdjusters;
+import java.util.TimeZone;
+
+// Date is the Apex Date, a day without a time zone. Methods that depend on
+// the current day or the locale use the time zone of the running user and
+// the en_US locale.
+public class Date implements Comparable<Date> {
+    final LocalDate value;
+
+    Date(LocalDate value) {
+        this.value = value;
+    }
+
+    public static Date today() {
+        return new Date(LocalDate.now(Datetime.userZone()));
+    }
+
+    public static Date newInstance(Integer year, Integer month, Integer day) {
+        return new Date(LocalDate.of(year, month, day));
+    }
+
+    // valueOf reads a date as yyyy-MM-dd, ignoring a time after it.
+    public static Date valueOf(String s) {
+        try {
+            return new Date(LocalDate.parse(s.trim().split(" ")[0]));
+        } catch (DateTimeParseException e) {
+            throw new TypeException("Invalid date: " + s);
+        }
+    }
+
+    // parse reads a date in the format of the en_US locale, M/d/yyyy.
+    public static Date parse(String s) {
+        try {
+            java.util.Date parsed = formatter("M/d/yyyy", ZoneId.of("UTC")).parse(s);
+            return new Date(parsed.toInstant().atZone(ZoneId.of("UTC")).toLocalDate());
+        } catch (java.text.ParseException e) {
+            throw new TypeException("Invalid date: " + s);
+        }
+    }
+
+    public static Boolean isLeapYear(Integer year) {
+        return java.time.Year.isLeap(year);
+    }
+
+    public static Integer daysInMonth(Integer year, Integer month) {
+        return java.time.YearMonth.of(year, month).lengthOfMonth();
+    }
+
+    public Date addDays(Integer days) {
+        return new Date(value.plusDays(days));
+    }
+
+    public Date addMonths(Integer months) {
+        return new Date(value.plusMonths(months));
+    }
+
+    public Date addYears(Integer years) {
+        return new Date(value.plusYears(years));
+    }
+
+    // daysBetween returns the days from this date to other, negative if
+    // other is earlier.
+    public Integer daysBetween(Date other) {
+        return (int) ChronoUnit.DAYS.between(value, other.value);
+    }
+
+    // monthsBetween returns the months from this date to other, ignoring the
+    // days of the month.
+    public Integer monthsBetween(Date other) {
+        return (other.value.getYear() - value.getYear()) * 12 + other.value.getMonthValue() - value.getMonthValue();
+    }
+
+    public Integer year() {
+        return value.getYear();
+    }
+
+    public Integer month() {
+        return value.getMonthValue();
+    }
+
+    public Integer day() {
+        return value.getDayOfMonth();
+    }
+
+    public Integer dayOfYear() {
+        return value.getDayOfYear();
+    }
+
+    public Boolean isSameDay(Date other) {
+        return other != null && value.equals(other.value);
+    }
+
+    public Date toStartOfMonth() {
+        return new Date(value.withDayOfMonth(1));
+    }
+
+    // toStartOfWeek returns the Sunday before or on this date, the first day
+    // of the week in the en_US locale.
+    public Date toStartOfWeek() {
+        return new Date(value.with(TemporalAdjusters.previousOrSame(DayOfWeek.SUNDAY)));
+    }
+
+    // format returns the date in the format of the en_US locale, M/d/yyyy.
+    public String format() {
+        return formatter("M/d/yyyy", ZoneId.of("UTC")).format(java.util.Date.from(value.atStartOfDay(ZoneId.of("UTC")).toInstant()));
+    }
+
+    static SimpleDateFormat formatter(String pattern, ZoneId zone) {
+        SimpleDateFormat format = new SimpleDateFormat(pattern, java.util.Locale.US);
+        format.setTimeZone(TimeZone.getTimeZone(zone));
+        format.setLenient(false);
+        return format;
+    }
+
+    @Override
+    public int compareTo(Date other) {
+        return value.compareTo(other.value);
+    }
+
+    @Override
+    public boolean equals(Object o) {
+        return o instanceof Date && value.equals(((Date) o).value);
+    }
+
+    @Override
+    public int hashCode() {
+        return value.hashCode();
+    }
+
+    // toString returns the date as yyyy-MM-dd, as String.valueOf does.
+    @Override
+    public String toString() {
+        return value.toString();
+    }
+}
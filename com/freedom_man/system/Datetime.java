@@ -0,0 +1,253 @@
+package com.freedom_man.system;
+
+import java.time.Instant;
+import java.time.LocalDate;
+import java.time.LocalDateTime;
+import java.time.ZoneId;
+import java.time.ZoneOffset;
+import java.time.ZonedDateTime;
+
+// Datetime is the Apex Datetime, an instant in time. Methods without Gmt
+// in their name work in the time zone of the running user: the
+// TimeZoneSidKey of the user of System.runAs, or else the apex.timezone
+// system property, or else the JVM's default time zone.
+public class Datetime implements Comparable<Datetime> {
+    private static final ZoneId GMT = ZoneOffset.UTC;
+
+    final Instant value;
+
+    Datetime(Instant value) {
+        this.value = value;
+    }
+
+    static ZoneId userZone() {
+        User user = System.runningUser();
+        if (user != null && user.TimeZoneSidKey != null) {
+            return ZoneId.of(user.TimeZoneSidKey);
+        }
+        String zone = java.lang.System.getProperty("apex.timezone");
+        return zone == null ? ZoneId.systemDefault() : ZoneId.of(zone);
+    }
+
+    private ZonedDateTime local() {
+        return value.atZone(userZone());
+    }
+
+    private ZonedDateTime gmt() {
+        return value.atZone(GMT);
+    }
+
+    private static Datetime of(LocalDateTime time, ZoneId zone) {
+        return new Datetime(time.atZone(zone).toInstant());
+    }
+
+    public static Datetime now() {
+        return new Datetime(Instant.now());
+    }
+
+    public static Datetime newInstance(Long milliseconds) {
+        return new Datetime(Instant.ofEpochMilli(milliseconds));
+    }
+
+    public static Datetime newInstance(Integer year, Integer month, Integer day) {
+        return of(LocalDate.of(year, month, day).atStartOfDay(), userZone());
+    }
+
+    public static Datetime newInstance(Integer year, Integer month, Integer day, Integer hour, Integer minute, Integer second) {
+        return of(LocalDateTime.of(year, month, day, hour, minute, second), userZone());
+    }
+
+    public static Datetime newInstance(Date date, Time time) {
+        return of(LocalDateTime.of(date.value, time.value), userZone());
+    }
+
+    public static Datetime newInstanceGmt(Integer year, Integer month, Integer day) {
+        return of(LocalDate.of(year, month, day).atStartOfDay(), GMT);
+    }
+
+    public static Datetime newInstanceGmt(Integer year, Integer month, Integer day, Integer hour, Integer minute, Integer second) {
+        return of(LocalDateTime.of(year, month, day, hour, minute, second), GMT);
+    }
+
+    public static Datetime newInstanceGmt(Date date, Time time) {
+        return of(LocalDateTime.of(date.value, time.value), GMT);
+    }
+
+    // valueOf reads a local time as yyyy-MM-dd HH:mm:ss.
+    public static Datetime valueOf(String s) {
+        return valueOf(s, userZone());
+    }
+
+    public static Datetime valueOfGmt(String s) {
+        return valueOf(s, GMT);
+    }
+
+    private static Datetime valueOf(String s, ZoneId zone) {
+        try {
+            return of(LocalDateTime.parse(s.trim().replace(' ', 'T')), zone);
+        } catch (java.time.format.DateTimeParseException e) {
+            throw new TypeException("Invalid date/time: " + s);
+        }
+    }
+
+    // parse reads a local time in the format of the en_US locale,
+    // M/d/yyyy h:mm a.
+    public static Datetime parse(String s) {
+        try {
+            return new Datetime(Date.formatter("M/d/yyyy h:mm a", userZone()).parse(s).toInstant());
+        } catch (java.text.ParseException e) {
+            throw new TypeException("Invalid date/time: " + s);
+        }
+    }
+
+    public Datetime addDays(Integer days) {
+        return new Datetime(local().plusDays(days).toInstant());
+    }
+
+    public Datetime addMonths(Integer months) {
+        return new Datetime(local().plusMonths(months).toInstant());
+    }
+
+    public Datetime addYears(Integer years) {
+        return new Datetime(local().plusYears(years).toInstant());
+    }
+
+    public Datetime addHours(Integer hours) {
+        return new Datetime(value.plusSeconds(hours * 3600L));
+    }
+
+    public Datetime addMinutes(Integer minutes) {
+        return new Datetime(value.plusSeconds(minutes * 60L));
+    }
+
+    public Datetime addSeconds(Integer seconds) {
+        return new Datetime(value.plusSeconds(seconds));
+    }
+
+    public Date date() {
+        return new Date(local().toLocalDate());
+    }
+
+    public Date dateGmt() {
+        return new Date(gmt().toLocalDate());
+    }
+
+    public Time time() {
+        return new Time(local().toLocalTime());
+    }
+
+    public Time timeGmt() {
+        return new Time(gmt().toLocalTime());
+    }
+
+    public Integer year() {
+        return local().getYear();
+    }
+
+    public Integer yearGmt() {
+        return gmt().getYear();
+    }
+
+    public Integer month() {
+        return local().getMonthValue();
+    }
+
+    public Integer monthGmt() {
+        return gmt().getMonthValue();
+    }
+
+    public Integer day() {
+        return local().getDayOfMonth();
+    }
+
+    public Integer dayGmt() {
+        return gmt().getDayOfMonth();
+    }
+
+    public Integer dayOfYear() {
+        return local().getDayOfYear();
+    }
+
+    public Integer hour() {
+        return local().getHour();
+    }
+
+    public Integer hourGmt() {
+        return gmt().getHour();
+    }
+
+    public Integer minute() {
+        return local().getMinute();
+    }
+
+    public Integer minuteGmt() {
+        return gmt().getMinute();
+    }
+
+    public Integer second() {
+        return local().getSecond();
+    }
+
+    public Integer secondGmt() {
+        return gmt().getSecond();
+    }
+
+    public Integer millisecond() {
+        return local().getNano() / 1000000;
+    }
+
+    // getTime returns the milliseconds since 1970-01-01 00:00:00 GMT.
+    public Long getTime() {
+        return value.toEpochMilli();
+    }
+
+    public Boolean isSameDay(Datetime other) {
+        return other != null && local().toLocalDate().equals(other.local().toLocalDate());
+    }
+
+    // format returns the local time in the format of the en_US locale,
+    // M/d/yyyy h:mm a.
+    public String format() {
+        return format("M/d/yyyy h:mm a");
+    }
+
+    // format returns the local time in a java.text.SimpleDateFormat
+    // pattern, as Apex does.
+    public String format(String pattern) {
+        return format(pattern, userZone().getId());
+    }
+
+    public String format(String pattern, String timeZone) {
+        return Date.formatter(pattern, ZoneId.of(timeZone)).format(java.util.Date.from(value));
+    }
+
+    public String formatGmt(String pattern) {
+        return Date.formatter(pattern, GMT).format(java.util.Date.from(value));
+    }
+
+    public String formatLong() {
+        return format("M/d/yyyy h:mm:ss a z");
+    }
+
+    @Override
+    public int compareTo(Datetime other) {
+        return value.compareTo(other.value);
+    }
+
+    @Override
+    public boolean equals(Object o) {
+        return o instanceof Datetime && value.equals(((Datetime) o).value);
+    }
+
+    @Override
+    public int hashCode() {
+        return value.hashCode();
+    }
+
+    // toString returns the time in GMT as yyyy-MM-dd HH:mm:ss, as
+    // System.debug writes it.
+    @Override
+    public String toString() {
+        return formatGmt("yyyy-MM-dd HH:mm:ss");
+    }
+}
@@ -0,0 +1,69 @@
+package com.freedom_man.system;
+
+// DmlException carries one entry per failed record, as returned by the
+// getDml* accessors in Apex.
+public class DmlException extends Exception {
+    private final java.util.List<DmlError> errors = new java.util.ArrayList<>();
+
+    public DmlException() {
+        super();
+    }
+
+    public DmlException(String message) {
+        super(message);
+    }
+
+    public DmlException(Exception cause) {
+        super(cause);
+    }
+
+    public DmlException(String message, Exception cause) {
+        super(message, cause);
+    }
+
+    public void addDmlError(Integer index, String id, String statusCode, String message, java.util.List<String> fieldNames) {
+        errors.add(new DmlError(index, id, statusCode, message, fieldNames));
+    }
+
+    public Integer getNumDml() {
+        return errors.size();
+    }
+
+    public Integer getDmlIndex(Integer i) {
+        return errors.get(i).index;
+    }
+
+    public String getDmlId(Integer i) {
+        return errors.get(i).id;
+    }
+
+    public String getDmlMessage(Integer i) {
+        return errors.get(i).message;
+    }
+
+    public String getDmlType(Integer i) {
+        return errors.get(i).statusCode;
+    }
+
+    public List<String> getDmlFieldNames(Integer i) {
+        List<String> names = new List<String>();
+        names.addAll(errors.get(i).fieldNames);
+        return names;
+    }
+
+    private static class DmlError {
+        final Integer index;
+        final String id;
+        final String statusCode;
+        final String message;
+        final java.util.List<String> fieldNames;
+
+        DmlError(Integer index, String id, String statusCode, String message, java.util.List<String> fieldNames) {
+            this.index = index;
+            this.id = id;
+            this.statusCode = statusCode;
+            this.message = message;
+            this.fieldNames = fieldNames == null ? new java.util.ArrayList<String>() : fieldNames;
+        }
+    }
+}
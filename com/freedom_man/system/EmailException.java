@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class EmailException extends Exception {
+    public EmailException() {
+        super();
+    }
+
+    public EmailException(String message) {
+        super(message);
+    }
+
+    public EmailException(Exception cause) {
+        super(cause);
+    }
+
+    public EmailException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
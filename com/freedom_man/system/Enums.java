@@ -0,0 +1,23 @@
+package com.freedom_man.system;
+
+// Enums implements the static methods every Apex enum type has, which Java
+// enums either lack or declare with different types.
+public class Enums {
+    // values returns the constants as a List, where Java returns an array.
+    public static <T extends java.lang.Enum<T>> List<T> values(Class<T> type) {
+        List<T> values = new List<T>();
+        for (T value : type.getEnumConstants()) {
+            values.add(value);
+        }
+        return values;
+    }
+
+    public static <T extends java.lang.Enum<T>> T valueOf(Class<T> type, String name) {
+        for (T value : type.getEnumConstants()) {
+            if (value.name().equals(name)) {
+                return value;
+            }
+        }
+        throw new NoSuchElementException("Invalid enum value: " + name);
+    }
+}
@@ -0,0 +1,132 @@
+package com.freedom_man.system;
+
+// Exception is the root of both the System exception hierarchy and custom
+// Apex exceptions, which get the same four constructors Apex gives them
+// implicitly.
+public class Exception extends RuntimeException {
+    private String message;
+
+    public Exception() {
+        super();
+    }
+
+    public Exception(String message) {
+        super(message);
+        this.message = message;
+    }
+
+    public Exception(Exception cause) {
+        super(cause);
+        this.message = cause == null ? null : cause.getTypeName() + ": " + cause.getMessage();
+    }
+
+    public Exception(String message, Exception cause) {
+        super(message, cause);
+        this.message = message;
+    }
+
+    @Override
+    public String getMessage() {
+        return message;
+    }
+
+    public void setMessage(String message) {
+        this.message = message;
+    }
+
+    @Override
+    public Exception getCause() {
+        Throwable cause = super.getCause();
+        if (cause instanceof Exception) {
+            return (Exception) cause;
+        }
+        return null;
+    }
+
+    public Exception initCause(Exception cause) {
+        super.initCause(cause);
+        return this;
+    }
+
+    // translate returns the System exception Apex throws where the JVM threw
+    // e, such as NullPointerException for java.lang.NullPointerException,
+    // with the stack trace and cause e. Converted try statements rethrow what
+    // they catch through it, so that their catch clauses see the System
+    // exceptions. Apex exceptions and the others are returned as they are.
+    public static RuntimeException translate(RuntimeException e) {
+        Exception translated;
+        if (e instanceof Exception) {
+            return e;
+        } else if (e instanceof java.lang.NullPointerException) {
+            translated = new NullPointerException();
+        } else if (e instanceof IndexOutOfBoundsException) {
+            translated = new ListException(e.getMessage());
+        } else if (e instanceof ClassCastException || e instanceof NumberFormatException) {
+            translated = new TypeException(e.getMessage());
+        } else if (e instanceof ArithmeticException) {
+            translated = new MathException(e.getMessage());
+        } else if (e instanceof java.util.ConcurrentModificationException) {
+            translated = new FinalException("Cannot modify a collection while it is being iterated.");
+        } else if (e instanceof java.util.NoSuchElementException) {
+            translated = new NoSuchElementException(e.getMessage());
+        } else if (e instanceof java.lang.IllegalArgumentException) {
+            translated = new IllegalArgumentException(e.getMessage());
+        } else {
+            return e;
+        }
+        translated.setStackTrace(e.getStackTrace());
+        translated.initCause((Throwable) e);
+        return translated;
+    }
+
+    // getLineNumber returns the line of the first frame outside the runtime,
+    // which is its Apex line for classes with a source map.
+    public Integer getLineNumber() {
+        StackTraceElement frame = firstUserFrame();
+        return frame == null ? -1 : SourceMap.apexLine(frame);
+    }
+
+    public String getStackTraceString() {
+        StringBuilder sb = new StringBuilder();
+        for (StackTraceElement e : getStackTrace()) {
+            if (e.getClassName().startsWith(Exception.class.getPackage().getName() + ".")) {
+                continue;
+            }
+            if (sb.length() != 0) {
+                sb.append("\n");
+            }
+            sb.append("Class.").append(typeName(e.getClassName())).append(".").append(e.getMethodName())
+                .append(": line ").append(SourceMap.apexLine(e));
+        }
+        return sb.toString();
+    }
+
+    // getTypeName returns System.XxxException for system exceptions and the
+    // Apex class name for custom ones.
+    public String getTypeName() {
+        Class<?> c = getClass();
+        if (c.getPackage() != null && c.getPackage().equals(Exception.class.getPackage())) {
+            return "System." + c.getSimpleName();
+        }
+        return typeName(c.getName());
+    }
+
+    @Override
+    public String toString() {
+        String message = getMessage();
+        return message == null ? getTypeName() : getTypeName() + ": " + message;
+    }
+
+    private StackTraceElement firstUserFrame() {
+        for (StackTraceElement e : getStackTrace()) {
+            if (!e.getClassName().startsWith(Exception.class.getPackage().getName() + ".")) {
+                return e;
+            }
+        }
+        return null;
+    }
+
+    private static String typeName(String className) {
+        return className.replace('$', '.');
+    }
+}
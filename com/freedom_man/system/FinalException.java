@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class FinalException extends Exception {
+    public FinalException() {
+        super();
+    }
+
+    public FinalException(String message) {
+        super(message);
+    }
+
+    public FinalException(Exception cause) {
+        super(cause);
+    }
+
+    public FinalException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,13 @@
+package com.freedom_man.system;
+
+import java.lang.annotation.ElementType;
+import java.lang.annotation.Retention;
+import java.lang.annotation.RetentionPolicy;
+import java.lang.annotation.Target;
+
+// Future marks a static method that Apex runs asynchronously.
+@Retention(RetentionPolicy.RUNTIME)
+@Target(ElementType.METHOD)
+public @interface Future {
+    boolean callout() default false;
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class HandledException extends Exception {
+    public HandledException() {
+        super();
+    }
+
+    public HandledException(String message) {
+        super(message);
+    }
+
+    public HandledException(Exception cause) {
+        super(cause);
+    }
+
+    public HandledException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
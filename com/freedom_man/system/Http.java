@@ -0,0 +1,115 @@
+package com.freedom_man.system;
+
+import java.io.ByteArrayOutputStream;
+import java.io.IOException;
+import java.net.URI;
+import java.net.http.HttpClient;
+import java.nio.charset.StandardCharsets;
+import java.time.Duration;
+import java.util.HashMap;
+import java.util.zip.GZIPOutputStream;
+
+// Http is the Apex Http. In tests, requests go to the HttpCalloutMock
+// registered with Test.setMock, and fail without one unless the
+// apex.callouts system property is true, which lets tests call a local
+// stub server. Otherwise they are sent with java.net.http, with
+// callout:Name endpoints resolved by NamedCredentials.
+public class Http {
+    private static final java.util.Map<Integer, String> REASONS = new HashMap<Integer, String>();
+
+    static {
+        REASONS.put(200, "OK");
+        REASONS.put(201, "Created");
+        REASONS.put(202, "Accepted");
+        REASONS.put(204, "No Content");
+        REASONS.put(301, "Moved Permanently");
+        REASONS.put(302, "Found");
+        REASONS.put(304, "Not Modified");
+        REASONS.put(400, "Bad Request");
+        REASONS.put(401, "Unauthorized");
+        REASONS.put(403, "Forbidden");
+        REASONS.put(404, "Not Found");
+        REASONS.put(405, "Method Not Allowed");
+        REASONS.put(409, "Conflict");
+        REASONS.put(429, "Too Many Requests");
+        REASONS.put(500, "Internal Server Error");
+        REASONS.put(502, "Bad Gateway");
+        REASONS.put(503, "Service Unavailable");
+        REASONS.put(504, "Gateway Timeout");
+    }
+
+    public HttpResponse send(HttpRequest request) {
+        if (request.getEndpoint() == null) {
+            throw new CalloutException("Endpoint URL is not set");
+        }
+        if (request.getMethod() == null) {
+            throw new CalloutException("Method is not set");
+        }
+        Limits.callout();
+        if (Test.isRunningTest()) {
+            HttpCalloutMock mock = Test.mock(HttpCalloutMock.class);
+            if (mock != null) {
+                HttpResponse response = mock.respond(request);
+                if (response == null) {
+                    throw new CalloutException("The HttpCalloutMock returned no response");
+                }
+                return response;
+            }
+            if (!Boolean.getBoolean("apex.callouts")) {
+                throw new CalloutException("Methods defined as TestMethod do not support Web service callouts");
+            }
+        }
+        return sendRequest(NamedCredentials.resolve(request));
+    }
+
+    private static HttpResponse sendRequest(HttpRequest request) {
+        Duration timeout = Duration.ofMillis(request.getTimeout());
+        java.net.http.HttpRequest.Builder builder;
+        try {
+            builder = java.net.http.HttpRequest.newBuilder(URI.create(request.getEndpoint())).timeout(timeout);
+            for (java.util.Map.Entry<String, String> header : request.headers.entrySet()) {
+                builder.header(header.getKey(), header.getValue());
+            }
+        } catch (java.lang.IllegalArgumentException e) {
+            throw new CalloutException(e.getMessage());
+        }
+        java.net.http.HttpRequest.BodyPublisher body = java.net.http.HttpRequest.BodyPublishers.noBody();
+        if (request.getBody() != null) {
+            byte[] bytes = request.getBody().getBytes(StandardCharsets.UTF_8);
+            if (Boolean.TRUE.equals(request.getCompressed())) {
+                bytes = gzip(bytes);
+                builder.header("Content-Encoding", "gzip");
+            }
+            body = java.net.http.HttpRequest.BodyPublishers.ofByteArray(bytes);
+        }
+        builder.method(request.getMethod().toUpperCase(), body);
+        HttpClient client = HttpClient.newBuilder().connectTimeout(timeout).build();
+        java.net.http.HttpResponse<String> received;
+        try {
+            received = client.send(builder.build(), java.net.http.HttpResponse.BodyHandlers.ofString());
+        } catch (IOException e) {
+            throw new CalloutException(e.getMessage());
+        } catch (InterruptedException e) {
+            Thread.currentThread().interrupt();
+            throw new CalloutException("Callout interrupted");
+        }
+        HttpResponse response = new HttpResponse();
+        response.setStatusCode(received.statusCode());
+        response.setStatus(REASONS.get(received.statusCode()));
+        for (java.util.Map.Entry<String, java.util.List<String>> header : received.headers().map().entrySet()) {
+            response.setHeader(header.getKey(), String.join(",", header.getValue()));
+        }
+        response.setBody(received.body());
+        return response;
+    }
+
+    private static byte[] gzip(byte[] bytes) {
+        ByteArrayOutputStream out = new ByteArrayOutputStream();
+        try (GZIPOutputStream gz = new GZIPOutputStream(out)) {
+            gz.write(bytes);
+        } catch (IOException e) {
+            throw new CalloutException(e.getMessage());
+        }
+        return out.toByteArray();
+    }
+}
@@ -0,0 +1,71 @@
+package com.freedom_man.system;
+
+import java.util.TreeMap;
+
+// HttpRequest is the Apex HttpRequest. Header names are case-insensitive.
+public class HttpRequest {
+    private String endpoint;
+    private String method;
+    private String body;
+    private Integer timeout = 10000;
+    private Boolean compressed = false;
+    final TreeMap<String, String> headers = new TreeMap<String, String>(String.CASE_INSENSITIVE_ORDER);
+
+    public void setEndpoint(String endpoint) {
+        this.endpoint = endpoint;
+    }
+
+    public String getEndpoint() {
+        return endpoint;
+    }
+
+    public void setMethod(String method) {
+        this.method = method;
+    }
+
+    public String getMethod() {
+        return method;
+    }
+
+    public void setHeader(String key, String value) {
+        headers.put(key, value);
+    }
+
+    public String getHeader(String key) {
+        return headers.get(key);
+    }
+
+    public void setBody(String body) {
+        this.body = body;
+    }
+
+    public String getBody() {
+        return body;
+    }
+
+    // setTimeout sets the timeout in milliseconds, which Apex limits to
+    // two minutes.
+    public void setTimeout(Integer timeout) {
+        if (timeout == null || timeout < 1 || timeout > 120000) {
+            throw new CalloutException("Timeout must be between 1 and 120000 milliseconds: " + timeout);
+        }
+        this.timeout = timeout;
+    }
+
+    public Integer getTimeout() {
+        return timeout;
+    }
+
+    public void setCompressed(Boolean compressed) {
+        this.compressed = compressed;
+    }
+
+    public Boolean getCompressed() {
+        return compressed;
+    }
+
+    @Override
+    public String toString() {
+        return "System.HttpRequest[Endpoint=" + endpoint + ", Method=" + method + "]";
+    }
+}
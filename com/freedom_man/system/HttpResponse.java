@@ -0,0 +1,54 @@
+package com.freedom_man.system;
+
+import java.util.TreeMap;
+
+// HttpResponse is the Apex HttpResponse. Header names are case-insensitive.
+public class HttpResponse {
+    private Integer statusCode = 0;
+    private String status;
+    private String body;
+    private final TreeMap<String, String> headers = new TreeMap<String, String>(String.CASE_INSENSITIVE_ORDER);
+
+    public void setStatusCode(Integer statusCode) {
+        this.statusCode = statusCode;
+    }
+
+    public Integer getStatusCode() {
+        return statusCode;
+    }
+
+    public void setStatus(String status) {
+        this.status = status;
+    }
+
+    public String getStatus() {
+        return status;
+    }
+
+    public void setHeader(String key, String value) {
+        headers.put(key, value);
+    }
+
+    public String getHeader(String key) {
+        return headers.get(key);
+    }
+
+    public List<String> getHeaderKeys() {
+        List<String> keys = new List<String>();
+        keys.addAll(headers.keySet());
+        return keys;
+    }
+
+    public void setBody(String body) {
+        this.body = body;
+    }
+
+    public String getBody() {
+        return body;
+    }
+
+    @Override
+    public String toString() {
+        return "System.HttpResponse[Status=" + status + ", StatusCode=" + statusCode + "]";
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class IllegalArgumentException extends Exception {
+    public IllegalArgumentException() {
+        super();
+    }
+
+    public IllegalArgumentException(String message) {
+        super(message);
+    }
+
+    public IllegalArgumentException(Exception cause) {
+        super(cause);
+    }
+
+    public IllegalArgumentException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class InvalidParameterValueException extends Exception {
+    public InvalidParameterValueException() {
+        super();
+    }
+
+    public InvalidParameterValueException(String message) {
+        super(message);
+    }
+
+    public InvalidParameterValueException(Exception cause) {
+        super(cause);
+    }
+
+    public InvalidParameterValueException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
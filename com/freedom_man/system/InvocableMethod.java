@@ -0,0 +1,23 @@
+package com.freedom_man.system;
+
+import java.lang.annotation.ElementType;
+import java.lang.annotation.Retention;
+import java.lang.annotation.RetentionPolicy;
+import java.lang.annotation.Target;
+
+// InvocableMethod exposes a static method to Flow and Process Builder.
+@Retention(RetentionPolicy.RUNTIME)
+@Target(ElementType.METHOD)
+public @interface InvocableMethod {
+    String label() default "";
+
+    String description() default "";
+
+    boolean callout() default false;
+
+    String category() default "";
+
+    String configurationEditor() default "";
+
+    String iconName() default "";
+}
@@ -0,0 +1,17 @@
+package com.freedom_man.system;
+
+import java.lang.annotation.ElementType;
+import java.lang.annotation.Retention;
+import java.lang.annotation.RetentionPolicy;
+import java.lang.annotation.Target;
+
+// InvocableVariable exposes a field of an invocable method's input or output.
+@Retention(RetentionPolicy.RUNTIME)
+@Target(ElementType.FIELD)
+public @interface InvocableVariable {
+    String label() default "";
+
+    String description() default "";
+
+    boolean required() default false;
+}
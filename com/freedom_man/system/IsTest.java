@@ -0,0 +1,17 @@
+package com.freedom_man.system;
+
+import java.lang.annotation.ElementType;
+import java.lang.annotation.Retention;
+import java.lang.annotation.RetentionPolicy;
+import java.lang.annotation.Target;
+
+// IsTest marks Apex test classes and test methods.
+@Retention(RetentionPolicy.RUNTIME)
+@Target({ElementType.TYPE, ElementType.METHOD})
+public @interface IsTest {
+    boolean seeAllData() default false;
+
+    boolean isParallel() default false;
+
+    boolean onInstall() default false;
+}
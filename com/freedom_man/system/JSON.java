@@ -0,0 +1,592 @@
+package com.freedom_man.system;
+
+import java.lang.reflect.Field;
+import java.lang.reflect.Modifier;
+import java.math.BigDecimal;
+import java.time.LocalTime;
+import java.time.OffsetDateTime;
+import java.time.format.DateTimeParseException;
+import java.util.ArrayList;
+import java.util.IdentityHashMap;
+import java.util.LinkedHashMap;
+import java.util.LinkedHashSet;
+
+// JSON is the Apex JSON class. Values are serialized through a tree of
+// java.util.Map, java.util.List, String, Number, Boolean and null: objects
+// become maps of their instance fields, found by reflection, and
+// deserialization fills the fields of a new instance of the requested Type
+// from such a tree. Untyped values are read into the runtime's Map and List.
+public class JSON {
+    private static final String DATETIME_FORMAT = "yyyy-MM-dd'T'HH:mm:ss.SSS'Z'";
+
+    public static String serialize(Object value) {
+        return serialize(value, false);
+    }
+
+    // serialize leaves out the null fields of objects if
+    // suppressApexObjectNulls is true.
+    public static String serialize(Object value, Boolean suppressApexObjectNulls) {
+        return write(toTree(value, suppressApexObjectNulls), false);
+    }
+
+    public static String serializePretty(Object value) {
+        return serializePretty(value, false);
+    }
+
+    public static String serializePretty(Object value, Boolean suppressApexObjectNulls) {
+        return write(toTree(value, suppressApexObjectNulls), true);
+    }
+
+    // deserialize reads json as the type, ignoring the fields the type does
+    // not have.
+    public static Object deserialize(String json, Type type) {
+        return convert(parse(json), type, false);
+    }
+
+    // deserializeStrict reads json as the type, failing on fields the type
+    // does not have.
+    public static Object deserializeStrict(String json, Type type) {
+        return convert(parse(json), type, true);
+    }
+
+    // deserializeUntyped reads objects as Map<String, Object> and arrays as
+    // List<Object>.
+    public static Object deserializeUntyped(String json) {
+        return parse(json);
+    }
+
+    public static JSONGenerator createGenerator(Boolean pretty) {
+        return new JSONGenerator(pretty);
+    }
+
+    public static JSONParser createParser(String json) {
+        return new JSONParser(json);
+    }
+
+    static Object toTree(Object value, boolean suppressNulls) {
+        return toTree(value, suppressNulls, new IdentityHashMap<Object, Boolean>());
+    }
+
+    private static Object toTree(Object value, boolean suppressNulls, IdentityHashMap<Object, Boolean> path) {
+        if (value == null || value instanceof String || value instanceof Number || value instanceof Boolean) {
+            return value;
+        }
+        if (value instanceof Date || value instanceof Time) {
+            return value.toString();
+        }
+        if (value instanceof Datetime) {
+            return ((Datetime) value).formatGmt(DATETIME_FORMAT);
+        }
+        if (value instanceof Enum) {
+            return ((Enum<?>) value).name();
+        }
+        if (path.put(value, Boolean.TRUE) != null) {
+            throw new JSONException("Cannot serialize a circular reference: " + value.getClass().getSimpleName());
+        }
+        try {
+            if (value instanceof java.util.Map) {
+                java.util.Map<String, Object> tree = new LinkedHashMap<String, Object>();
+                for (java.util.Map.Entry<?, ?> e : ((java.util.Map<?, ?>) value).entrySet()) {
+                    tree.put(ApexOperator.toString(e.getKey()), toTree(e.getValue(), suppressNulls, path));
+                }
+                return tree;
+            }
+            if (value instanceof Iterable) {
+                java.util.List<Object> tree = new ArrayList<Object>();
+                for (Object item : (Iterable<?>) value) {
+                    tree.add(toTree(item, suppressNulls, path));
+                }
+                return tree;
+            }
+            java.util.Map<String, Object> tree = new LinkedHashMap<String, Object>();
+            if (value instanceof SObject) {
+                java.util.Map<String, Object> attributes = new LinkedHashMap<String, Object>();
+                attributes.put("type", DataStore.typeName((SObject) value));
+                tree.put("attributes", attributes);
+                // only the fields that are set, as Apex does for SObjects
+                suppressNulls = true;
+            }
+            for (Field f : fields(value.getClass())) {
+                Object v = toTree(get(f, value), suppressNulls, path);
+                if (v != null || !suppressNulls) {
+                    tree.put(f.getName(), v);
+                }
+            }
+            if (value instanceof SObject) {
+                for (java.util.Map.Entry<String, Object> e : ((SObject) value).undeclaredFields().entrySet()) {
+                    Object v = toTree(e.getValue(), suppressNulls, path);
+                    if (v != null) {
+                        tree.put(e.getKey(), v);
+                    }
+                }
+            }
+            return tree;
+        } finally {
+            path.remove(value);
+        }
+    }
+
+    // fields returns the instance fields of c and its superclasses that are
+    // serialized, those of the superclasses first.
+    private static java.util.List<Field> fields(Class<?> c) {
+        java.util.List<Field> fields = new ArrayList<Field>();
+        if (c.getSuperclass() != null && c.getSuperclass() != Object.class) {
+            fields.addAll(fields(c.getSuperclass()));
+        }
+        for (Field f : c.getDeclaredFields()) {
+            int modifiers = f.getModifiers();
+            if (Modifier.isStatic(modifiers) || Modifier.isTransient(modifiers) || f.isSynthetic()) {
+                continue;
+            }
+            if (f.getDeclaringClass() == SObject.class && f.getName().equals("type")) {
+                continue;
+            }
+            f.setAccessible(true);
+            fields.add(f);
+        }
+        return fields;
+    }
+
+    private static Field field(Class<?> c, String name) {
+        for (Field f : fields(c)) {
+            if (f.getName().equalsIgnoreCase(name)) {
+                return f;
+            }
+        }
+        return null;
+    }
+
+    private static Object get(Field f, Object o) {
+        try {
+            return f.get(o);
+        } catch (IllegalAccessException e) {
+            throw new JSONException(e.getMessage());
+        }
+    }
+
+    // convert reads a tree as the type.
+    @SuppressWarnings({"unchecked", "rawtypes"})
+    static Object convert(Object tree, Type type, boolean strict) {
+        Class<?> raw = type.raw;
+        if (tree == null || raw == Object.class) {
+            return tree;
+        }
+        if (raw == String.class) {
+            if (tree instanceof java.util.Map || tree instanceof java.util.List) {
+                throw cannotConvert(tree, type);
+            }
+            return ApexOperator.toString(tree);
+        }
+        if (raw == Integer.class) {
+            return decimal(tree, type).intValue();
+        }
+        if (raw == Long.class) {
+            return decimal(tree, type).longValue();
+        }
+        if (raw == Double.class) {
+            return decimal(tree, type).doubleValue();
+        }
+        if (raw == BigDecimal.class) {
+            return decimal(tree, type);
+        }
+        if (raw == Boolean.class) {
+            if (tree instanceof Boolean) {
+                return tree;
+            }
+            if ("true".equals(tree) || "false".equals(tree)) {
+                return Boolean.valueOf((String) tree);
+            }
+            throw cannotConvert(tree, type);
+        }
+        if (raw == Date.class) {
+            return Date.valueOf(string(tree, type));
+        }
+        if (raw == Datetime.class) {
+            return datetime(string(tree, type));
+        }
+        if (raw == Time.class) {
+            return time(string(tree, type));
+        }
+        if (raw.isEnum()) {
+            String name = string(tree, type);
+            for (Object constant : raw.getEnumConstants()) {
+                if (((Enum<?>) constant).name().equalsIgnoreCase(name)) {
+                    return constant;
+                }
+            }
+            throw cannotConvert(tree, type);
+        }
+        if (java.util.Collection.class.isAssignableFrom(raw)) {
+            if (!(tree instanceof java.util.List)) {
+                throw cannotConvert(tree, type);
+            }
+            java.util.Collection c;
+            if (!Modifier.isAbstract(raw.getModifiers())) {
+                c = (java.util.Collection) type.newInstance();
+            } else if (java.util.Set.class.isAssignableFrom(raw)) {
+                c = new LinkedHashSet<Object>();
+            } else {
+                c = new List<Object>();
+            }
+            Type element = parameter(type, 0);
+            for (Object item : (java.util.List<?>) tree) {
+                c.add(convert(item, element, strict));
+            }
+            return c;
+        }
+        if (!(tree instanceof java.util.Map)) {
+            throw cannotConvert(tree, type);
+        }
+        java.util.Map<?, ?> object = (java.util.Map<?, ?>) tree;
+        if (java.util.Map.class.isAssignableFrom(raw)) {
+            java.util.Map m = Modifier.isAbstract(raw.getModifiers()) ? new Map<Object, Object>() : (java.util.Map) type.newInstance();
+            Type key = parameter(type, 0);
+            Type value = parameter(type, 1);
+            for (java.util.Map.Entry<?, ?> e : object.entrySet()) {
+                m.put(convert(e.getKey(), key, strict), convert(e.getValue(), value, strict));
+            }
+            return m;
+        }
+        Object target = type.newInstance();
+        for (java.util.Map.Entry<?, ?> e : object.entrySet()) {
+            String name = (String) e.getKey();
+            if (target instanceof SObject && name.equals("attributes")) {
+                continue;
+            }
+            Field f = field(raw, name);
+            if (f == null && target instanceof SObject) {
+                // a field the sObject metadata lacks
+                ((SObject) target).setField(name, e.getValue());
+                continue;
+            }
+            if (f == null) {
+                if (strict) {
+                    throw new JSONException("No such column '" + name + "' on object of type " + type.getName());
+                }
+                continue;
+            }
+            try {
+                f.set(target, convert(e.getValue(), Type.reflected(f.getGenericType()), strict));
+            } catch (IllegalAccessException ex) {
+                throw new JSONException(ex.getMessage());
+            }
+        }
+        return target;
+    }
+
+    private static Type parameter(Type type, int i) {
+        return i < type.parameters.length ? type.parameters[i] : Type.of(Object.class);
+    }
+
+    private static BigDecimal decimal(Object tree, Type type) {
+        if (tree instanceof Number) {
+            return ApexOperator.toDecimal((Number) tree);
+        }
+        if (tree instanceof String) {
+            try {
+                return new BigDecimal(((String) tree).trim());
+            } catch (NumberFormatException e) {
+                // fall through to the error below
+            }
+        }
+        throw cannotConvert(tree, type);
+    }
+
+    private static String string(Object tree, Type type) {
+        if (!(tree instanceof String)) {
+            throw cannotConvert(tree, type);
+        }
+        return (String) tree;
+    }
+
+    private static JSONException cannotConvert(Object tree, Type type) {
+        String value = tree instanceof java.util.Map ? "an object" : tree instanceof java.util.List ? "an array" : "'" + tree + "'";
+        return new JSONException("Cannot deserialize " + value + " as " + type.getName());
+    }
+
+    // datetime reads a Datetime written as an ISO 8601 time with an offset,
+    // such as 2024-01-31T09:30:00.000Z.
+    static Datetime datetime(String s) {
+        try {
+            return new Datetime(OffsetDateTime.parse(s).toInstant());
+        } catch (DateTimeParseException e) {
+            throw new JSONException("Invalid datetime: " + s);
+        }
+    }
+
+    // time reads a Time written as HH:mm:ss.SSSZ.
+    static Time time(String s) {
+        try {
+            return new Time(LocalTime.parse(s.endsWith("Z") ? s.substring(0, s.length() - 1) : s));
+        } catch (DateTimeParseException e) {
+            throw new JSONException("Invalid time: " + s);
+        }
+    }
+
+    static String datetime(Datetime value) {
+        return value.formatGmt(DATETIME_FORMAT);
+    }
+
+    // write returns a tree as JSON. Pretty output is indented like Apex's,
+    // with arrays on one line and objects on several.
+    static String write(Object tree, boolean pretty) {
+        StringBuilder out = new StringBuilder();
+        write(out, tree, pretty, 0);
+        return out.toString();
+    }
+
+    private static void write(StringBuilder out, Object tree, boolean pretty, int indent) {
+        if (tree == null) {
+            out.append("null");
+        } else if (tree instanceof String) {
+            quote(out, (String) tree);
+        } else if (tree instanceof BigDecimal) {
+            out.append(((BigDecimal) tree).toPlainString());
+        } else if (tree instanceof Number || tree instanceof Boolean) {
+            out.append(tree);
+        } else if (tree instanceof java.util.Map) {
+            java.util.Map<?, ?> object = (java.util.Map<?, ?>) tree;
+            if (object.isEmpty()) {
+                out.append(pretty ? "{ }" : "{}");
+                return;
+            }
+            out.append('{');
+            boolean first = true;
+            for (java.util.Map.Entry<?, ?> e : object.entrySet()) {
+                if (!first) {
+                    out.append(',');
+                }
+                first = false;
+                if (pretty) {
+                    newline(out, indent + 1);
+                }
+                quote(out, (String) e.getKey());
+                out.append(pretty ? " : " : ":");
+                write(out, e.getValue(), pretty, indent + 1);
+            }
+            if (pretty) {
+                newline(out, indent);
+            }
+            out.append('}');
+        } else if (tree instanceof java.util.List) {
+            java.util.List<?> array = (java.util.List<?>) tree;
+            if (array.isEmpty()) {
+                out.append(pretty ? "[ ]" : "[]");
+                return;
+            }
+            out.append(pretty ? "[ " : "[");
+            for (int i = 0; i < array.size(); i++) {
+                if (i > 0) {
+                    out.append(pretty ? ", " : ",");
+                }
+                write(out, array.get(i), pretty, indent);
+            }
+            out.append(pretty ? " ]" : "]");
+        } else {
+            throw new JSONException("Cannot serialize " + tree.getClass().getSimpleName());
+        }
+    }
+
+    private static void newline(StringBuilder out, int indent) {
+        out.append('\n');
+        for (int i = 0; i < indent; i++) {
+            out.append("  ");
+        }
+    }
+
+    private static void quote(StringBuilder out, String s) {
+        out.append('"');
+        for (int i = 0; i < s.length(); i++) {
+            char c = s.charAt(i);
+            switch (c) {
+            case '"': out.append("\\\""); break;
+            case '\\': out.append("\\\\"); break;
+            case '\n': out.append("\\n"); break;
+            case '\r': out.append("\\r"); break;
+            case '\t': out.append("\\t"); break;
+            case '\b': out.append("\\b"); break;
+            case '\f': out.append("\\f"); break;
+            default:
+                if (c < 0x20) {
+                    out.append(String.format("\\u%04x", (int) c));
+                } else {
+                    out.append(c);
+                }
+            }
+        }
+        out.append('"');
+    }
+
+    // parse reads JSON text into a tree of the runtime's Map and List.
+    static Object parse(String json) {
+        if (json == null) {
+            throw new JSONException("No content to map due to end-of-input");
+        }
+        Reader reader = new Reader(json);
+        Object value = reader.value();
+        reader.skipSpaces();
+        if (reader.pos < json.length()) {
+            throw reader.error("unexpected character '" + json.charAt(reader.pos) + "'");
+        }
+        return value;
+    }
+
+    private static class Reader {
+        final String json;
+        int pos;
+
+        Reader(String json) {
+            this.json = json;
+        }
+
+        Object value() {
+            skipSpaces();
+            if (pos >= json.length()) {
+                throw new JSONException("No content to map due to end-of-input");
+            }
+            char c = json.charAt(pos);
+            switch (c) {
+            case '{':
+                return object();
+            case '[':
+                return array();
+            case '"':
+                return string();
+            }
+            if (json.startsWith("true", pos)) {
+                pos += 4;
+                return Boolean.TRUE;
+            }
+            if (json.startsWith("false", pos)) {
+                pos += 5;
+                return Boolean.FALSE;
+            }
+            if (json.startsWith("null", pos)) {
+                pos += 4;
+                return null;
+            }
+            if (c == '-' || Character.isDigit(c)) {
+                return number();
+            }
+            throw error("unexpected character '" + c + "'");
+        }
+
+        Map<String, Object> object() {
+            Map<String, Object> object = new Map<String, Object>();
+            pos++;
+            skipSpaces();
+            if (accept('}')) {
+                return object;
+            }
+            do {
+                skipSpaces();
+                if (pos >= json.length() || json.charAt(pos) != '"') {
+                    throw error("expected a field name");
+                }
+                String name = string();
+                skipSpaces();
+                expect(':');
+                object.put(name, value());
+                skipSpaces();
+            } while (accept(','));
+            expect('}');
+            return object;
+        }
+
+        List<Object> array() {
+            List<Object> array = new List<Object>();
+            pos++;
+            skipSpaces();
+            if (accept(']')) {
+                return array;
+            }
+            do {
+                array.add(value());
+                skipSpaces();
+            } while (accept(','));
+            expect(']');
+            return array;
+        }
+
+        String string() {
+            StringBuilder sb = new StringBuilder();
+            for (pos++; pos < json.length(); pos++) {
+                char c = json.charAt(pos);
+                if (c == '"') {
+                    pos++;
+                    return sb.toString();
+                }
+                if (c == '\\' && pos + 1 < json.length()) {
+                    c = json.charAt(++pos);
+                    switch (c) {
+                    case 'n': c = '\n'; break;
+                    case 'r': c = '\r'; break;
+                    case 't': c = '\t'; break;
+                    case 'b': c = '\b'; break;
+                    case 'f': c = '\f'; break;
+                    case 'u':
+                        if (pos + 4 >= json.length()) {
+                            throw error("invalid escape");
+                        }
+                        c = (char) Integer.parseInt(json.substring(pos + 1, pos + 5), 16);
+                        pos += 4;
+                        break;
+                    }
+                }
+                sb.append(c);
+            }
+            throw error("unterminated string");
+        }
+
+        // number reads an integer as an Integer or a Long if it fits, and any
+        // other number as a Decimal.
+        Object number() {
+            int start = pos;
+            if (json.charAt(pos) == '-') {
+                pos++;
+            }
+            while (pos < json.length() && "0123456789.eE+-".indexOf(json.charAt(pos)) >= 0) {
+                pos++;
+            }
+            String text = json.substring(start, pos);
+            try {
+                BigDecimal n = new BigDecimal(text);
+                if (text.indexOf('.') < 0 && text.indexOf('e') < 0 && text.indexOf('E') < 0) {
+                    if (n.compareTo(BigDecimal.valueOf(Integer.MIN_VALUE)) >= 0 && n.compareTo(BigDecimal.valueOf(Integer.MAX_VALUE)) <= 0) {
+                        return n.intValue();
+                    }
+                    if (n.compareTo(BigDecimal.valueOf(Long.MIN_VALUE)) >= 0 && n.compareTo(BigDecimal.valueOf(Long.MAX_VALUE)) <= 0) {
+                        return n.longValue();
+                    }
+                }
+                return n;
+            } catch (NumberFormatException e) {
+                throw error("invalid number " + text);
+            }
+        }
+
+        void skipSpaces() {
+            while (pos < json.length() && Character.isWhitespace(json.charAt(pos))) {
+                pos++;
+            }
+        }
+
+        boolean accept(char c) {
+            if (pos < json.length() && json.charAt(pos) == c) {
+                pos++;
+                return true;
+            }
+            return false;
+        }
+
+        void expect(char c) {
+            if (!accept(c)) {
+                throw error("expected '" + c + "'");
+            }
+        }
+
+        JSONException error(String message) {
+            return new JSONException("Malformed JSON: " + message + " at [" + pos + "]");
+        }
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class JSONException extends Exception {
+    public JSONException() {
+        super();
+    }
+
+    public JSONException(String message) {
+        super(message);
+    }
+
+    public JSONException(Exception cause) {
+        super(cause);
+    }
+
+    public JSONException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,173 @@
+package com.freedom_man.system;
+
+import java.util.ArrayDeque;
+import java.util.ArrayList;
+import java.util.Deque;
+import java.util.LinkedHashMap;
+
+// JSONGenerator builds JSON content incrementally. It keeps the content as
+// a tree, written out by getAsString the way JSON.serialize writes it.
+public class JSONGenerator {
+    private final boolean pretty;
+    private final Deque<Object> containers = new ArrayDeque<Object>();
+    private String fieldName;
+    private Object root;
+    private boolean hasRoot;
+    private boolean closed;
+
+    JSONGenerator(boolean pretty) {
+        this.pretty = pretty;
+    }
+
+    public void writeStartObject() {
+        open(new LinkedHashMap<String, Object>());
+    }
+
+    public void writeEndObject() {
+        close(java.util.Map.class, "object");
+    }
+
+    public void writeStartArray() {
+        open(new ArrayList<Object>());
+    }
+
+    public void writeEndArray() {
+        close(java.util.List.class, "array");
+    }
+
+    public void writeFieldName(String name) {
+        if (!(containers.peek() instanceof java.util.Map) || fieldName != null) {
+            throw new JSONException("Can not write a field name, expecting a value");
+        }
+        fieldName = name;
+    }
+
+    public void writeString(String value) {
+        value(value);
+    }
+
+    public void writeStringField(String name, String value) {
+        writeFieldName(name);
+        writeString(value);
+    }
+
+    public void writeNumber(Number value) {
+        value(value);
+    }
+
+    public void writeNumberField(String name, Number value) {
+        writeFieldName(name);
+        writeNumber(value);
+    }
+
+    public void writeBoolean(Boolean value) {
+        value(value);
+    }
+
+    public void writeBooleanField(String name, Boolean value) {
+        writeFieldName(name);
+        writeBoolean(value);
+    }
+
+    public void writeNull() {
+        value(null);
+    }
+
+    public void writeNullField(String name) {
+        writeFieldName(name);
+        writeNull();
+    }
+
+    public void writeId(String value) {
+        value(value);
+    }
+
+    public void writeIdField(String name, String value) {
+        writeFieldName(name);
+        writeId(value);
+    }
+
+    public void writeDate(Date value) {
+        value(JSON.toTree(value, false));
+    }
+
+    public void writeDateField(String name, Date value) {
+        writeFieldName(name);
+        writeDate(value);
+    }
+
+    public void writeDateTime(Datetime value) {
+        value(JSON.toTree(value, false));
+    }
+
+    public void writeDateTimeField(String name, Datetime value) {
+        writeFieldName(name);
+        writeDateTime(value);
+    }
+
+    public void writeTime(Time value) {
+        value(JSON.toTree(value, false));
+    }
+
+    public void writeTimeField(String name, Time value) {
+        writeFieldName(name);
+        writeTime(value);
+    }
+
+    // writeObject writes a value as JSON.serialize does.
+    public void writeObject(Object value) {
+        value(JSON.toTree(value, false));
+    }
+
+    public void writeObjectField(String name, Object value) {
+        writeFieldName(name);
+        writeObject(value);
+    }
+
+    public String getAsString() {
+        return hasRoot ? JSON.write(root, pretty) : "";
+    }
+
+    public void close() {
+        closed = true;
+    }
+
+    public Boolean isClosed() {
+        return closed;
+    }
+
+    private void open(Object container) {
+        value(container);
+        containers.push(container);
+    }
+
+    @SuppressWarnings("unchecked")
+    private void value(Object value) {
+        if (closed) {
+            throw new JSONException("Generator is closed");
+        }
+        Object parent = containers.peek();
+        if (parent instanceof java.util.Map) {
+            if (fieldName == null) {
+                throw new JSONException("Can not write a value, expecting a field name");
+            }
+            ((java.util.Map<String, Object>) parent).put(fieldName, value);
+            fieldName = null;
+        } else if (parent instanceof java.util.List) {
+            ((java.util.List<Object>) parent).add(value);
+        } else {
+            if (hasRoot) {
+                throw new JSONException("Can not write more than one root value");
+            }
+            root = value;
+            hasRoot = true;
+        }
+    }
+
+    private void close(Class<?> kind, String name) {
+        if (!kind.isInstance(containers.peek()) || fieldName != null) {
+            throw new JSONException("Current context not an " + name);
+        }
+        containers.pop();
+    }
+}
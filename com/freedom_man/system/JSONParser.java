@@ -0,0 +1,221 @@
+package com.freedom_man.system;
+
+import java.math.BigDecimal;
+import java.util.ArrayList;
+
+// JSONParser reads JSON content token by token. The content is read into a
+// tree up front and flattened into its tokens, so that readValueAs can
+// convert the value at the current token like JSON.deserialize does.
+public class JSONParser {
+    private static class Token {
+        JSONToken kind;
+        // name is the field name of the value, or of the field for FIELD_NAME.
+        String name;
+        Object value;
+        // end is the index of the token closing an object or array, or of
+        // the token itself.
+        int end;
+    }
+
+    private final java.util.List<Token> tokens = new ArrayList<Token>();
+    private int pos = -1;
+    private Token current;
+    private JSONToken lastCleared;
+
+    JSONParser(String json) {
+        flatten(JSON.parse(json), null);
+    }
+
+    private void flatten(Object value, String name) {
+        Token t = new Token();
+        t.name = name;
+        t.value = value;
+        tokens.add(t);
+        if (value instanceof java.util.Map) {
+            t.kind = JSONToken.START_OBJECT;
+            for (java.util.Map.Entry<?, ?> e : ((java.util.Map<?, ?>) value).entrySet()) {
+                Token field = new Token();
+                field.kind = JSONToken.FIELD_NAME;
+                field.name = (String) e.getKey();
+                field.value = field.name;
+                field.end = tokens.size();
+                tokens.add(field);
+                flatten(e.getValue(), field.name);
+            }
+            close(JSONToken.END_OBJECT, name);
+        } else if (value instanceof java.util.List) {
+            t.kind = JSONToken.START_ARRAY;
+            for (Object item : (java.util.List<?>) value) {
+                flatten(item, null);
+            }
+            close(JSONToken.END_ARRAY, name);
+        } else if (value == null) {
+            t.kind = JSONToken.VALUE_NULL;
+        } else if (value instanceof String) {
+            t.kind = JSONToken.VALUE_STRING;
+        } else if (value instanceof Boolean) {
+            t.kind = (Boolean) value ? JSONToken.VALUE_TRUE : JSONToken.VALUE_FALSE;
+        } else if (value instanceof BigDecimal) {
+            t.kind = JSONToken.VALUE_NUMBER_FLOAT;
+        } else {
+            t.kind = JSONToken.VALUE_NUMBER_INT;
+        }
+        t.end = tokens.size() - 1;
+    }
+
+    private void close(JSONToken kind, String name) {
+        Token t = new Token();
+        t.kind = kind;
+        t.name = name;
+        t.end = tokens.size();
+        tokens.add(t);
+    }
+
+    // nextToken moves to the next token and returns it, or null at the end
+    // of the content.
+    public JSONToken nextToken() {
+        if (pos + 1 >= tokens.size()) {
+            pos = tokens.size();
+            current = null;
+            return null;
+        }
+        current = tokens.get(++pos);
+        return current.kind;
+    }
+
+    // nextValue moves to the next token that is not a field name.
+    public JSONToken nextValue() {
+        JSONToken t = nextToken();
+        while (t == JSONToken.FIELD_NAME) {
+            t = nextToken();
+        }
+        return t;
+    }
+
+    public JSONToken getCurrentToken() {
+        return current == null ? null : current.kind;
+    }
+
+    public Boolean hasCurrentToken() {
+        return current != null;
+    }
+
+    public void clearCurrentToken() {
+        if (current != null) {
+            lastCleared = current.kind;
+            current = null;
+        }
+    }
+
+    public JSONToken getLastClearedToken() {
+        return lastCleared;
+    }
+
+    public String getCurrentName() {
+        return current == null ? null : current.name;
+    }
+
+    public String getText() {
+        if (current == null) {
+            return null;
+        }
+        switch (current.kind) {
+        case START_OBJECT:
+            return "{";
+        case END_OBJECT:
+            return "}";
+        case START_ARRAY:
+            return "[";
+        case END_ARRAY:
+            return "]";
+        case FIELD_NAME:
+            return current.name;
+        default:
+            return ApexOperator.toString(current.value);
+        }
+    }
+
+    public Integer getIntegerValue() {
+        return number().intValue();
+    }
+
+    public Long getLongValue() {
+        return number().longValue();
+    }
+
+    public Double getDoubleValue() {
+        return number().doubleValue();
+    }
+
+    public BigDecimal getDecimalValue() {
+        return ApexOperator.toDecimal(number());
+    }
+
+    public Boolean getBooleanValue() {
+        if (current == null || (current.kind != JSONToken.VALUE_TRUE && current.kind != JSONToken.VALUE_FALSE)) {
+            throw new JSONException("Current token (" + getCurrentToken() + ") not of boolean type");
+        }
+        return (Boolean) current.value;
+    }
+
+    public Date getDateValue() {
+        return Date.valueOf(text());
+    }
+
+    public Datetime getDatetimeValue() {
+        return JSON.datetime(text());
+    }
+
+    public Time getTimeValue() {
+        return JSON.time(text());
+    }
+
+    public String getIdValue() {
+        return text();
+    }
+
+    // skipChildren moves to the end of the object or array starting at the
+    // current token.
+    public void skipChildren() {
+        if (current != null && current.end != pos) {
+            pos = current.end;
+            current = tokens.get(pos);
+        }
+    }
+
+    // readValueAs reads the value at the current token, or at the next
+    // one, as the type, and moves to its last token.
+    public Object readValueAs(Type type) {
+        return read(type, false);
+    }
+
+    public Object readValueAsStrict(Type type) {
+        return read(type, true);
+    }
+
+    private Object read(Type type, boolean strict) {
+        if (current == null || current.kind == JSONToken.FIELD_NAME) {
+            nextToken();
+        }
+        if (current == null) {
+            throw new JSONException("No content to map due to end-of-input");
+        }
+        Object value = JSON.convert(current.value, type, strict);
+        skipChildren();
+        return value;
+    }
+
+    private Number number() {
+        if (current == null || (current.kind != JSONToken.VALUE_NUMBER_INT && current.kind != JSONToken.VALUE_NUMBER_FLOAT)) {
+            throw new JSONException("Current token (" + getCurrentToken() + ") not numeric, can not use numeric value accessors");
+        }
+        return (Number) current.value;
+    }
+
+    private String text() {
+        if (current == null || current.kind != JSONToken.VALUE_STRING) {
+            throw new JSONException("Current token (" + getCurrentToken() + ") not a string");
+        }
+        return (String) current.value;
+    }
+}
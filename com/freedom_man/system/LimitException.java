@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class LimitException extends Exception {
+    public LimitException() {
+        super();
+    }
+
+    public LimitException(String message) {
+        super(message);
+    }
+
+    public LimitException(Exception cause) {
+        super(cause);
+    }
+
+    public LimitException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,223 @@
+package com.freedom_man.system;
+
+import java.lang.management.ManagementFactory;
+import java.lang.management.ThreadMXBean;
+import java.util.ArrayDeque;
+import java.util.Deque;
+
+// Limits is the Apex Limits class. The runtime counts the queries, DML,
+// callouts and jobs of each transaction and, as Apex does, throws
+// LimitException on the operation that exceeds a governor limit. Setting
+// the apex.limits system property to "count" only counts them.
+//
+// A transaction starts with the program, each test and Test.startTest,
+// which gets a fresh set of limits until Test.stopTest, and each
+// asynchronous job, which gets the higher asynchronous limits. CPU time is
+// the CPU time of the thread. Heap size is the growth of the used JVM heap,
+// which is only reported, since garbage collection makes it too unsteady
+// to enforce.
+public class Limits {
+    private static final ThreadMXBean THREADS = ManagementFactory.getThreadMXBean();
+
+    private static class Transaction {
+        final boolean async;
+        final long cpuStart = cpuTime();
+        final long heapStart = usedHeap();
+        int queries;
+        int queryRows;
+        int queryLocatorRows;
+        int dmlStatements;
+        int dmlRows;
+        int callouts;
+        int futureCalls;
+        int queueableJobs;
+
+        Transaction(boolean async) {
+            this.async = async;
+        }
+    }
+
+    private static final Deque<Transaction> transactions = new ArrayDeque<Transaction>();
+
+    static {
+        transactions.push(new Transaction(false));
+    }
+
+    private static Transaction current() {
+        return transactions.peek();
+    }
+
+    // begin starts a transaction, which end ends, returning to the limits
+    // of the previous one.
+    static void begin(boolean async) {
+        transactions.push(new Transaction(async));
+    }
+
+    static void end() {
+        if (transactions.size() > 1) {
+            transactions.pop();
+        }
+    }
+
+    // reset discards the transactions of the previous test.
+    static void reset() {
+        transactions.clear();
+        transactions.push(new Transaction(false));
+    }
+
+    private static boolean enforced() {
+        return !"count".equals(java.lang.System.getProperty("apex.limits"));
+    }
+
+    private static void check(int used, int limit, String message) {
+        if (used > limit && enforced()) {
+            throw new LimitException(message + ": " + used);
+        }
+    }
+
+    private static void checkCpuTime() {
+        if (getCpuTime() > getLimitCpuTime() && enforced()) {
+            throw new LimitException("Apex CPU time limit exceeded");
+        }
+    }
+
+    // query counts a SOQL query returning rows records.
+    static void query(int rows) {
+        Transaction t = current();
+        t.queries++;
+        check(t.queries, getLimitQueries(), "Too many SOQL queries");
+        t.queryRows += rows;
+        check(t.queryRows, getLimitQueryRows(), "Too many query rows");
+        checkCpuTime();
+    }
+
+    // queryLocator counts the query of a QueryLocator, whose rows are
+    // limited separately. The start method of a batch job is not limited.
+    static void queryLocator(int rows) {
+        Transaction t = current();
+        t.queries++;
+        check(t.queries, getLimitQueries(), "Too many SOQL queries");
+        if (!System.batch) {
+            t.queryLocatorRows += rows;
+            check(t.queryLocatorRows, getLimitQueryLocatorRows(), "Too many query locator rows");
+        }
+        checkCpuTime();
+    }
+
+    // dml counts a DML statement on rows records.
+    static void dml(int rows) {
+        Transaction t = current();
+        t.dmlStatements++;
+        check(t.dmlStatements, getLimitDmlStatements(), "Too many DML statements");
+        t.dmlRows += rows;
+        check(t.dmlRows, getLimitDmlRows(), "Too many DML rows");
+        checkCpuTime();
+    }
+
+    static void callout() {
+        Transaction t = current();
+        t.callouts++;
+        check(t.callouts, getLimitCallouts(), "Too many callouts");
+    }
+
+    static void futureCall() {
+        Transaction t = current();
+        t.futureCalls++;
+        check(t.futureCalls, getLimitFutureCalls(), "Too many future calls");
+    }
+
+    static void queueableJob() {
+        Transaction t = current();
+        t.queueableJobs++;
+        check(t.queueableJobs, getLimitQueueableJobs(), "Too many queueable jobs added to the queue");
+    }
+
+    private static long cpuTime() {
+        return THREADS.isCurrentThreadCpuTimeSupported() ? THREADS.getCurrentThreadCpuTime() / 1000000 : 0;
+    }
+
+    private static long usedHeap() {
+        Runtime runtime = Runtime.getRuntime();
+        return runtime.totalMemory() - runtime.freeMemory();
+    }
+
+    public static Integer getQueries() {
+        return current().queries;
+    }
+
+    public static Integer getLimitQueries() {
+        return current().async ? 200 : 100;
+    }
+
+    public static Integer getQueryRows() {
+        return current().queryRows;
+    }
+
+    public static Integer getLimitQueryRows() {
+        return 50000;
+    }
+
+    public static Integer getQueryLocatorRows() {
+        return current().queryLocatorRows;
+    }
+
+    public static Integer getLimitQueryLocatorRows() {
+        return 10000;
+    }
+
+    public static Integer getDmlStatements() {
+        return current().dmlStatements;
+    }
+
+    public static Integer getLimitDmlStatements() {
+        return 150;
+    }
+
+    public static Integer getDmlRows() {
+        return current().dmlRows;
+    }
+
+    public static Integer getLimitDmlRows() {
+        return 10000;
+    }
+
+    public static Integer getCallouts() {
+        return current().callouts;
+    }
+
+    public static Integer getLimitCallouts() {
+        return 100;
+    }
+
+    public static Integer getFutureCalls() {
+        return current().futureCalls;
+    }
+
+    public static Integer getLimitFutureCalls() {
+        return 50;
+    }
+
+    public static Integer getQueueableJobs() {
+        return current().queueableJobs;
+    }
+
+    public static Integer getLimitQueueableJobs() {
+        return 50;
+    }
+
+    public static Integer getCpuTime() {
+        return (int) (cpuTime() - current().cpuStart);
+    }
+
+    public static Integer getLimitCpuTime() {
+        return current().async ? 60000 : 10000;
+    }
+
+    public static Integer getHeapSize() {
+        return (int) Math.max(0, usedHeap() - current().heapStart);
+    }
+
+    public static Integer getLimitHeapSize() {
+        return current().async ? 12000000 : 6000000;
+    }
+}
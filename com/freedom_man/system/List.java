@@ -1,4 +1,74 @@
 package com.freedom_man.system;
 
+import java.util.Collection;
+import java.util.ConcurrentModificationException;
+import java.util.Iterator;
+
+// List is the Apex List. Out of range indexes throw ListException, and
+// modifying a list while iterating it throws FinalException, as in Apex.
 public class List<T> extends java.util.ArrayList<T> {
+    public List() {
+        super();
+    }
+
+    public List(Collection<? extends T> values) {
+        super(values);
+    }
+
+    @Override
+    public T get(int index) {
+        checkIndex(index, size());
+        return super.get(index);
+    }
+
+    @Override
+    public T set(int index, T value) {
+        checkIndex(index, size());
+        return super.set(index, value);
+    }
+
+    @Override
+    public void add(int index, T value) {
+        checkIndex(index, size() + 1);
+        super.add(index, value);
+    }
+
+    @Override
+    public T remove(int index) {
+        checkIndex(index, size());
+        return super.remove(index);
+    }
+
+    @Override
+    public Iterator<T> iterator() {
+        final Iterator<T> iterator = super.iterator();
+        return new Iterator<T>() {
+            @Override
+            public boolean hasNext() {
+                return iterator.hasNext();
+            }
+
+            @Override
+            public T next() {
+                try {
+                    return iterator.next();
+                } catch (ConcurrentModificationException e) {
+                    throw Exception.translate(e);
+                } catch (java.util.NoSuchElementException e) {
+                    throw new NoSuchElementException("Iterator has no more elements.");
+                }
+            }
+
+            @Override
+            public void remove() {
+                iterator.remove();
+            }
+        };
+    }
+
+    private static void checkIndex(int index, int size) {
+        if (index < 0 || index >= size) {
+            throw new ListException("List index out of bounds: " + index);
+        }
+    }
 }
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class ListException extends Exception {
+    public ListException() {
+        super();
+    }
+
+    public ListException(String message) {
+        super(message);
+    }
+
+    public ListException(Exception cause) {
+        super(cause);
+    }
+
+    public ListException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,15 @@
+package com.freedom_man.system;
+
+// LoggingLevel is the level of a System.debug message, from the least to
+// the most verbose.
+public enum LoggingLevel {
+    NONE,
+    ERROR,
+    WARN,
+    INFO,
+    DEBUG,
+    FINE,
+    FINER,
+    FINEST,
+    INTERNAL
+}
@@ -0,0 +1,25 @@
+package com.freedom_man.system;
+
+import java.util.LinkedHashSet;
+
+// Map is the Apex Map. keySet and values return copies, as in Apex, so the
+// map can be modified while iterating them.
+public class Map<K, V> extends java.util.LinkedHashMap<K, V> {
+    public Map() {
+        super();
+    }
+
+    public Map(java.util.Map<? extends K, ? extends V> values) {
+        super(values);
+    }
+
+    @Override
+    public java.util.Set<K> keySet() {
+        return new LinkedHashSet<K>(super.keySet());
+    }
+
+    @Override
+    public List<V> values() {
+        return new List<V>(super.values());
+    }
+}
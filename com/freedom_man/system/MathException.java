@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class MathException extends Exception {
+    public MathException() {
+        super();
+    }
+
+    public MathException(String message) {
+        super(message);
+    }
+
+    public MathException(Exception cause) {
+        super(cause);
+    }
+
+    public MathException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,93 @@
+package com.freedom_man.system;
+
+import java.io.IOException;
+import java.io.InputStream;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.nio.file.Paths;
+import java.util.Base64;
+import java.util.Properties;
+
+// NamedCredentials resolves callout:Name/path endpoints with the named
+// credentials of a properties file, named by the apex.namedCredentials
+// system property or else named-credentials.properties in the working
+// directory:
+//
+//     Stub.url=http://localhost:8080/api
+//     Stub.username=user
+//     Stub.password=secret
+//
+// A credential with a username sends basic authentication unless the
+// request sets an Authorization header, and {!$Credential.UserName} and
+// {!$Credential.Password} in headers and the body are replaced, as in Apex.
+class NamedCredentials {
+    private static final String PREFIX = "callout:";
+
+    private final String url;
+    private final String username;
+    private final String password;
+
+    private NamedCredentials(String url, String username, String password) {
+        this.url = url;
+        this.username = username;
+        this.password = password;
+    }
+
+    // resolve returns the request to send for request, which is the
+    // request itself unless its endpoint names a credential.
+    static HttpRequest resolve(HttpRequest request) {
+        String endpoint = request.getEndpoint();
+        if (endpoint == null || !endpoint.startsWith(PREFIX)) {
+            return request;
+        }
+        String rest = endpoint.substring(PREFIX.length());
+        int end = rest.length();
+        for (char c : new char[]{'/', '?'}) {
+            int i = rest.indexOf(c);
+            if (i >= 0 && i < end) {
+                end = i;
+            }
+        }
+        NamedCredentials credential = find(rest.substring(0, end));
+        HttpRequest resolved = new HttpRequest();
+        resolved.setEndpoint(credential.url.replaceAll("/+$", "") + rest.substring(end));
+        resolved.setMethod(request.getMethod());
+        resolved.setTimeout(request.getTimeout());
+        resolved.setCompressed(request.getCompressed());
+        resolved.setBody(credential.merge(request.getBody()));
+        for (java.util.Map.Entry<String, String> header : request.headers.entrySet()) {
+            resolved.setHeader(header.getKey(), credential.merge(header.getValue()));
+        }
+        if (credential.username != null && resolved.getHeader("Authorization") == null) {
+            String token = credential.username + ":" + (credential.password == null ? "" : credential.password);
+            resolved.setHeader("Authorization", "Basic " + Base64.getEncoder().encodeToString(token.getBytes(StandardCharsets.UTF_8)));
+        }
+        return resolved;
+    }
+
+    private String merge(String s) {
+        if (s == null) {
+            return null;
+        }
+        return s.replace("{!$Credential.UserName}", username == null ? "" : username)
+                .replace("{!$Credential.Password}", password == null ? "" : password);
+    }
+
+    private static NamedCredentials find(String name) {
+        Path path = Paths.get(java.lang.System.getProperty("apex.namedCredentials", "named-credentials.properties"));
+        Properties properties = new Properties();
+        if (Files.exists(path)) {
+            try (InputStream in = Files.newInputStream(path)) {
+                properties.load(in);
+            } catch (IOException e) {
+                throw new CalloutException("Unable to read named credentials from " + path + ": " + e.getMessage());
+            }
+        }
+        String url = properties.getProperty(name + ".url");
+        if (url == null) {
+            throw new CalloutException("Unable to find the named credential: " + name);
+        }
+        return new NamedCredentials(url, properties.getProperty(name + ".username"), properties.getProperty(name + ".password"));
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class NoAccessException extends Exception {
+    public NoAccessException() {
+        super();
+    }
+
+    public NoAccessException(String message) {
+        super(message);
+    }
+
+    public NoAccessException(Exception cause) {
+        super(cause);
+    }
+
+    public NoAccessException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class NoDataFoundException extends Exception {
+    public NoDataFoundException() {
+        super();
+    }
+
+    public NoDataFoundException(String message) {
+        super(message);
+    }
+
+    public NoDataFoundException(Exception cause) {
+        super(cause);
+    }
+
+    public NoDataFoundException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
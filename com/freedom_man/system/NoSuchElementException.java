@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class NoSuchElementException extends Exception {
+    public NoSuchElementException() {
+        super();
+    }
+
+    public NoSuchElementException(String message) {
+        super(message);
+    }
+
+    public NoSuchElementException(Exception cause) {
+        super(cause);
+    }
+
+    public NoSuchElementException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
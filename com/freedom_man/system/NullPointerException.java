@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class NullPointerException extends Exception {
+    public NullPointerException() {
+        super("Attempt to de-reference a null object");
+    }
+
+    public NullPointerException(String message) {
+        super(message);
+    }
+
+    public NullPointerException(Exception cause) {
+        super(cause);
+    }
+
+    public NullPointerException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class QueryException extends Exception {
+    public QueryException() {
+        super();
+    }
+
+    public QueryException(String message) {
+        super(message);
+    }
+
+    public QueryException(Exception cause) {
+        super(cause);
+    }
+
+    public QueryException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
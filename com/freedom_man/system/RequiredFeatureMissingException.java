@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class RequiredFeatureMissingException extends Exception {
+    public RequiredFeatureMissingException() {
+        super();
+    }
+
+    public RequiredFeatureMissingException(String message) {
+        super(message);
+    }
+
+    public RequiredFeatureMissingException(Exception cause) {
+        super(cause);
+    }
+
+    public RequiredFeatureMissingException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,14 @@
+package com.freedom_man.system;
+
+// RuntimeVersion is the version of apex2java this runtime ships with.
+// Converted classes check it when they are loaded, so that code converted
+// by one version does not silently run on the runtime of another.
+public class RuntimeVersion {
+    public static final String VERSION = "0.1.0";
+
+    public static void check(String version) {
+        if (!VERSION.equals(version)) {
+            throw new java.lang.IllegalStateException("code converted by apex2java " + version + " cannot run on runtime " + VERSION);
+        }
+    }
+}
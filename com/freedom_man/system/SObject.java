@@ -1,5 +1,301 @@
 package com.freedom_man.system;
 
-public abstract class SObject {
+import java.lang.reflect.Field;
+import java.lang.reflect.Modifier;
+import java.util.TreeMap;
+import java.util.concurrent.ConcurrentHashMap;
+
+// SObject is the base of the sObject classes, which are generated from the
+// sObject metadata. The typed fields hold the values of the fields of the
+// metadata, and a field map holds the values of the others, such as custom
+// fields the metadata lacks; get and put reach both by name, ignoring case.
+// Related records, set by putSObject, are kept in a map by relationship
+// name.
+public abstract class SObject implements Cloneable {
+    private static final java.util.Map<Class<?>, java.util.Map<String, Field>> typedFields = new ConcurrentHashMap<Class<?>, java.util.Map<String, Field>>();
+
     public String type;
+    public String Id;
+    public Datetime CreatedDate;
+    public Datetime LastModifiedDate;
+
+    private transient TreeMap<String, Object> fields = new TreeMap<String, Object>(String.CASE_INSENSITIVE_ORDER);
+    private transient java.util.Map<String, Object> relationships = new java.util.LinkedHashMap<String, Object>();
+    // assigned holds the names of the fields set by put since the record was
+    // last read from or written to the data store, and saved the values its
+    // typed fields had then, so that update can tell the fields the code set,
+    // to null as well, from the ones it did not touch.
+    private transient java.util.Set<String> assigned = new java.util.TreeSet<String>(String.CASE_INSENSITIVE_ORDER);
+    private transient java.util.Map<String, Object> saved = new java.util.HashMap<String, Object>();
+
+    public Schema.SObjectType getSObjectType() {
+        return Schema.getSObjectType(getClass());
+    }
+
+    // typedField returns the typed field of the sObject field name, or null
+    // if it has none.
+    private Field typedField(String name) {
+        return typedFields.computeIfAbsent(getClass(), SObject::typedFields).get(name.toLowerCase());
+    }
+
+    private static java.util.Map<String, Field> typedFields(Class<?> c) {
+        java.util.Map<String, Field> fields = new java.util.HashMap<String, Field>();
+        for (Field f : c.getFields()) {
+            if (Modifier.isStatic(f.getModifiers()) || (f.getDeclaringClass() == SObject.class && f.getName().equals("type"))) {
+                continue;
+            }
+            fields.put(f.getName().toLowerCase(), f);
+        }
+        return fields;
+    }
+
+    // getField returns the value of the field, from its typed field or the
+    // field map.
+    Object getField(String name) {
+        Field f = typedField(name);
+        if (f == null) {
+            return fields.get(name);
+        }
+        try {
+            return f.get(this);
+        } catch (IllegalAccessException e) {
+            throw new UnexpectedException(e.getMessage());
+        }
+    }
+
+    void setField(String name, Object value) {
+        assigned.add(name);
+        Field f = typedField(name);
+        if (f == null) {
+            fields.put(name, value);
+            return;
+        }
+        if (value != null && !f.getType().isInstance(value)) {
+            throw new SObjectException("Illegal assignment from " + value.getClass().getSimpleName() + " to field " + f.getName());
+        }
+        try {
+            f.set(this, value);
+        } catch (IllegalAccessException e) {
+            throw new UnexpectedException(e.getMessage());
+        }
+    }
+
+    // undeclaredFields returns the field map, for JSON.
+    java.util.Map<String, Object> undeclaredFields() {
+        return fields;
+    }
+
+    public Object get(String fieldName) {
+        return getField(fieldName);
+    }
+
+    public Object get(Schema.SObjectField field) {
+        return get(field.getDescribe().getName());
+    }
+
+    // put sets the field and returns its previous value.
+    public Object put(String fieldName, Object value) {
+        Object previous = getField(fieldName);
+        setField(fieldName, value);
+        return previous;
+    }
+
+    public Object put(Schema.SObjectField field, Object value) {
+        return put(field.getDescribe().getName(), value);
+    }
+
+    public SObject getSObject(String relationshipName) {
+        Object value = relationships.get(relationshipName.toLowerCase());
+        if (value != null && !(value instanceof SObject)) {
+            throw new SObjectException("Invalid relationship " + relationshipName + " for " + DataStore.typeName(this));
+        }
+        return (SObject) value;
+    }
+
+    // putSObject sets the related record and returns the previous one.
+    public SObject putSObject(String relationshipName, SObject value) {
+        SObject previous = getSObject(relationshipName);
+        relationships.put(relationshipName.toLowerCase(), value);
+        return previous;
+    }
+
+    // getSObjects returns the related records of a child relationship, or
+    // null if there are none.
+    @SuppressWarnings("unchecked")
+    public List<SObject> getSObjects(String relationshipName) {
+        Object value = relationships.get(relationshipName.toLowerCase());
+        if (value != null && !(value instanceof List)) {
+            throw new SObjectException("Invalid relationship " + relationshipName + " for " + DataStore.typeName(this));
+        }
+        return (List<SObject>) value;
+    }
+
+    // putSObjects sets the related records of a child relationship, which
+    // Apex sets from subqueries.
+    void putSObjects(String relationshipName, List<SObject> values) {
+        relationships.put(relationshipName.toLowerCase(), values);
+    }
+
+    // value returns a field or a related record, for queries.
+    Object value(String name) {
+        if (typedField(name) == null && relationships.containsKey(name.toLowerCase())) {
+            return relationships.get(name.toLowerCase());
+        }
+        return getField(name);
+    }
+
+    // equals compares records field by field, as Apex == and the sets and
+    // maps of records do: records of the same type are equal if their
+    // fields have equal values. Related records are not compared.
+    @Override
+    public boolean equals(Object o) {
+        if (o == this) {
+            return true;
+        }
+        return o != null && o.getClass() == getClass() && fieldValues().equals(((SObject) o).fieldValues());
+    }
+
+    @Override
+    public int hashCode() {
+        return fieldValues().hashCode();
+    }
+
+    // fieldValues returns the values of the fields that have one, by
+    // lowercased name.
+    private java.util.Map<String, Object> fieldValues() {
+        java.util.Map<String, Object> values = new java.util.HashMap<String, Object>();
+        for (String name : typedFields.computeIfAbsent(getClass(), SObject::typedFields).keySet()) {
+            Object value = getField(name);
+            if (value != null) {
+                values.put(name, value);
+            }
+        }
+        for (java.util.Map.Entry<String, Object> e : fields.entrySet()) {
+            if (e.getValue() != null) {
+                values.put(e.getKey().toLowerCase(), e.getValue());
+            }
+        }
+        return values;
+    }
+
+    // getPopulatedFieldsAsMap returns the fields that have a value and the
+    // related records, by name.
+    public Map<String, Object> getPopulatedFieldsAsMap() {
+        Map<String, Object> populated = new Map<String, Object>();
+        for (Schema.SObjectField field : getSObjectType().getDescribe().fields.getMap().values()) {
+            String name = field.getDescribe().getName();
+            Object value = getField(name);
+            if (value != null) {
+                populated.put(name, value);
+            }
+        }
+        for (java.util.Map.Entry<String, Object> e : fields.entrySet()) {
+            if (e.getValue() != null) {
+                populated.put(e.getKey(), e.getValue());
+            }
+        }
+        for (java.util.Map.Entry<String, Object> e : relationships.entrySet()) {
+            if (e.getValue() != null) {
+                populated.put(e.getKey(), e.getValue());
+            }
+        }
+        return populated;
+    }
+
+    // changedFields returns the fields set since the record was last read
+    // from or written to the data store, including the ones set to null, by
+    // name. Related records are not fields.
+    java.util.Map<String, Object> changedFields() {
+        java.util.Map<String, Object> changed = new java.util.LinkedHashMap<String, Object>();
+        for (java.util.Map.Entry<String, Field> e : typedFields.computeIfAbsent(getClass(), SObject::typedFields).entrySet()) {
+            Object value = getField(e.getKey());
+            if (assigned.contains(e.getKey()) || !java.util.Objects.equals(value, saved.get(e.getKey()))) {
+                changed.put(e.getValue().getName(), value);
+            }
+        }
+        for (java.util.Map.Entry<String, Object> e : fields.entrySet()) {
+            if (assigned.contains(e.getKey())) {
+                changed.put(e.getKey(), e.getValue());
+            }
+        }
+        return changed;
+    }
+
+    // markSaved records the values of the fields as the ones in the data
+    // store.
+    void markSaved() {
+        assigned.clear();
+        saved.clear();
+        for (String name : typedFields.computeIfAbsent(getClass(), SObject::typedFields).keySet()) {
+            saved.put(name, getField(name));
+        }
+    }
+
+    // clone overrides Object.clone with Apex's SObject.clone. Generated
+    // classes override its overloads to return their own type.
+    @Override
+    public SObject clone() {
+        return clone(false, false, false, false);
+    }
+
+    public SObject clone(Boolean preserveId) {
+        return clone(preserveId, false, false, false);
+    }
+
+    public SObject clone(Boolean preserveId, Boolean isDeepClone) {
+        return clone(preserveId, isDeepClone, false, false);
+    }
+
+    public SObject clone(Boolean preserveId, Boolean isDeepClone, Boolean preserveReadonlyTimestamps) {
+        return clone(preserveId, isDeepClone, preserveReadonlyTimestamps, false);
+    }
+
+    // clone copies the record. A deep clone copies the related records as
+    // well. There are no auto-number fields, so preserveAutonumber has no
+    // effect.
+    public SObject clone(Boolean preserveId, Boolean isDeepClone, Boolean preserveReadonlyTimestamps, Boolean preserveAutonumber) {
+        SObject c = copy();
+        if (!Boolean.TRUE.equals(preserveId)) {
+            c.Id = null;
+        }
+        if (!Boolean.TRUE.equals(preserveReadonlyTimestamps)) {
+            c.CreatedDate = null;
+            c.LastModifiedDate = null;
+        }
+        if (Boolean.TRUE.equals(isDeepClone)) {
+            for (java.util.Map.Entry<String, Object> e : c.relationships.entrySet()) {
+                e.setValue(deepClone(e.getValue(), preserveId, preserveReadonlyTimestamps));
+            }
+        }
+        return c;
+    }
+
+    private static Object deepClone(Object value, Boolean preserveId, Boolean preserveReadonlyTimestamps) {
+        if (value instanceof SObject) {
+            return ((SObject) value).clone(preserveId, true, preserveReadonlyTimestamps, false);
+        }
+        if (value instanceof List) {
+            List<Object> list = new List<Object>();
+            for (Object item : (List<?>) value) {
+                list.add(deepClone(item, preserveId, preserveReadonlyTimestamps));
+            }
+            return list;
+        }
+        return value;
+    }
+
+    // copy returns a shallow copy, used by the data store to keep records
+    // independent from the instances the code under test holds.
+    public SObject copy() {
+        try {
+            SObject c = (SObject) super.clone();
+            c.fields = new TreeMap<String, Object>(fields);
+            c.relationships = new java.util.LinkedHashMap<String, Object>(relationships);
+            c.assigned = new java.util.TreeSet<String>(assigned);
+            c.saved = new java.util.HashMap<String, Object>(saved);
+            return c;
+        } catch (CloneNotSupportedException e) {
+            throw new UnexpectedException(e.getMessage());
+        }
+    }
 }
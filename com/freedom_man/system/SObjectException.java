@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class SObjectException extends Exception {
+    public SObjectException() {
+        super();
+    }
+
+    public SObjectException(String message) {
+        super(message);
+    }
+
+    public SObjectException(Exception cause) {
+        super(cause);
+    }
+
+    public SObjectException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,5 @@
+package com.freedom_man.system;
+
+public interface Schedulable {
+    void execute(SchedulableContext context);
+}
@@ -0,0 +1,439 @@
+package com.freedom_man.system;
+
+// Schema is the Apex Schema namespace for describing sObjects. The
+// describes are defined by SchemaMetadata, which is generated from the
+// sObject metadata of apex2java. Converted code gets the SObjectType of
+// Account.SObjectType from getSObjectType and the SObjectField of
+// Account.Name from getSObjectField.
+public class Schema {
+    public enum DisplayType {
+        BOOLEAN, CURRENCY, DATE, DATETIME, DOUBLE, EMAIL, ID, INTEGER, LONG, PERCENT, PHONE, PICKLIST, REFERENCE, STRING, TEXTAREA, TIME, URL
+    }
+
+    // names maps the sObject names, lowercased, to their types.
+    private static final CaseInsensitiveMap<SObjectType> names = new CaseInsensitiveMap<SObjectType>();
+    private static final java.util.Map<Class<?>, SObjectType> types = new java.util.HashMap<Class<?>, SObjectType>();
+
+    static {
+        SchemaMetadata.load();
+    }
+
+    // CaseInsensitiveMap is a map with lowercased keys, as the maps of
+    // names Schema returns are in Apex.
+    static class CaseInsensitiveMap<V> extends Map<String, V> {
+        private static Object lower(Object key) {
+            return key instanceof String ? ((String) key).toLowerCase() : key;
+        }
+
+        @Override
+        public V put(String key, V value) {
+            return super.put((String) lower(key), value);
+        }
+
+        @Override
+        public V get(Object key) {
+            return super.get(lower(key));
+        }
+
+        @Override
+        public boolean containsKey(Object key) {
+            return super.containsKey(lower(key));
+        }
+
+        @Override
+        public V remove(Object key) {
+            return super.remove(lower(key));
+        }
+    }
+
+    public static class SObjectType {
+        private final Class<? extends SObject> type;
+        private final DescribeSObjectResult describe;
+
+        private SObjectType(Class<? extends SObject> type, DescribeSObjectResult describe) {
+            this.type = type;
+            this.describe = describe;
+        }
+
+        public DescribeSObjectResult getDescribe() {
+            return describe;
+        }
+
+        public SObject newSObject() {
+            try {
+                return type.getDeclaredConstructor().newInstance();
+            } catch (ReflectiveOperationException e) {
+                throw new SObjectException("Cannot create " + describe.getName() + ": " + e);
+            }
+        }
+
+        public SObject newSObject(String id) {
+            SObject record = newSObject();
+            record.Id = id;
+            return record;
+        }
+
+        // newSObject sets the default values of the fields if loadDefaults
+        // is true. Record types are not stored on the records.
+        public SObject newSObject(String recordTypeId, Boolean loadDefaults) {
+            SObject record = newSObject();
+            if (Boolean.TRUE.equals(loadDefaults)) {
+                for (SObjectField field : describe.fields.getMap().values()) {
+                    Object value = field.describe.getDefaultValue();
+                    if (value != null) {
+                        record.put(field.describe.getName(), value);
+                    }
+                }
+            }
+            return record;
+        }
+
+        @Override
+        public String toString() {
+            return describe.getName();
+        }
+    }
+
+    public static class SObjectField {
+        private final DescribeFieldResult describe;
+
+        private SObjectField(DescribeFieldResult describe) {
+            this.describe = describe;
+        }
+
+        public DescribeFieldResult getDescribe() {
+            return describe;
+        }
+
+        @Override
+        public String toString() {
+            return describe.getName();
+        }
+    }
+
+    // Fields is the fields member of a DescribeSObjectResult.
+    public static class Fields {
+        private final CaseInsensitiveMap<SObjectField> fields = new CaseInsensitiveMap<SObjectField>();
+
+        public Map<String, SObjectField> getMap() {
+            CaseInsensitiveMap<SObjectField> map = new CaseInsensitiveMap<SObjectField>();
+            for (java.util.Map.Entry<String, SObjectField> e : fields.entrySet()) {
+                map.put(e.getKey(), e.getValue());
+            }
+            return map;
+        }
+    }
+
+    public static class DescribeSObjectResult {
+        private final String name;
+        private final String label;
+        private final String labelPlural;
+        private final String keyPrefix;
+        private final java.util.List<RecordTypeInfo> recordTypes = new java.util.ArrayList<RecordTypeInfo>();
+        private SObjectType sObjectType;
+        public final Fields fields = new Fields();
+
+        private DescribeSObjectResult(String name, String label, String labelPlural, String keyPrefix) {
+            this.name = name;
+            this.label = label;
+            this.labelPlural = labelPlural;
+            this.keyPrefix = keyPrefix;
+        }
+
+        DescribeSObjectResult field(String name, String label, DisplayType type, int length, boolean nillable, String defaultValue, String... picklistValues) {
+            List<PicklistEntry> entries = new List<PicklistEntry>();
+            for (String value : picklistValues) {
+                entries.add(new PicklistEntry(value, value, value.equals(defaultValue)));
+            }
+            DescribeFieldResult describe = new DescribeFieldResult(name, label, type, length, nillable, defaultValue, entries);
+            describe.sObjectField = new SObjectField(describe);
+            fields.fields.put(name, describe.sObjectField);
+            return this;
+        }
+
+        DescribeSObjectResult recordType(String name, String developerName, String id, boolean isDefault) {
+            recordTypes.add(new RecordTypeInfo(name, developerName, id, isDefault));
+            return this;
+        }
+
+        // findField returns the field named name, ignoring case, or null.
+        SObjectField findField(String name) {
+            return fields.fields.get(name);
+        }
+
+        public String getName() {
+            return name;
+        }
+
+        public String getLabel() {
+            return label;
+        }
+
+        public String getLabelPlural() {
+            return labelPlural;
+        }
+
+        public String getKeyPrefix() {
+            return keyPrefix;
+        }
+
+        public SObjectType getSObjectType() {
+            return sObjectType;
+        }
+
+        public Boolean isCustom() {
+            return name.endsWith("__c");
+        }
+
+        public Boolean isAccessible() {
+            return true;
+        }
+
+        public Boolean isCreateable() {
+            return true;
+        }
+
+        public Boolean isUpdateable() {
+            return true;
+        }
+
+        public Boolean isDeletable() {
+            return true;
+        }
+
+        public Boolean isQueryable() {
+            return true;
+        }
+
+        public List<RecordTypeInfo> getRecordTypeInfos() {
+            List<RecordTypeInfo> infos = new List<RecordTypeInfo>();
+            infos.addAll(recordTypes);
+            return infos;
+        }
+
+        public Map<String, RecordTypeInfo> getRecordTypeInfosByName() {
+            Map<String, RecordTypeInfo> infos = new Map<String, RecordTypeInfo>();
+            for (RecordTypeInfo info : recordTypes) {
+                infos.put(info.getName(), info);
+            }
+            return infos;
+        }
+
+        public Map<String, RecordTypeInfo> getRecordTypeInfosByDeveloperName() {
+            Map<String, RecordTypeInfo> infos = new Map<String, RecordTypeInfo>();
+            for (RecordTypeInfo info : recordTypes) {
+                infos.put(info.getDeveloperName(), info);
+            }
+            return infos;
+        }
+
+        public Map<String, RecordTypeInfo> getRecordTypeInfosById() {
+            Map<String, RecordTypeInfo> infos = new Map<String, RecordTypeInfo>();
+            for (RecordTypeInfo info : recordTypes) {
+                infos.put(info.getRecordTypeId(), info);
+            }
+            return infos;
+        }
+    }
+
+    public static class DescribeFieldResult {
+        private final String name;
+        private final String label;
+        private final DisplayType type;
+        private final int length;
+        private final boolean nillable;
+        private final String defaultValue;
+        private final List<PicklistEntry> picklistValues;
+        private SObjectField sObjectField;
+
+        private DescribeFieldResult(String name, String label, DisplayType type, int length, boolean nillable, String defaultValue, List<PicklistEntry> picklistValues) {
+            this.name = name;
+            this.label = label;
+            this.type = type;
+            this.length = length;
+            this.nillable = nillable;
+            this.defaultValue = defaultValue;
+            this.picklistValues = picklistValues;
+        }
+
+        public String getName() {
+            return name;
+        }
+
+        public String getLocalName() {
+            return name;
+        }
+
+        public String getLabel() {
+            return label;
+        }
+
+        public DisplayType getType() {
+            return type;
+        }
+
+        public Integer getLength() {
+            return length;
+        }
+
+        public Boolean isNillable() {
+            return nillable;
+        }
+
+        public Boolean isCustom() {
+            return name.endsWith("__c");
+        }
+
+        public Boolean isAccessible() {
+            return true;
+        }
+
+        // isCreateable and isUpdateable are false for the Id and the audit
+        // fields, which the data store sets.
+        public Boolean isCreateable() {
+            return !isSystemField();
+        }
+
+        public Boolean isUpdateable() {
+            return !isSystemField();
+        }
+
+        private boolean isSystemField() {
+            return name.equals("Id") || name.equals("CreatedDate") || name.equals("LastModifiedDate");
+        }
+
+        public Object getDefaultValue() {
+            return defaultValue;
+        }
+
+        public List<PicklistEntry> getPicklistValues() {
+            List<PicklistEntry> values = new List<PicklistEntry>();
+            values.addAll(picklistValues);
+            return values;
+        }
+
+        public SObjectField getSObjectField() {
+            return sObjectField;
+        }
+    }
+
+    public static class PicklistEntry {
+        private final String value;
+        private final String label;
+        private final boolean defaultValue;
+
+        private PicklistEntry(String value, String label, boolean defaultValue) {
+            this.value = value;
+            this.label = label;
+            this.defaultValue = defaultValue;
+        }
+
+        public String getValue() {
+            return value;
+        }
+
+        public String getLabel() {
+            return label;
+        }
+
+        public Boolean isActive() {
+            return true;
+        }
+
+        public Boolean isDefaultValue() {
+            return defaultValue;
+        }
+    }
+
+    public static class RecordTypeInfo {
+        private final String name;
+        private final String developerName;
+        private final String recordTypeId;
+        private final boolean defaultMapping;
+
+        private RecordTypeInfo(String name, String developerName, String recordTypeId, boolean defaultMapping) {
+            this.name = name;
+            this.developerName = developerName;
+            this.recordTypeId = recordTypeId;
+            this.defaultMapping = defaultMapping;
+        }
+
+        public String getName() {
+            return name;
+        }
+
+        public String getDeveloperName() {
+            return developerName;
+        }
+
+        public String getRecordTypeId() {
+            return recordTypeId;
+        }
+
+        public Boolean isDefaultRecordTypeMapping() {
+            return defaultMapping;
+        }
+
+        public Boolean isMaster() {
+            return developerName.equals("Master");
+        }
+
+        public Boolean isActive() {
+            return true;
+        }
+
+        public Boolean isAvailable() {
+            return true;
+        }
+    }
+
+    // define registers the describe of an sObject, whose fields and record
+    // types are added to the result.
+    static DescribeSObjectResult define(Class<? extends SObject> type, String label, String labelPlural, String keyPrefix) {
+        DescribeSObjectResult describe = new DescribeSObjectResult(type.getSimpleName(), label, labelPlural, keyPrefix);
+        describe.sObjectType = new SObjectType(type, describe);
+        names.put(describe.getName(), describe.sObjectType);
+        types.put(type, describe.sObjectType);
+        return describe;
+    }
+
+    public static Map<String, SObjectType> getGlobalDescribe() {
+        CaseInsensitiveMap<SObjectType> map = new CaseInsensitiveMap<SObjectType>();
+        for (java.util.Map.Entry<String, SObjectType> e : names.entrySet()) {
+            map.put(e.getKey(), e.getValue());
+        }
+        return map;
+    }
+
+    public static List<DescribeSObjectResult> describeSObjects(java.util.List<String> sObjectTypes) {
+        List<DescribeSObjectResult> results = new List<DescribeSObjectResult>();
+        for (String name : sObjectTypes) {
+            SObjectType type = names.get(name);
+            if (type == null) {
+                throw new InvalidParameterValueException("sObject type '" + name + "' is not supported.");
+            }
+            results.add(type.getDescribe());
+        }
+        return results;
+    }
+
+    // getSObjectType returns the type of the sObject class, as Apex's
+    // Account.SObjectType.
+    public static SObjectType getSObjectType(Class<? extends SObject> type) {
+        SObjectType t = types.get(type);
+        if (t == null) {
+            throw new SObjectException("No describe metadata for " + type.getSimpleName());
+        }
+        return t;
+    }
+
+    // getSObjectField returns the field of the sObject class, as Apex's
+    // Account.Name.
+    public static SObjectField getSObjectField(Class<? extends SObject> type, String name) {
+        SObjectField field = getSObjectType(type).getDescribe().findField(name);
+        if (field == null) {
+            throw new SObjectException("Invalid field " + name + " for " + type.getSimpleName());
+        }
+        return field;
+    }
+}
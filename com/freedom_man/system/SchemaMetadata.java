@@ -0,0 +1,60 @@
+// Code generated by apex2java from sobject_generator.go. DO NOT EDIT.
+
+package com.freedom_man.system;
+
+final class SchemaMetadata {
+    private SchemaMetadata() {
+    }
+
+    static void load() {
+        Schema.define(Account.class, "Account", "Accounts", "001")
+                .field("Id", "Account ID", Schema.DisplayType.ID, 18, false, null)
+                .field("Name", "Account Name", Schema.DisplayType.STRING, 255, false, null)
+                .field("Industry", "Industry", Schema.DisplayType.PICKLIST, 255, true, null, "Agriculture", "Banking", "Education", "Technology")
+                .field("CreatedDate", "Created Date", Schema.DisplayType.DATETIME, 0, false, null)
+                .field("LastModifiedDate", "Last Modified Date", Schema.DisplayType.DATETIME, 0, false, null)
+                .recordType("Master", "Master", "012000000000000AAA", true);
+
+        Schema.define(User.class, "User", "Users", "005")
+                .field("Id", "User ID", Schema.DisplayType.ID, 18, false, null)
+                .field("Username", "Username", Schema.DisplayType.STRING, 80, false, null)
+                .field("LastName", "Last Name", Schema.DisplayType.STRING, 80, false, null)
+                .field("FirstName", "First Name", Schema.DisplayType.STRING, 40, true, null)
+                .field("Alias", "Alias", Schema.DisplayType.STRING, 8, false, null)
+                .field("Email", "Email", Schema.DisplayType.EMAIL, 128, false, null)
+                .field("ProfileId", "Profile ID", Schema.DisplayType.REFERENCE, 18, false, null)
+                .field("TimeZoneSidKey", "Time Zone", Schema.DisplayType.PICKLIST, 40, false, null)
+                .field("LocaleSidKey", "Locale", Schema.DisplayType.PICKLIST, 40, false, null)
+                .field("LanguageLocaleKey", "Language", Schema.DisplayType.PICKLIST, 40, false, null)
+                .field("EmailEncodingKey", "Email Encoding", Schema.DisplayType.PICKLIST, 40, false, null)
+                .field("CreatedDate", "Created Date", Schema.DisplayType.DATETIME, 0, false, null)
+                .field("LastModifiedDate", "Last Modified Date", Schema.DisplayType.DATETIME, 0, false, null)
+                .recordType("Master", "Master", "012000000000000AAA", true);
+
+        Schema.define(AsyncApexJob.class, "Apex Job", "Apex Jobs", "707")
+                .field("Id", "Apex Job ID", Schema.DisplayType.ID, 18, false, null)
+                .field("JobType", "Job Type", Schema.DisplayType.PICKLIST, 40, true, null, "Future", "ScheduledApex", "BatchApex", "BatchApexWorker", "Queueable")
+                .field("MethodName", "Apex Method", Schema.DisplayType.STRING, 255, true, null)
+                .field("Status", "Status", Schema.DisplayType.PICKLIST, 40, false, null, "Queued", "Preparing", "Processing", "Aborted", "Completed", "Failed", "Holding")
+                .field("ExtendedStatus", "Status Detail", Schema.DisplayType.STRING, 255, true, null)
+                .field("JobItemsProcessed", "Batches Processed", Schema.DisplayType.INTEGER, 0, false, null)
+                .field("TotalJobItems", "Total Batches", Schema.DisplayType.INTEGER, 0, true, null)
+                .field("NumberOfErrors", "Failures", Schema.DisplayType.INTEGER, 0, true, null)
+                .field("CompletedDate", "Completion Date", Schema.DisplayType.DATETIME, 0, true, null)
+                .field("CreatedDate", "Created Date", Schema.DisplayType.DATETIME, 0, false, null)
+                .field("LastModifiedDate", "Last Modified Date", Schema.DisplayType.DATETIME, 0, false, null)
+                .recordType("Master", "Master", "012000000000000AAA", true);
+
+        Schema.define(CronTrigger.class, "Scheduled Jobs", "Scheduled Jobs", "08e")
+                .field("Id", "Scheduled Jobs ID", Schema.DisplayType.ID, 18, false, null)
+                .field("CronExpression", "Cron Expression", Schema.DisplayType.STRING, 255, true, null)
+                .field("State", "State", Schema.DisplayType.PICKLIST, 40, true, null, "WAITING", "ACQUIRED", "EXECUTING", "COMPLETE", "ERROR", "DELETED", "PAUSED", "BLOCKED", "PAUSED_BLOCKED")
+                .field("TimesTriggered", "Times Triggered", Schema.DisplayType.INTEGER, 0, true, null)
+                .field("StartTime", "Start Time", Schema.DisplayType.DATETIME, 0, true, null)
+                .field("NextFireTime", "Next Fire Time", Schema.DisplayType.DATETIME, 0, true, null)
+                .field("PreviousFireTime", "Previous Fire Time", Schema.DisplayType.DATETIME, 0, true, null)
+                .field("CreatedDate", "Created Date", Schema.DisplayType.DATETIME, 0, false, null)
+                .field("LastModifiedDate", "Last Modified Date", Schema.DisplayType.DATETIME, 0, false, null)
+                .recordType("Master", "Master", "012000000000000AAA", true);
+    }
+}
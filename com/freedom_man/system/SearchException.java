@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class SearchException extends Exception {
+    public SearchException() {
+        super();
+    }
+
+    public SearchException(String message) {
+        super(message);
+    }
+
+    public SearchException(Exception cause) {
+        super(cause);
+    }
+
+    public SearchException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
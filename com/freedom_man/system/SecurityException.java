@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class SecurityException extends Exception {
+    public SecurityException() {
+        super();
+    }
+
+    public SecurityException(String message) {
+        super(message);
+    }
+
+    public SecurityException(Exception cause) {
+        super(cause);
+    }
+
+    public SecurityException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class SerializationException extends Exception {
+    public SerializationException() {
+        super();
+    }
+
+    public SerializationException(String message) {
+        super(message);
+    }
+
+    public SerializationException(Exception cause) {
+        super(cause);
+    }
+
+    public SerializationException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
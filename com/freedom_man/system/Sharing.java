@@ -0,0 +1,62 @@
+package com.freedom_man.system;
+
+import java.lang.annotation.ElementType;
+import java.lang.annotation.Retention;
+import java.lang.annotation.RetentionPolicy;
+import java.lang.annotation.Target;
+
+// Sharing records the Apex sharing keyword of a class, so that record
+// access can be checked against the mode of the running code.
+@Retention(RetentionPolicy.RUNTIME)
+@Target(ElementType.TYPE)
+public @interface Sharing {
+    Mode value();
+
+    enum Mode {
+        WITH,
+        WITHOUT,
+        INHERITED;
+
+        // declared caches the modes of the classes on the stack by name.
+        private static final java.util.Map<String, java.util.Optional<Mode>> declared = new java.util.concurrent.ConcurrentHashMap<String, java.util.Optional<Mode>>();
+
+        // of returns the sharing mode declared by type or the nearest
+        // superclass declaring one, or null. Inner classes do not inherit
+        // the mode of the class enclosing them.
+        public static Mode of(Class<?> type) {
+            for (Class<?> c = type; c != null; c = c.getSuperclass()) {
+                Sharing sharing = c.getAnnotation(Sharing.class);
+                if (sharing != null) {
+                    return sharing.value();
+                }
+            }
+            return null;
+        }
+
+        // running returns the sharing mode of the running code: the mode of
+        // the innermost caller declaring with or without sharing. Classes
+        // without a sharing keyword run in the mode of their caller, and
+        // inherited sharing runs with sharing when no caller declares one.
+        public static Mode running() {
+            boolean inherited = false;
+            for (StackTraceElement frame : Thread.currentThread().getStackTrace()) {
+                Mode mode = declared.computeIfAbsent(frame.getClassName(), Mode::declaredBy).orElse(null);
+                if (mode == INHERITED) {
+                    inherited = true;
+                } else if (mode != null) {
+                    return mode;
+                }
+            }
+            return inherited ? WITH : WITHOUT;
+        }
+
+        private static java.util.Optional<Mode> declaredBy(String className) {
+            ClassLoader loader = Thread.currentThread().getContextClassLoader();
+            try {
+                return java.util.Optional.ofNullable(of(Class.forName(className, false, loader)));
+            } catch (ClassNotFoundException | LinkageError e) {
+                return java.util.Optional.empty();
+            }
+        }
+    }
+}
@@ -0,0 +1,552 @@
+package com.freedom_man.system;
+
+import java.math.BigDecimal;
+import java.time.DayOfWeek;
+import java.time.LocalDate;
+import java.time.OffsetDateTime;
+import java.time.temporal.TemporalAdjusters;
+import java.util.ArrayList;
+import java.util.Collection;
+import java.util.function.IntPredicate;
+import java.util.regex.Matcher;
+import java.util.regex.Pattern;
+
+// Soql runs SOQL queries against the records in the DataStore. It reads the
+// subset of SOQL that runs without an org: one object, WHERE conditions on
+// its fields, ORDER BY, LIMIT and OFFSET. Bind variables are written as ?
+// and take the query arguments in order, or, in dynamic queries, as :name
+// and take the value of the name in a map of bind variables. Selected
+// fields are not projected, so the records carry every stored field. Date
+// literals such as TODAY and LAST_N_DAYS:n are days in the time zone of the
+// running user. Queries only return the records the sharing mode of the
+// running code can read, see DataStore.readableRecords.
+class Soql {
+    private static final Pattern DATE_LITERAL = Pattern.compile("\\d{4}-\\d{2}-\\d{2}(T\\d{2}:\\d{2}:\\d{2}(\\.\\d+)?(Z|[+-]\\d{2}:\\d{2}))?");
+
+    interface Condition {
+        boolean test(SObject record);
+    }
+
+    // DateRange is the value of a date literal, the days from start up to
+    // but not including end.
+    private static class DateRange {
+        final LocalDate start;
+        final LocalDate end;
+
+        DateRange(LocalDate start, LocalDate end) {
+            this.start = start;
+            this.end = end;
+        }
+
+        // compare returns where the day of a Date or Datetime field value
+        // falls: below, within (0) or after the range.
+        int compare(Object value) {
+            LocalDate day;
+            if (value instanceof Date) {
+                day = ((Date) value).value;
+            } else if (value instanceof Datetime) {
+                day = ((Datetime) value).date().value;
+            } else {
+                throw new QueryException("cannot compare " + value + " with a date literal");
+            }
+            if (day.isBefore(start)) {
+                return -1;
+            }
+            return day.isBefore(end) ? 0 : 1;
+        }
+    }
+
+    private static class Ordering {
+        String field;
+        boolean descending;
+        boolean nullsFirst;
+    }
+
+    private final java.util.List<String> tokens;
+    private final Object[] binds;
+    private final java.util.Map<String, Object> namedBinds;
+    private int pos;
+    private int bind;
+
+    private String object;
+    private Condition where = record -> true;
+    private final java.util.List<Ordering> orderings = new ArrayList<Ordering>();
+    private Integer limit;
+    private int offset;
+
+    private Soql(String soql, Object[] binds, java.util.Map<String, Object> namedBinds) {
+        this.tokens = tokenize(soql);
+        this.binds = binds;
+        this.namedBinds = namedBinds;
+    }
+
+    static java.util.List<SObject> execute(String soql, Object... binds) {
+        Soql query = new Soql(soql, binds, java.util.Collections.<String, Object>emptyMap());
+        query.parse();
+        return query.run();
+    }
+
+    // execute runs a dynamic query, whose bind variables are looked up by
+    // name, ignoring case as Apex does.
+    static java.util.List<SObject> execute(String soql, java.util.Map<String, Object> binds) {
+        Soql query = new Soql(soql, new Object[0], binds);
+        query.parse();
+        return query.run();
+    }
+
+    private java.util.List<SObject> run() {
+        java.util.List<SObject> result = new ArrayList<SObject>();
+        for (SObject record : DataStore.readableRecords(object)) {
+            if (where.test(record)) {
+                result.add(record);
+            }
+        }
+        if (!orderings.isEmpty()) {
+            result.sort(this::compareRecords);
+        }
+        int from = Math.min(offset, result.size());
+        int to = limit == null ? result.size() : Math.min(result.size(), from + limit);
+        return new ArrayList<SObject>(result.subList(from, to));
+    }
+
+    private int compareRecords(SObject l, SObject r) {
+        for (Ordering o : orderings) {
+            Object lv = field(l, o.field);
+            Object rv = field(r, o.field);
+            int c;
+            if (lv == null || rv == null) {
+                if (lv == rv) {
+                    continue;
+                }
+                c = (lv == null) == o.nullsFirst ? -1 : 1;
+            } else {
+                c = compare(lv, rv);
+                if (o.descending) {
+                    c = -c;
+                }
+            }
+            if (c != 0) {
+                return c;
+            }
+        }
+        return 0;
+    }
+
+    private void parse() {
+        expect("SELECT");
+        for (int depth = 0; depth > 0 || !peekIs("FROM"); ) {
+            String token = next();
+            if (token.equals("(")) {
+                depth++;
+            } else if (token.equals(")")) {
+                depth--;
+            }
+        }
+        expect("FROM");
+        object = next();
+        if (accept("WHERE")) {
+            where = or();
+        }
+        if (accept("ORDER")) {
+            expect("BY");
+            do {
+                Ordering o = new Ordering();
+                o.field = next();
+                if (accept("DESC")) {
+                    o.descending = true;
+                } else {
+                    accept("ASC");
+                }
+                o.nullsFirst = !o.descending;
+                if (accept("NULLS")) {
+                    if (accept("LAST")) {
+                        o.nullsFirst = false;
+                    } else {
+                        expect("FIRST");
+                        o.nullsFirst = true;
+                    }
+                }
+                orderings.add(o);
+            } while (accept(","));
+        }
+        if (accept("LIMIT")) {
+            limit = count();
+        }
+        if (accept("OFFSET")) {
+            offset = count();
+        }
+        if (accept("FOR")) {
+            // FOR UPDATE, VIEW and REFERENCE do not change the result.
+            pos = tokens.size();
+        }
+        if (pos < tokens.size()) {
+            throw new QueryException("unexpected token: " + tokens.get(pos));
+        }
+    }
+
+    private Condition or() {
+        Condition condition = and();
+        while (accept("OR")) {
+            Condition l = condition;
+            Condition r = and();
+            condition = record -> l.test(record) || r.test(record);
+        }
+        return condition;
+    }
+
+    private Condition and() {
+        Condition condition = not();
+        while (accept("AND")) {
+            Condition l = condition;
+            Condition r = not();
+            condition = record -> l.test(record) && r.test(record);
+        }
+        return condition;
+    }
+
+    private Condition not() {
+        if (accept("NOT")) {
+            Condition c = not();
+            return record -> !c.test(record);
+        }
+        if (accept("(")) {
+            Condition c = or();
+            expect(")");
+            return c;
+        }
+        return comparison();
+    }
+
+    private Condition comparison() {
+        String field = next();
+        if (accept("NOT")) {
+            expect("IN");
+            Object value = value();
+            return record -> !in(field(record, field), value);
+        }
+        String op = next().toUpperCase();
+        Object value = value();
+        if (value instanceof DateRange) {
+            return dateComparison(field, op, (DateRange) value);
+        }
+        switch (op) {
+        case "=":
+            return record -> ApexOperator.equals(field(record, field), value);
+        case "!=":
+        case "<>":
+            return record -> !ApexOperator.equals(field(record, field), value);
+        case "<":
+            return record -> relational(field(record, field), value, c -> c < 0);
+        case "<=":
+            return record -> relational(field(record, field), value, c -> c <= 0);
+        case ">":
+            return record -> relational(field(record, field), value, c -> c > 0);
+        case ">=":
+            return record -> relational(field(record, field), value, c -> c >= 0);
+        case "LIKE":
+            Pattern pattern = like(value);
+            return record -> {
+                Object v = field(record, field);
+                return v != null && pattern.matcher(v.toString()).matches();
+            };
+        case "IN":
+            return record -> in(field(record, field), value);
+        }
+        throw new QueryException("unexpected token: " + op);
+    }
+
+    // dateComparison compares a field with a date literal. A value equals
+    // the literal when its day is in the range.
+    private static Condition dateComparison(String field, String op, DateRange range) {
+        switch (op) {
+        case "=":
+            return record -> relational(field(record, field), range, c -> c == 0);
+        case "!=":
+        case "<>":
+            return record -> relational(field(record, field), range, c -> c != 0);
+        case "<":
+            return record -> relational(field(record, field), range, c -> c < 0);
+        case "<=":
+            return record -> relational(field(record, field), range, c -> c <= 0);
+        case ">":
+            return record -> relational(field(record, field), range, c -> c > 0);
+        case ">=":
+            return record -> relational(field(record, field), range, c -> c >= 0);
+        }
+        throw new QueryException("unexpected token: " + op);
+    }
+
+    private Object value() {
+        String token = next();
+        if (token.equals("?")) {
+            if (bind >= binds.length) {
+                throw new QueryException("missing bind variable " + (bind + 1));
+            }
+            return binds[bind++];
+        }
+        if (token.equals(":")) {
+            return namedBind(next());
+        }
+        if (token.equals("(")) {
+            java.util.List<Object> values = new ArrayList<Object>();
+            do {
+                values.add(value());
+            } while (accept(","));
+            expect(")");
+            return values;
+        }
+        if (token.startsWith("'")) {
+            return token.substring(1);
+        }
+        switch (token.toUpperCase()) {
+        case "NULL":
+            return null;
+        case "TRUE":
+            return Boolean.TRUE;
+        case "FALSE":
+            return Boolean.FALSE;
+        }
+        DateRange range = dateLiteral(token);
+        if (range != null) {
+            return range;
+        }
+        if (DATE_LITERAL.matcher(token).matches()) {
+            if (token.length() == 10) {
+                return new Date(LocalDate.parse(token));
+            }
+            return new Datetime(OffsetDateTime.parse(token).toInstant());
+        }
+        try {
+            if (token.contains(".")) {
+                return new BigDecimal(token);
+            }
+            long n = Long.parseLong(token);
+            if (n == (int) n) {
+                return Integer.valueOf((int) n);
+            }
+            return Long.valueOf(n);
+        } catch (NumberFormatException e) {
+            throw new QueryException("unexpected token: " + token);
+        }
+    }
+
+    private Object namedBind(String name) {
+        for (java.util.Map.Entry<String, Object> e : namedBinds.entrySet()) {
+            if (e.getKey().equalsIgnoreCase(name)) {
+                return e.getValue();
+            }
+        }
+        throw new QueryException("Variable does not exist: " + name);
+    }
+
+    // dateLiteral returns the range of a relative date literal, or null if
+    // the token is not one.
+    private DateRange dateLiteral(String token) {
+        LocalDate today = Date.today().value;
+        LocalDate week = today.with(TemporalAdjusters.previousOrSame(DayOfWeek.SUNDAY));
+        LocalDate month = today.withDayOfMonth(1);
+        LocalDate year = today.withDayOfYear(1);
+        switch (token.toUpperCase()) {
+        case "TODAY":
+            return new DateRange(today, today.plusDays(1));
+        case "YESTERDAY":
+            return new DateRange(today.minusDays(1), today);
+        case "TOMORROW":
+            return new DateRange(today.plusDays(1), today.plusDays(2));
+        case "THIS_WEEK":
+            return new DateRange(week, week.plusWeeks(1));
+        case "LAST_WEEK":
+            return new DateRange(week.minusWeeks(1), week);
+        case "NEXT_WEEK":
+            return new DateRange(week.plusWeeks(1), week.plusWeeks(2));
+        case "THIS_MONTH":
+            return new DateRange(month, month.plusMonths(1));
+        case "LAST_MONTH":
+            return new DateRange(month.minusMonths(1), month);
+        case "NEXT_MONTH":
+            return new DateRange(month.plusMonths(1), month.plusMonths(2));
+        case "THIS_YEAR":
+            return new DateRange(year, year.plusYears(1));
+        case "LAST_YEAR":
+            return new DateRange(year.minusYears(1), year);
+        case "NEXT_YEAR":
+            return new DateRange(year.plusYears(1), year.plusYears(2));
+        case "LAST_N_DAYS":
+            expect(":");
+            return new DateRange(today.minusDays(count()), today.plusDays(1));
+        case "NEXT_N_DAYS":
+            expect(":");
+            return new DateRange(today.plusDays(1), today.plusDays(1 + count()));
+        }
+        return null;
+    }
+
+    private int count() {
+        Object value = value();
+        if (!(value instanceof Number)) {
+            throw new QueryException("expected a number: " + value);
+        }
+        return ((Number) value).intValue();
+    }
+
+    // field returns the value of a field of the record, following
+    // relationship fields for dotted names.
+    static Object field(SObject record, String name) {
+        Object value = record;
+        for (String part : name.split("\\.")) {
+            if (value == null) {
+                return null;
+            }
+            if (!(value instanceof SObject)) {
+                throw new QueryException("Invalid field " + name);
+            }
+            value = DataStore.field((SObject) value, part);
+        }
+        return value;
+    }
+
+    private static boolean in(Object value, Object values) {
+        if (!(values instanceof Collection)) {
+            throw new QueryException("IN requires a list of values");
+        }
+        for (Object v : (Collection<?>) values) {
+            if (ApexOperator.equals(value, v)) {
+                return true;
+            }
+        }
+        return false;
+    }
+
+    // relational applies a relational operator, which never matches null, to
+    // the field value l and r.
+    private static boolean relational(Object l, Object r, IntPredicate test) {
+        if (l == null || r == null) {
+            return false;
+        }
+        if (r instanceof DateRange) {
+            return test.test(((DateRange) r).compare(l));
+        }
+        return test.test(compare(l, r));
+    }
+
+    @SuppressWarnings({"unchecked", "rawtypes"})
+    private static int compare(Object l, Object r) {
+        if (l instanceof Number && r instanceof Number) {
+            return ApexOperator.toDecimal((Number) l).compareTo(ApexOperator.toDecimal((Number) r));
+        }
+        if (l instanceof String && r instanceof String) {
+            return ((String) l).compareToIgnoreCase((String) r);
+        }
+        if (l instanceof Comparable && l.getClass().isInstance(r)) {
+            return ((Comparable) l).compareTo(r);
+        }
+        throw new QueryException("cannot compare " + l + " with " + r);
+    }
+
+    // like compiles a LIKE pattern, where % matches any characters and _ a
+    // single one, case-insensitively.
+    private static Pattern like(Object value) {
+        if (!(value instanceof String)) {
+            throw new QueryException("LIKE requires a string: " + value);
+        }
+        String pattern = (String) value;
+        StringBuilder regex = new StringBuilder();
+        for (int i = 0; i < pattern.length(); i++) {
+            char c = pattern.charAt(i);
+            if (c == '%') {
+                regex.append(".*");
+            } else if (c == '_') {
+                regex.append('.');
+            } else {
+                if (c == '\\' && i + 1 < pattern.length()) {
+                    c = pattern.charAt(++i);
+                }
+                regex.append(Pattern.quote(String.valueOf(c)));
+            }
+        }
+        return Pattern.compile(regex.toString(), Pattern.CASE_INSENSITIVE | Pattern.DOTALL);
+    }
+
+    private String next() {
+        if (pos >= tokens.size()) {
+            throw new QueryException("unexpected end of query");
+        }
+        return tokens.get(pos++);
+    }
+
+    private boolean peekIs(String keyword) {
+        return pos < tokens.size() && tokens.get(pos).equalsIgnoreCase(keyword);
+    }
+
+    private boolean accept(String keyword) {
+        if (peekIs(keyword)) {
+            pos++;
+            return true;
+        }
+        return false;
+    }
+
+    private void expect(String keyword) {
+        String token = next();
+        if (!token.equalsIgnoreCase(keyword)) {
+            throw new QueryException("expected " + keyword + ": " + token);
+        }
+    }
+
+    // tokenize splits a query into keywords, names, numbers, operators and
+    // string literals. String literals are returned unescaped, prefixed
+    // with the opening quote to tell them from names.
+    private static java.util.List<String> tokenize(String soql) {
+        java.util.List<String> tokens = new ArrayList<String>();
+        int i = 0;
+        while (i < soql.length()) {
+            char c = soql.charAt(i);
+            Matcher date = DATE_LITERAL.matcher(soql).region(i, soql.length());
+            if (Character.isWhitespace(c)) {
+                i++;
+            } else if (date.lookingAt()) {
+                tokens.add(date.group());
+                i = date.end();
+            } else if (c == '\'') {
+                StringBuilder s = new StringBuilder("'");
+                for (i++; i < soql.length() && soql.charAt(i) != '\''; i++) {
+                    char e = soql.charAt(i);
+                    if (e == '\\' && i + 1 < soql.length()) {
+                        e = soql.charAt(++i);
+                        switch (e) {
+                        case 'n': e = '\n'; break;
+                        case 't': e = '\t'; break;
+                        case 'r': e = '\r'; break;
+                        case '%':
+                        case '_':
+                            // kept escaped for LIKE patterns
+                            s.append('\\');
+                            break;
+                        }
+                    }
+                    s.append(e);
+                }
+                if (i >= soql.length()) {
+                    throw new QueryException("unterminated string literal");
+                }
+                tokens.add(s.toString());
+                i++;
+            } else if (Character.isLetterOrDigit(c) || c == '_' || (c == '-' && i + 1 < soql.length() && Character.isDigit(soql.charAt(i + 1)))) {
+                int start = i++;
+                while (i < soql.length() && (Character.isLetterOrDigit(soql.charAt(i)) || soql.charAt(i) == '_' || soql.charAt(i) == '.')) {
+                    i++;
+                }
+                tokens.add(soql.substring(start, i));
+            } else if (soql.startsWith("<=", i) || soql.startsWith(">=", i) || soql.startsWith("!=", i) || soql.startsWith("<>", i)) {
+                tokens.add(soql.substring(i, i + 2));
+                i += 2;
+            } else if ("=<>(),?:".indexOf(c) >= 0) {
+                tokens.add(String.valueOf(c));
+                i++;
+            } else {
+                throw new QueryException("unexpected character: " + c);
+            }
+        }
+        return tokens;
+    }
+}
@@ -0,0 +1,104 @@
+package com.freedom_man.system;
+
+import java.io.ByteArrayOutputStream;
+import java.io.IOException;
+import java.io.InputStream;
+import java.nio.charset.StandardCharsets;
+import java.util.Collections;
+import java.util.IdentityHashMap;
+import java.util.concurrent.ConcurrentHashMap;
+
+// SourceMap maps the lines of converted classes to the lines of their Apex
+// source, read from the apex2java/<Class>.map.json resources apex2java
+// writes next to them. Frames of classes without a source map are kept as
+// they are.
+public class SourceMap {
+    private static final java.util.Map<String, SourceMap> maps = new ConcurrentHashMap<String, SourceMap>();
+    private static final SourceMap NONE = new SourceMap(null, new int[0]);
+
+    private final String source;
+    private final int[] lines;
+
+    private SourceMap(String source, int[] lines) {
+        this.source = source;
+        this.lines = lines;
+    }
+
+    // apexLine returns the Apex line of the frame, or its Java line if it has
+    // no Apex line.
+    public static int apexLine(StackTraceElement frame) {
+        return apexFrame(frame).getLineNumber();
+    }
+
+    // apexFrame returns the frame with the Apex file and line of its Java
+    // line, if it has one.
+    public static StackTraceElement apexFrame(StackTraceElement frame) {
+        if (frame.getFileName() != null && frame.getFileName().endsWith(".cls")) {
+            return frame;
+        }
+        SourceMap map = forClass(frame.getClassName());
+        int line = frame.getLineNumber();
+        if (line < 1 || line > map.lines.length || map.lines[line - 1] == 0) {
+            return frame;
+        }
+        return new StackTraceElement(frame.getClassName(), frame.getMethodName(), map.source, map.lines[line - 1]);
+    }
+
+    // rewrite replaces the frames of the stack traces of the throwable, its
+    // causes and suppressed exceptions with their Apex frames, and returns
+    // the throwable.
+    public static <T extends Throwable> T rewrite(T throwable) {
+        rewrite(throwable, Collections.newSetFromMap(new IdentityHashMap<Throwable, Boolean>()));
+        return throwable;
+    }
+
+    private static void rewrite(Throwable throwable, java.util.Set<Throwable> seen) {
+        if (throwable == null || !seen.add(throwable)) {
+            return;
+        }
+        StackTraceElement[] frames = throwable.getStackTrace();
+        for (int i = 0; i < frames.length; i++) {
+            frames[i] = apexFrame(frames[i]);
+        }
+        throwable.setStackTrace(frames);
+        rewrite(throwable.getCause(), seen);
+        for (Throwable suppressed : throwable.getSuppressed()) {
+            rewrite(suppressed, seen);
+        }
+    }
+
+    // forClass returns the source map of the top level class of className.
+    private static SourceMap forClass(String className) {
+        int inner = className.indexOf('$');
+        String name = inner < 0 ? className : className.substring(0, inner);
+        return maps.computeIfAbsent(name, SourceMap::load);
+    }
+
+    private static SourceMap load(String className) {
+        ClassLoader loader = Thread.currentThread().getContextClassLoader();
+        if (loader == null) {
+            loader = SourceMap.class.getClassLoader();
+        }
+        String resource = "apex2java/" + className.replace('.', '/') + ".map.json";
+        try (InputStream in = loader.getResourceAsStream(resource)) {
+            if (in == null) {
+                return NONE;
+            }
+            ByteArrayOutputStream out = new ByteArrayOutputStream();
+            byte[] buffer = new byte[8192];
+            for (int n; (n = in.read(buffer)) > 0; ) {
+                out.write(buffer, 0, n);
+            }
+            java.util.Map<?, ?> json = (java.util.Map<?, ?>) JSON.parse(new String(out.toByteArray(), StandardCharsets.UTF_8));
+            java.util.List<?> lines = (java.util.List<?>) json.get("lines");
+            int[] map = new int[lines.size()];
+            for (int i = 0; i < map.length; i++) {
+                map[i] = ((Number) lines.get(i)).intValue();
+            }
+            return new SourceMap((String) json.get("source"), map);
+        } catch (IOException | RuntimeException e) {
+            // a missing or broken source map leaves the Java lines
+            return NONE;
+        }
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class StringException extends Exception {
+    public StringException() {
+        super();
+    }
+
+    public StringException(String message) {
+        super(message);
+    }
+
+    public StringException(Exception cause) {
+        super(cause);
+    }
+
+    public StringException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
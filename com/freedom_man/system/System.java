@@ -1,7 +1,218 @@
 package com.freedom_man.system;
 
+import java.time.LocalTime;
+import java.time.format.DateTimeFormatter;
+import java.util.ArrayDeque;
+import java.util.Deque;
+import java.util.LinkedHashMap;
+import java.util.Map;
+
 public class System {
-    public static void debug(Object o) {
-        java.lang.System.out.println(o);
+    private static final DateTimeFormatter LOG_TIME = DateTimeFormatter.ofPattern("HH:mm:ss.SSS");
+    private static final long started = java.lang.System.nanoTime();
+
+    private static final Deque<User> runningUsers = new ArrayDeque<User>();
+    // scheduled maps the Ids of the scheduled jobs to their names.
+    private static final Map<String, String> scheduled = new LinkedHashMap<String, String>();
+    private static final Map<String, CronTrigger> triggers = new LinkedHashMap<String, CronTrigger>();
+    static boolean batch;
+    static boolean future;
+    static boolean queueable;
+    static boolean scheduledJob;
+
+    public static void debug(Object message) {
+        debug(LoggingLevel.DEBUG, message);
+    }
+
+    // debug writes a USER_DEBUG line of the Apex debug log format,
+    // "12:34:56.789 (1234567)|USER_DEBUG|[12]|DEBUG|message", where the
+    // line number is the Apex line of the caller, or its Java line if the
+    // caller has no source map.
+    public static void debug(LoggingLevel level, Object message) {
+        java.lang.System.out.println(String.format(
+            "%s (%d)|USER_DEBUG|[%d]|%s|%s",
+            LocalTime.now().format(LOG_TIME),
+            java.lang.System.nanoTime() - started,
+            callerLine(),
+            level.name(),
+            ApexOperator.toString(message)));
+    }
+
+    private static int callerLine() {
+        for (StackTraceElement e : new Throwable().getStackTrace()) {
+            if (!e.getClassName().equals(System.class.getName())) {
+                return SourceMap.apexLine(e);
+            }
+        }
+        return 0;
+    }
+
+    public static Datetime now() {
+        return Datetime.now();
+    }
+
+    public static Date today() {
+        return Date.today();
+    }
+
+    public static Long currentTimeMillis() {
+        return java.lang.System.currentTimeMillis();
+    }
+
+    // runAs starts running as user until the matching endRunAs.
+    // System.runAs(user) { ... } is converted to runAs(user), followed by
+    // the block in a try statement that calls endRunAs when it completes.
+    public static void runAs(User user) {
+        runningUsers.push(user);
+    }
+
+    public static void endRunAs() {
+        runningUsers.pop();
+    }
+
+    // runningUser returns the user set by the innermost runAs, or null.
+    static User runningUser() {
+        return runningUsers.peek();
+    }
+
+    // enqueueJob queues the job, which AsyncApex.run runs, and returns its
+    // job Id. As in Apex, a job cannot queue another one in a test.
+    public static String enqueueJob(Queueable job) {
+        if (queueable && Test.isRunningTest()) {
+            throw new AsyncException("Maximum stack depth has been reached.");
+        }
+        Limits.queueableJob();
+        return AsyncApex.enqueue("Queueable", null, record -> {
+            boolean previous = queueable;
+            queueable = true;
+            try {
+                job.execute(() -> record.Id);
+            } finally {
+                queueable = previous;
+            }
+        });
+    }
+
+    // schedule records the job as a CronTrigger, whose Id it returns, and
+    // queues a run of it, as Apex runs a scheduled job once when a test
+    // stops testing. The trigger's NextFireTime follows the cron
+    // expression.
+    public static String schedule(String jobName, String cronExpression, Schedulable job) {
+        if (scheduled.containsValue(jobName)) {
+            throw new AsyncException("The Apex job named \"" + jobName + "\" is already scheduled for execution.");
+        }
+        Cron cron = new Cron(cronExpression);
+        CronTrigger trigger = new CronTrigger();
+        trigger.CronExpression = cronExpression;
+        trigger.State = "WAITING";
+        trigger.TimesTriggered = 0;
+        trigger.StartTime = Datetime.now();
+        trigger.NextFireTime = cron.next(trigger.StartTime);
+        DataStore.insert(trigger);
+        scheduled.put(trigger.Id, jobName);
+        triggers.put(trigger.Id, trigger);
+        AsyncApex.enqueue("ScheduledApex", null, record -> {
+            if (!scheduled.containsKey(trigger.Id)) {
+                return;
+            }
+            boolean previous = scheduledJob;
+            scheduledJob = true;
+            try {
+                job.execute(() -> trigger.Id);
+            } finally {
+                scheduledJob = previous;
+            }
+            trigger.TimesTriggered++;
+            trigger.PreviousFireTime = trigger.NextFireTime;
+            trigger.NextFireTime = trigger.NextFireTime == null ? null : cron.next(trigger.NextFireTime);
+            DataStore.update(trigger);
+        });
+        return trigger.Id;
+    }
+
+    // abortJob deletes a scheduled job or stops a queued one. Jobs that
+    // have run are left as they are.
+    public static void abortJob(String jobId) {
+        CronTrigger trigger = triggers.remove(jobId);
+        if (trigger != null) {
+            scheduled.remove(jobId);
+            DataStore.delete(trigger);
+            return;
+        }
+        AsyncApex.abort(jobId);
+    }
+
+    public static Boolean isBatch() {
+        return batch;
+    }
+
+    public static Boolean isFuture() {
+        return future;
+    }
+
+    public static Boolean isQueueable() {
+        return queueable;
+    }
+
+    public static Boolean isScheduled() {
+        return scheduledJob;
+    }
+
+    // reset discards the jobs and the running user of the previous test.
+    static void reset() {
+        runningUsers.clear();
+        scheduled.clear();
+        triggers.clear();
+        AsyncApex.reset();
+        batch = false;
+        future = false;
+        queueable = false;
+        scheduledJob = false;
+    }
+
+    // assertTrue is System.assert, which is a Java keyword.
+    public static void assertTrue(Boolean condition) {
+        assertTrue(condition, null);
+    }
+
+    public static void assertTrue(Boolean condition, Object message) {
+        if (condition == null || !condition) {
+            throw assertionFailed(message, null);
+        }
+    }
+
+    public static void assertEquals(Object expected, Object actual) {
+        assertEquals(expected, actual, null);
+    }
+
+    // assertEquals and assertNotEquals compare Strings case-sensitively,
+    // unlike ==.
+    public static void assertEquals(Object expected, Object actual, Object message) {
+        if (!ApexOperator.equalsCaseSensitive(expected, actual)) {
+            throw assertionFailed(message, "Expected: " + ApexOperator.toString(expected) + ", Actual: " + ApexOperator.toString(actual));
+        }
+    }
+
+    public static void assertNotEquals(Object notExpected, Object actual) {
+        assertNotEquals(notExpected, actual, null);
+    }
+
+    public static void assertNotEquals(Object notExpected, Object actual, Object message) {
+        if (ApexOperator.equalsCaseSensitive(notExpected, actual)) {
+            throw assertionFailed(message, "Same value: " + ApexOperator.toString(actual));
+        }
+    }
+
+    // assertionFailed builds the exception with the message Apex reports,
+    // e.g. "Assertion Failed: msg: Expected: 1, Actual: 2".
+    private static AssertException assertionFailed(Object message, String detail) {
+        StringBuilder sb = new StringBuilder("Assertion Failed");
+        if (message != null) {
+            sb.append(": ").append(ApexOperator.toString(message));
+        }
+        if (detail != null) {
+            sb.append(": ").append(detail);
+        }
+        return new AssertException(sb.toString());
     }
 }
@@ -0,0 +1,58 @@
+package com.freedom_man.system;
+
+// Test is the Apex Test class. JUnit tests converted from Apex call reset
+// before each test method.
+public class Test {
+    private static boolean running;
+    private static boolean started;
+    private static boolean stopped;
+    private static final java.util.Map<Class<?>, Object> mocks = new java.util.HashMap<Class<?>, Object>();
+
+    public static void startTest() {
+        if (started) {
+            throw new FinalException("Testing already started");
+        }
+        started = true;
+        Limits.begin(false);
+    }
+
+    // stopTest runs the asynchronous jobs queued so far, as Apex runs the
+    // jobs started in a test when it stops testing.
+    public static void stopTest() {
+        if (stopped) {
+            throw new FinalException("Testing already stopped");
+        }
+        stopped = true;
+        AsyncApex.run();
+        Limits.end();
+    }
+
+    // setMock registers the mock that callouts of the interface, such as
+    // HttpCalloutMock, go to for the rest of the test.
+    public static void setMock(Type interfaceType, Object instance) {
+        if (instance == null || !interfaceType.raw.isInstance(instance)) {
+            throw new TypeException("Mock does not implement " + interfaceType.getName());
+        }
+        mocks.put(interfaceType.raw, instance);
+    }
+
+    static <T> T mock(Class<T> interfaceType) {
+        return interfaceType.cast(mocks.get(interfaceType));
+    }
+
+    public static Boolean isRunningTest() {
+        return running;
+    }
+
+    // reset discards the records and state of the previous test, as Apex
+    // rolls them back after each test method.
+    public static void reset() {
+        DataStore.reset();
+        System.reset();
+        mocks.clear();
+        Limits.reset();
+        running = true;
+        started = false;
+        stopped = false;
+    }
+}
@@ -0,0 +1,73 @@
+package com.freedom_man.system;
+
+import java.time.LocalTime;
+import java.time.format.DateTimeFormatter;
+
+// Time is the Apex Time, a time of day without a time zone. Adding to a
+// time wraps around midnight.
+public class Time implements Comparable<Time> {
+    private static final DateTimeFormatter FORMAT = DateTimeFormatter.ofPattern("HH:mm:ss.SSS'Z'");
+
+    final LocalTime value;
+
+    Time(LocalTime value) {
+        this.value = value;
+    }
+
+    public static Time newInstance(Integer hour, Integer minute, Integer second, Integer millisecond) {
+        return new Time(LocalTime.of(hour, minute, second, millisecond * 1000000));
+    }
+
+    public Time addHours(Integer hours) {
+        return new Time(value.plusHours(hours));
+    }
+
+    public Time addMinutes(Integer minutes) {
+        return new Time(value.plusMinutes(minutes));
+    }
+
+    public Time addSeconds(Integer seconds) {
+        return new Time(value.plusSeconds(seconds));
+    }
+
+    public Time addMilliseconds(Integer milliseconds) {
+        return new Time(value.plusNanos(milliseconds * 1000000L));
+    }
+
+    public Integer hour() {
+        return value.getHour();
+    }
+
+    public Integer minute() {
+        return value.getMinute();
+    }
+
+    public Integer second() {
+        return value.getSecond();
+    }
+
+    public Integer millisecond() {
+        return value.getNano() / 1000000;
+    }
+
+    @Override
+    public int compareTo(Time other) {
+        return value.compareTo(other.value);
+    }
+
+    @Override
+    public boolean equals(Object o) {
+        return o instanceof Time && value.equals(((Time) o).value);
+    }
+
+    @Override
+    public int hashCode() {
+        return value.hashCode();
+    }
+
+    // toString returns the time as HH:mm:ss.SSSZ, as String.valueOf does.
+    @Override
+    public String toString() {
+        return value.format(FORMAT);
+    }
+}
@@ -0,0 +1,142 @@
+package com.freedom_man.system;
+
+import java.lang.reflect.Constructor;
+import java.lang.reflect.ParameterizedType;
+import java.math.BigDecimal;
+import java.util.Arrays;
+
+// Type is the Apex Type, the token of a type literal such as Foo.class or
+// List<Foo>.class and the result of Type.forName. It keeps the type
+// parameters Java erases, so that JSON.deserialize knows the element type
+// of a list.
+public class Type {
+    private static final String RUNTIME_PACKAGE = "com.freedom_man.system.";
+
+    final Class<?> raw;
+    final Type[] parameters;
+
+    private Type(Class<?> raw, Type[] parameters) {
+        this.raw = raw;
+        this.parameters = parameters;
+    }
+
+    public static Type of(Class<?> raw, Type... parameters) {
+        return new Type(raw, parameters);
+    }
+
+    // reflected returns the type of a reflected field type. Type variables and
+    // wildcards are read as Object.
+    static Type reflected(java.lang.reflect.Type type) {
+        if (type instanceof Class) {
+            return new Type((Class<?>) type, new Type[0]);
+        }
+        if (type instanceof ParameterizedType) {
+            ParameterizedType p = (ParameterizedType) type;
+            java.lang.reflect.Type[] arguments = p.getActualTypeArguments();
+            Type[] parameters = new Type[arguments.length];
+            for (int i = 0; i < arguments.length; i++) {
+                parameters[i] = reflected(arguments[i]);
+            }
+            return new Type((Class<?>) p.getRawType(), parameters);
+        }
+        return new Type(Object.class, new Type[0]);
+    }
+
+    // forName returns the type named by an Apex name, such as Outer.Inner
+    // for an inner class, or null if there is none. Converted classes are
+    // looked up in the default package, then the runtime's and java.lang's.
+    public static Type forName(String name) {
+        if (name == null) {
+            return null;
+        }
+        Class<?> c = lookup(name.trim());
+        return c == null ? null : new Type(c, new Type[0]);
+    }
+
+    public static Type forName(String namespace, String name) {
+        if (namespace == null || namespace.isEmpty()) {
+            return forName(name);
+        }
+        return forName(namespace + "." + name);
+    }
+
+    private static Class<?> lookup(String name) {
+        if (name.equalsIgnoreCase("Decimal")) {
+            return BigDecimal.class;
+        }
+        String binary = name;
+        while (true) {
+            for (String prefix : new String[]{"", RUNTIME_PACKAGE, "java.lang."}) {
+                try {
+                    return Class.forName(prefix + binary);
+                } catch (ClassNotFoundException e) {
+                    // try the next package
+                }
+            }
+            int dot = binary.lastIndexOf('.');
+            if (dot < 0) {
+                return null;
+            }
+            binary = binary.substring(0, dot) + "$" + binary.substring(dot + 1);
+        }
+    }
+
+    // getName returns the Apex name of the type, such as List<Outer.Inner>.
+    public String getName() {
+        String name = raw.getName();
+        if (raw == BigDecimal.class) {
+            name = "Decimal";
+        } else if (name.startsWith(RUNTIME_PACKAGE)) {
+            name = name.substring(RUNTIME_PACKAGE.length());
+        } else if (name.startsWith("java.lang.")) {
+            name = name.substring("java.lang.".length());
+        }
+        name = name.replace('$', '.');
+        if (parameters.length == 0) {
+            return name;
+        }
+        StringBuilder sb = new StringBuilder(name).append('<');
+        for (int i = 0; i < parameters.length; i++) {
+            if (i > 0) {
+                sb.append(',');
+            }
+            sb.append(parameters[i].getName());
+        }
+        return sb.append('>').toString();
+    }
+
+    public Boolean isAssignableFrom(Type other) {
+        return other != null && raw.isAssignableFrom(other.raw);
+    }
+
+    // newInstance calls the constructor without parameters, which Apex
+    // requires the type to have.
+    public Object newInstance() {
+        try {
+            Constructor<?> c = raw.getDeclaredConstructor();
+            c.setAccessible(true);
+            return c.newInstance();
+        } catch (ReflectiveOperationException e) {
+            throw new TypeException("Type cannot be constructed: " + getName());
+        }
+    }
+
+    @Override
+    public boolean equals(Object o) {
+        if (!(o instanceof Type)) {
+            return false;
+        }
+        Type t = (Type) o;
+        return raw.equals(t.raw) && Arrays.equals(parameters, t.parameters);
+    }
+
+    @Override
+    public int hashCode() {
+        return raw.hashCode() * 31 + Arrays.hashCode(parameters);
+    }
+
+    @Override
+    public String toString() {
+        return getName();
+    }
+}
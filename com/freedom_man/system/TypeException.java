@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class TypeException extends Exception {
+    public TypeException() {
+        super();
+    }
+
+    public TypeException(String message) {
+        super(message);
+    }
+
+    public TypeException(Exception cause) {
+        super(cause);
+    }
+
+    public TypeException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class UnexpectedException extends Exception {
+    public UnexpectedException() {
+        super();
+    }
+
+    public UnexpectedException(String message) {
+        super(message);
+    }
+
+    public UnexpectedException(Exception cause) {
+        super(cause);
+    }
+
+    public UnexpectedException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
@@ -0,0 +1,9 @@
+package com.freedom_man.system;
+
+// Unsupported stands in for the Apex expressions apex2java cannot convert,
+// which lenient conversions emit as calls of raise.
+public class Unsupported {
+    public static <T> T raise(String construct) {
+        throw new UnsupportedOperationException(construct);
+    }
+}
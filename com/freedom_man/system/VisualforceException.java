@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class VisualforceException extends Exception {
+    public VisualforceException() {
+        super();
+    }
+
+    public VisualforceException(String message) {
+        super(message);
+    }
+
+    public VisualforceException(Exception cause) {
+        super(cause);
+    }
+
+    public VisualforceException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
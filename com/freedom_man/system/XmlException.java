@@ -0,0 +1,19 @@
+package com.freedom_man.system;
+
+public class XmlException extends Exception {
+    public XmlException() {
+        super();
+    }
+
+    public XmlException(String message) {
+        super(message);
+    }
+
+    public XmlException(Exception cause) {
+        super(cause);
+    }
+
+    public XmlException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
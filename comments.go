@@ -0,0 +1,353 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// Comment is a comment of the Apex source. The lexer skips comments, so
+// they are read from the source and attached to the declarations and
+// statements they precede, or follow on the same line.
+type Comment struct {
+	Text    string
+	Line    int
+	Column  int
+	EndLine int
+	// EndColumn is the column after the comment.
+	EndColumn int
+	// End is the byte offset after the comment.
+	End int
+	// Trailing is set for comments following code on their line.
+	Trailing bool
+}
+
+// readComments returns the comments of src in order, with lines counted
+// from 1 and columns counted in characters from 0 like the parser does.
+func readComments(src string) []*Comment {
+	comments := []*Comment{}
+	line, lineStart := 1, 0
+	code := false
+	for i := 0; i < len(src); i++ {
+		switch {
+		case src[i] == '\n':
+			line, lineStart, code = line+1, i+1, false
+		case src[i] == '\'':
+			code = true
+			for i++; i < len(src) && src[i] != '\'' && src[i] != '\n'; i++ {
+				if src[i] == '\\' && i+1 < len(src) && src[i+1] != '\n' {
+					i++
+				}
+			}
+			if i < len(src) && src[i] == '\n' {
+				i--
+			}
+		case src[i] == '/' && i+1 < len(src) && (src[i+1] == '/' || src[i+1] == '*'):
+			end := strings.Index(src[i:], "\n")
+			if src[i+1] == '*' {
+				end = strings.Index(src[i+2:], "*/")
+				if end >= 0 {
+					end += 4
+				}
+			}
+			if end < 0 {
+				end = len(src) - i
+			}
+			text := src[i : i+end]
+			c := &Comment{
+				Text:     strings.TrimRight(text, " \t\r"),
+				Line:     line,
+				Column:   utf8.RuneCountInString(src[lineStart:i]),
+				Trailing: code,
+			}
+			if n := strings.Count(text, "\n"); n > 0 {
+				line += n
+				lineStart = i + strings.LastIndex(text, "\n") + 1
+				code = false
+			}
+			c.EndLine = line
+			c.EndColumn = utf8.RuneCountInString(src[lineStart : i+end])
+			c.End = i + end
+			comments = append(comments, c)
+			i += end - 1
+		case src[i] != ' ' && src[i] != '\t' && src[i] != '\r':
+			code = true
+		}
+	}
+	return comments
+}
+
+// commentAnchors returns the nodes Generator emits comments with: the root,
+// the members of classes and interfaces and the statements of blocks,
+// sorted by location.
+func commentAnchors(root ast.Node) []ast.Node {
+	anchors := []ast.Node{root}
+	walkNodes(root, func(n ast.Node, _ *ast.Location) {
+		switch n := n.(type) {
+		case *ast.ClassDeclaration:
+			anchors = append(anchors, n.Declarations...)
+		case *ast.InterfaceDeclaration:
+			for _, m := range n.Methods {
+				anchors = append(anchors, m)
+			}
+		case *ast.Block:
+			anchors = append(anchors, n.Statements...)
+		case *ast.If:
+			// bodies without braces are emitted as blocks
+			for _, s := range []ast.Node{n.IfStatement, n.ElseStatement} {
+				switch s.(type) {
+				case nil, *ast.Block, *ast.If:
+				default:
+					anchors = append(anchors, s)
+				}
+			}
+		}
+	})
+	located := anchors[:0]
+	for _, a := range anchors {
+		if nodeLocation(a) != nil {
+			located = append(located, a)
+		}
+	}
+	sort.SliceStable(located, func(i, j int) bool {
+		return before(nodeLocation(located[i]), nodeLocation(located[j]))
+	})
+	return located
+}
+
+func before(a, b *ast.Location) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
+
+// attachComments attaches the comments of the source to the anchors of
+// root. A comment leads the first anchor after it; a single line comment
+// following code trails the last anchor starting on its line before it. A
+// comment followed by the closing brace of a block or type declaration
+// closes it. Comments after the last anchor trail the root.
+func (v *Generator) attachComments(root ast.Node) {
+	v.leadingComments = map[ast.Node][]*Comment{}
+	v.trailingComments = map[ast.Node][]*Comment{}
+	v.closingComments = map[ast.Node][]*Comment{}
+	anchors := commentAnchors(root)
+	bodies := commentBodies(root)
+	code := blankNonCode(v.Source)
+	for _, c := range readComments(v.Source) {
+		if c.Trailing && c.Line == c.EndLine {
+			var anchor ast.Node
+			for _, a := range anchors {
+				l := nodeLocation(a)
+				if l.Line == c.Line && l.Column < c.Column {
+					anchor = a
+				}
+			}
+			if anchor != nil {
+				v.trailingComments[anchor] = append(v.trailingComments[anchor], c)
+				continue
+			}
+		}
+		if body := closedBody(bodies, code, c); body != nil {
+			v.closingComments[body] = append(v.closingComments[body], c)
+			continue
+		}
+		i := sort.Search(len(anchors), func(i int) bool {
+			return before(&ast.Location{Line: c.EndLine, Column: c.EndColumn - 1}, nodeLocation(anchors[i]))
+		})
+		if i < len(anchors) {
+			v.leadingComments[anchors[i]] = append(v.leadingComments[anchors[i]], c)
+		} else {
+			v.trailingComments[root] = append(v.trailingComments[root], c)
+		}
+	}
+}
+
+// commentBodies returns the blocks and type declarations under root, which
+// comments can close, sorted by location.
+func commentBodies(root ast.Node) []ast.Node {
+	bodies := []ast.Node{}
+	walkNodes(root, func(n ast.Node, _ *ast.Location) {
+		switch n.(type) {
+		case *ast.Block, *ast.ClassDeclaration, *ast.InterfaceDeclaration:
+			if n.GetLocation() != nil {
+				bodies = append(bodies, n)
+			}
+		}
+	})
+	sort.SliceStable(bodies, func(i, j int) bool {
+		return before(bodies[i].GetLocation(), bodies[j].GetLocation())
+	})
+	return bodies
+}
+
+// closedBody returns the block or type declaration of bodies the comment c
+// ends, the one whose closing brace is the first code after c, or nil. code
+// is the source with comments and string literals blanked.
+func closedBody(bodies []ast.Node, code string, c *Comment) ast.Node {
+	i := c.End
+	for i < len(code) && strings.ContainsRune(" \t\r\n", rune(code[i])) {
+		i++
+	}
+	if i == len(code) || code[i] != '}' {
+		return nil
+	}
+	depth := 0
+	for i--; i >= 0; i-- {
+		switch code[i] {
+		case '}':
+			depth++
+		case '{':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			lineStart := strings.LastIndex(code[:i], "\n") + 1
+			open := &ast.Location{
+				Line:   strings.Count(code[:i], "\n") + 1,
+				Column: utf8.RuneCountInString(code[lineStart:i]),
+			}
+			// a block starts at its brace, and the body of a type
+			// declaration follows the last declaration starting before it
+			var body ast.Node
+			for _, b := range bodies {
+				l := b.GetLocation()
+				if _, ok := b.(*ast.Block); ok {
+					if l.Line == open.Line && l.Column == open.Column {
+						return b
+					}
+				} else if before(l, open) {
+					body = b
+				}
+			}
+			return body
+		}
+	}
+	return nil
+}
+
+// closingLines returns the lines of the comments closing the block or type
+// declaration n, or nil.
+func (v *Generator) closingLines(n ast.Node) javaLines {
+	var lines javaLines
+	for _, c := range v.closingComments[n] {
+		lines = append(lines, formatComment(c)...)
+	}
+	return lines
+}
+
+// sourced returns node, the Java of the anchor n, with the comments
+// attached to n and the TODO comments added since the todos had length
+// start.
+func (v *Generator) sourced(n ast.Node, node javaNode, start int) javaNode {
+	s := &javaSourced{Node: node}
+	if loc := nodeLocation(n); loc != nil {
+		s.Line = loc.Line
+	}
+	for _, c := range v.leadingComments[n] {
+		s.Leading = append(s.Leading, formatComment(c)...)
+	}
+	s.Leading = append(s.Leading, v.takeTodos(start)...)
+	if n != v.root {
+		for _, c := range v.trailingComments[n] {
+			s.Trailing = append(s.Trailing, c.Text)
+		}
+	}
+	return s
+}
+
+// rootComments returns the lines of the comments after the last anchor,
+// which follow the root.
+func (v *Generator) rootComments() []string {
+	lines := []string{}
+	for _, c := range v.trailingComments[v.root] {
+		lines = append(lines, formatComment(c)...)
+	}
+	return lines
+}
+
+// formatComment returns the lines of the comment. ApexDoc comments are
+// rewritten as Javadoc; the continuation lines of other block comments keep
+// their indent relative to the comment.
+func formatComment(c *Comment) []string {
+	if isApexDoc(c.Text) {
+		return javadoc(c.Text)
+	}
+	lines := strings.Split(c.Text, "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = strings.TrimRight(trimIndent(lines[i], c.Column), " \t\r")
+	}
+	return lines
+}
+
+func isApexDoc(text string) bool {
+	return strings.HasPrefix(text, "/**") && text != "/**/"
+}
+
+// trimIndent removes up to width columns of leading white space from line.
+func trimIndent(line string, width int) string {
+	i := 0
+	for i < len(line) && i < width && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	return line[i:]
+}
+
+// javadoc returns the ApexDoc comment text as Javadoc. The text of
+// @description becomes the main description and @returns becomes @return;
+// @param, @return and other tags are kept.
+func javadoc(text string) []string {
+	body := strings.TrimSuffix(strings.TrimPrefix(text, "/**"), "*/")
+	description := []string{}
+	tags := []string{}
+	inTag := false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		lower := strings.ToLower(line)
+		switch {
+		case strings.HasPrefix(lower, "@description"):
+			inTag = false
+			line = strings.TrimSpace(line[len("@description"):])
+			if line != "" {
+				description = append(description, line)
+			}
+		case strings.HasPrefix(lower, "@returns"):
+			inTag = true
+			tags = append(tags, "@return"+line[len("@returns"):])
+		case strings.HasPrefix(line, "@"):
+			inTag = true
+			tags = append(tags, line)
+		case inTag:
+			if line != "" {
+				tags[len(tags)-1] += " " + line
+			}
+		default:
+			description = append(description, line)
+		}
+	}
+	description = trimBlankLines(description)
+	lines := []string{"/**"}
+	for _, line := range description {
+		lines = append(lines, strings.TrimRight(" * "+line, " "))
+	}
+	if len(description) > 0 && len(tags) > 0 {
+		lines = append(lines, " *")
+	}
+	for _, tag := range tags {
+		lines = append(lines, " * "+tag)
+	}
+	return append(lines, " */")
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/tzmfreedom/land/ast"
+	"github.com/tzmfreedom/land/parser"
+)
+
+// EnumDeclaration is an Apex enum. land's AST builder has no node for enums,
+// so they are read from the parse tree before the AST is built.
+type EnumDeclaration struct {
+	Modifiers []*ast.Modifier
+	Name      string
+	Values    []string
+	Location  *ast.Location
+	Parent    ast.Node
+}
+
+// EnumVisitor is implemented by visitors that handle EnumDeclaration.
+// Visitors without it skip enums.
+type EnumVisitor interface {
+	VisitEnumDeclaration(n *EnumDeclaration) (interface{}, error)
+}
+
+func (n *EnumDeclaration) Accept(v ast.Visitor) (interface{}, error) {
+	if ev, ok := v.(EnumVisitor); ok {
+		return ev.VisitEnumDeclaration(n)
+	}
+	return nil, nil
+}
+
+func (n *EnumDeclaration) GetChildren() []interface{} {
+	return []interface{}{
+		n.Modifiers,
+		n.Name,
+		n.Values,
+	}
+}
+
+func (n *EnumDeclaration) GetType() string {
+	return "EnumDeclaration"
+}
+
+func (n *EnumDeclaration) GetParent() ast.Node {
+	return n.Parent
+}
+
+func (n *EnumDeclaration) SetParent(parent ast.Node) {
+	n.Parent = parent
+}
+
+func (n *EnumDeclaration) GetLocation() *ast.Location {
+	return n.Location
+}
+
+func newEnumDeclaration(ctx *parser.EnumDeclarationContext, modifiers []antlr.ParseTree, src string) *EnumDeclaration {
+	n := &EnumDeclaration{
+		Name:      ctx.ApexIdentifier().GetText(),
+		Modifiers: []*ast.Modifier{},
+		Values:    []string{},
+		Location:  newLocation(ctx, src),
+	}
+	for _, m := range modifiers {
+		if strings.HasPrefix(m.GetText(), "@") {
+			continue
+		}
+		n.Modifiers = append(n.Modifiers, &ast.Modifier{
+			Name:     m.GetText(),
+			Location: newLocation(m.(antlr.ParserRuleContext), src),
+			Parent:   n,
+		})
+	}
+	if constants := ctx.EnumConstants(); constants != nil {
+		for _, c := range constants.(*parser.EnumConstantsContext).AllEnumConstant() {
+			n.Values = append(n.Values, c.(*parser.EnumConstantContext).ApexIdentifier().GetText())
+		}
+	}
+	return n
+}
+
+func newLocation(ctx antlr.ParserRuleContext, src string) *ast.Location {
+	return &ast.Location{
+		FileName: src,
+		Column:   ctx.GetStart().GetColumn(),
+		Line:     ctx.GetStart().GetLine(),
+	}
+}
+
+// liftEnums removes the enums declared in class bodies from the parse tree,
+// so that the AST builder does not see them, and returns them keyed by the
+// location of the declaring class.
+func liftEnums(tree antlr.Tree, src string) map[ast.Location][]*EnumDeclaration {
+	enums := map[ast.Location][]*EnumDeclaration{}
+	var walk func(t antlr.Tree)
+	walk = func(t antlr.Tree) {
+		children := t.GetChildren()
+		body, isClassBody := t.(*parser.ClassBodyContext)
+		for i, child := range children {
+			if d, ok := child.(*parser.ClassBodyDeclarationContext); ok && isClassBody {
+				if e := memberEnum(d); e != nil {
+					if class, ok := body.GetParent().(*parser.ClassDeclarationContext); ok {
+						modifiers := make([]antlr.ParseTree, len(d.AllModifier()))
+						for j, m := range d.AllModifier() {
+							modifiers[j] = m
+						}
+						loc := *newLocation(class, src)
+						enums[loc] = append(enums[loc], newEnumDeclaration(e, modifiers, src))
+						// A terminal node is not a ClassBodyDeclarationContext,
+						// so the builder no longer finds the enum.
+						children[i] = antlr.NewTerminalNodeImpl(d.GetStart())
+						continue
+					}
+				}
+			}
+			walk(child)
+		}
+	}
+	walk(tree)
+	return enums
+}
+
+func memberEnum(d *parser.ClassBodyDeclarationContext) *parser.EnumDeclarationContext {
+	member := d.MemberDeclaration()
+	if member == nil {
+		return nil
+	}
+	if e := member.(*parser.MemberDeclarationContext).EnumDeclaration(); e != nil {
+		return e.(*parser.EnumDeclarationContext)
+	}
+	return nil
+}
+
+// topLevelEnum returns the enum declared by a file that contains only an
+// enum, or nil.
+func topLevelEnum(tree *parser.CompilationUnitContext, src string) *EnumDeclaration {
+	t := tree.TypeDeclaration().(*parser.TypeDeclarationContext)
+	e := t.EnumDeclaration()
+	if e == nil {
+		return nil
+	}
+	modifiers := make([]antlr.ParseTree, len(t.AllClassOrInterfaceModifier()))
+	for i, m := range t.AllClassOrInterfaceModifier() {
+		modifiers[i] = m
+	}
+	return newEnumDeclaration(e.(*parser.EnumDeclarationContext), modifiers, src)
+}
+
+// attachEnums adds the enums returned by liftEnums to the declarations of
+// the classes declaring them, in source order.
+func attachEnums(n ast.Node, enums map[ast.Location][]*EnumDeclaration) {
+	class, ok := n.(*ast.ClassDeclaration)
+	if !ok {
+		return
+	}
+	for _, d := range class.Declarations {
+		attachEnums(d, enums)
+	}
+	for _, e := range enums[*class.Location] {
+		e.SetParent(class)
+		i := 0
+		for i < len(class.Declarations) && locatedBefore(class.Declarations[i].GetLocation(), e.Location) {
+			i++
+		}
+		class.Declarations = append(class.Declarations[:i], append([]ast.Node{e}, class.Declarations[i:]...)...)
+	}
+}
+
+func locatedBefore(a, b *ast.Location) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
+
+// enumNames returns the lowercased names of the enums declared in nodes.
+func enumNames(nodes ...ast.Node) map[string]struct{} {
+	names := map[string]struct{}{}
+	var walk func(n ast.Node)
+	walk = func(n ast.Node) {
+		switch decl := n.(type) {
+		case *EnumDeclaration:
+			names[strings.ToLower(decl.Name)] = struct{}{}
+		case *ast.ClassDeclaration:
+			for _, d := range decl.Declarations {
+				walk(d)
+			}
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+	return names
+}
+
+// enumMethod reports whether n calls values() or valueOf() on an enum type,
+// returning the type name and the lowercased method name. Apex enums return
+// a List from values(), so these calls go through the runtime. A receiver
+// naming a variable in scope is not a type, even if an enum has its name.
+func (v *Generator) enumMethod(n *ast.MethodInvocation) ([]string, string, bool) {
+	name, ok := n.NameOrExpression.(*ast.Name)
+	if !ok || len(name.Value) < 2 {
+		return nil, "", false
+	}
+	if _, ok := v.env.Get(name.Value[0]); ok {
+		return nil, "", false
+	}
+	method := strings.ToLower(name.Value[len(name.Value)-1])
+	if method != "values" && method != "valueof" {
+		return nil, "", false
+	}
+	enumType := name.Value[:len(name.Value)-1]
+	if _, ok := v.enums[strings.ToLower(enumType[len(enumType)-1])]; !ok {
+		return nil, "", false
+	}
+	return enumType, method, true
+}
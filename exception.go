@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// implicitExceptionConstructors are the constructors Apex gives every custom
+// exception without them being declared.
+var implicitExceptionConstructors = [][]string{
+	{},
+	{"String message"},
+	{"Exception cause"},
+	{"String message", "Exception cause"},
+}
+
+// isExceptionClass reports whether n declares a custom exception. Apex
+// requires their names to end with Exception and they always extend
+// Exception or another custom exception.
+func isExceptionClass(n *ast.ClassDeclaration) bool {
+	if n.SuperClassRef == nil || !strings.HasSuffix(strings.ToLower(n.Name), "exception") {
+		return false
+	}
+	super := apexTypeName(n.SuperClassRef.Name)
+	return strings.HasSuffix(strings.ToLower(super[len(super)-1]), "exception")
+}
+
+// exceptionConstructors returns the implicit constructors of the exception
+// class n that are not declared explicitly.
+func (v *Generator) exceptionConstructors(n *ast.ClassDeclaration) []javaNode {
+	declared := map[string]struct{}{}
+	for _, d := range n.Declarations {
+		if c, ok := d.(*ast.ConstructorDeclaration); ok {
+			types := make([]string, len(c.Parameters))
+			for i, p := range c.Parameters {
+				types[i] = strings.ToLower(strings.Join(apexTypeName(p.TypeRef.Name), "."))
+			}
+			declared[strings.Join(types, ",")] = struct{}{}
+		}
+	}
+	constructors := []javaNode{}
+	for _, params := range implicitExceptionConstructors {
+		types := make([]string, len(params))
+		args := make([]string, len(params))
+		for i, p := range params {
+			parts := strings.Split(p, " ")
+			types[i] = strings.ToLower(parts[0])
+			args[i] = parts[1]
+		}
+		if _, ok := declared[strings.Join(types, ",")]; ok {
+			continue
+		}
+		constructors = append(constructors, &javaMethod{
+			javaHeader: javaHeader{Modifiers: []string{"public"}},
+			Name:       n.Name,
+			Parameters: params,
+			Body:       newBlock(fmt.Sprintf("super(%s)", strings.Join(args, ", "))),
+		})
+	}
+	return constructors
+}
+
+// translateJVMExceptions wraps the body of a try statement with catch
+// clauses in a try that rethrows the exceptions of the JVM, such as
+// java.lang.NullPointerException, as the System exceptions Apex throws, so
+// that the catch clauses see them.
+func (v *Generator) translateJVMExceptions(body *javaBlock) *javaBlock {
+	name := "jvmException"
+	for i := 2; ; i++ {
+		if _, ok := v.env.Get(name); !ok {
+			break
+		}
+		name = fmt.Sprintf("jvmException%d", i)
+	}
+	try := newCompound("try", body)
+	try.Clauses = append(try.Clauses, &javaClause{
+		Header: fmt.Sprintf("catch (RuntimeException %s)", name),
+		Body:   newBlock(fmt.Sprintf("throw Exception.translate(%s)", name)),
+	})
+	return &javaBlock{Statements: []javaNode{try}}
+}
@@ -2,133 +2,249 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/tzmfreedom/land/ast"
 )
 
 type Generator struct {
-	Indent int
+	// NullSafeArithmetic emits arithmetic on boxed Apex primitives through
+	// runtime helpers that raise Apex's System.NullPointerException.
+	NullSafeArithmetic bool
+	// RuntimeVersion, if set, is the runtime version top level classes and
+	// enums check when they are loaded.
+	RuntimeVersion string
+	// Lenient emits placeholders for the Apex that is not supported instead
+	// of failing. Source is the Apex source, quoted in their TODO comments.
+	Lenient bool
+	Source  string
+	// Enums are the lowercased names of the enums declared in the other
+	// files converted with this one, which Enums.values and Enums.valueOf
+	// are emitted for as for the enums of this file.
+	Enums map[string]struct{}
+
+	env     *typeEnv
+	methods map[string]*ast.TypeRef
+	enums   map[string]struct{}
+	// imports holds the ImportClasses keys of the runtime classes chosen
+	// from inferred types, which the ImportTypeResolver cannot see.
+	imports map[string]struct{}
+	// targets are the declared or assigned types of the initializers and
+	// the right sides of assignments, which placeholders take.
+	targets map[ast.Node]*ast.TypeRef
+	// todos are the TODO comments of the unsupported nodes of the statement
+	// or declaration being generated.
+	todos []string
+	// root is the node being generated, and the comments of the source
+	// are attached to it and the declarations and statements under it.
+	root             ast.Node
+	leadingComments  map[ast.Node][]*Comment
+	trailingComments map[ast.Node][]*Comment
+	// closingComments are the comments after the last statement or member
+	// of a block or type declaration, which go at its end.
+	closingComments map[ast.Node][]*Comment
+	// lines are the Apex lines of the lines generated last, for SourceMap.
+	lines []int
 }
 
-func (v *Generator) AddIndent(f func()) {
-	v.Indent += 4
+func (v *Generator) importClass(key string) {
+	if v.imports == nil {
+		v.imports = map[string]struct{}{}
+	}
+	v.imports[key] = struct{}{}
+}
+
+// importType imports the runtime classes the type t names, with its type
+// parameters, as the ImportTypeResolver does for the types in the source.
+func (v *Generator) importType(t *ast.TypeRef) {
+	key := strings.ToLower(apexTypeName(t.Name)[0])
+	if _, ok := ImportClasses[key]; ok {
+		v.importClass(key)
+	}
+	for _, p := range t.Parameters {
+		v.importType(p)
+	}
+}
+
+// setTarget records t as the type the expression n is assigned to.
+func (v *Generator) setTarget(n ast.Node, t *ast.TypeRef) {
+	if n == nil || t == nil {
+		return
+	}
+	if v.targets == nil {
+		v.targets = map[ast.Node]*ast.TypeRef{}
+	}
+	v.targets[n] = t
+}
+
+// JavaTypeNames maps Apex type names to the Java types they are emitted as.
+var JavaTypeNames = map[string]string{
+	"decimal":               "BigDecimal",
+	"describefieldresult":   "Schema.DescribeFieldResult",
+	"describesobjectresult": "Schema.DescribeSObjectResult",
+	"displaytype":           "Schema.DisplayType",
+	"picklistentry":         "Schema.PicklistEntry",
+	"recordtypeinfo":        "Schema.RecordTypeInfo",
+	"sobjectfield":          "Schema.SObjectField",
+	"sobjecttype":           "Schema.SObjectType",
+	"httprequest":           "HttpRequest",
+	"httpresponse":          "HttpResponse",
+	"id":                    "String",
+	"sobject":               "SObject",
+}
+
+func (v *Generator) withScope(f func()) {
+	v.env = newTypeEnv(v.env)
 	f()
-	v.Indent -= 4
+	v.env = v.env.parent
 }
 
-func (v *Generator) withIndent(src string) string {
-	return strings.Repeat(" ", v.Indent) + src
+func (v *Generator) declare(name string, t *ast.TypeRef) {
+	if v.env == nil {
+		v.env = newTypeEnv(nil)
+	}
+	v.env.Set(name, t)
 }
 
 func (v *Generator) VisitClassDeclaration(n *ast.ClassDeclaration) (interface{}, error) {
-	annotations := make([]string, len(n.Annotations))
-	for i, a := range n.Annotations {
-		r, err := a.Accept(v)
-		if err != nil {
-			return nil, err
-		}
-		annotations[i] = r.(string)
-	}
-	annotationStr := ""
-	if len(annotations) != 0 {
-		annotationStr = fmt.Sprintf("%s\n", strings.Join(annotations, "\n"))
+	header, err := v.declarationHeader(n.Annotations, n.Modifiers)
+	if err != nil {
+		return nil, err
 	}
-	modifiers := make([]string, len(n.Modifiers))
-	for i, m := range n.Modifiers {
-		r, err := m.Accept(v)
-		if err != nil {
-			return nil, err
+	if n.Parent == nil && hasModifier(n.Modifiers, "private") {
+		// private top level classes are Apex test classes, which Java
+		// only allows to be package-private.
+		header.Modifiers = removeModifier(header.Modifiers, "private")
+	}
+	if _, ok := n.Parent.(*ast.ClassDeclaration); ok && !hasModifier(n.Modifiers, "static") {
+		// Apex inner classes cannot reach the instance of the outer class.
+		header.Modifiers = append(header.Modifiers, "static")
+	}
+	class := &javaTypeDeclaration{javaHeader: header, Kind: "class", Name: n.Name}
+	methods := v.methods
+	v.methods = map[string]*ast.TypeRef{}
+	v.withScope(func() {
+		v.env.members = true
+		for _, d := range n.Declarations {
+			switch decl := d.(type) {
+			case *ast.FieldDeclaration:
+				for _, d := range decl.Declarators {
+					v.declare(d.Name, decl.TypeRef)
+				}
+			case *ast.PropertyDeclaration:
+				v.declare(decl.Identifier, decl.TypeRef)
+			case *ast.MethodDeclaration:
+				if decl.ReturnType != nil {
+					v.methods[strings.ToLower(decl.Name)] = decl.ReturnType
+				}
+			}
 		}
-		modifiers[i] = r.(string)
-	}
-	declarations := make([]string, len(n.Declarations))
-	v.AddIndent(func() {
-		for i, d := range n.Declarations {
+		if n.Parent == nil && v.RuntimeVersion != "" {
+			class.Members = append(class.Members, v.runtimeVersionCheck())
+		}
+		for _, d := range n.Declarations {
+			start := len(v.todos)
 			r, err := d.Accept(v)
 			if err != nil {
 				panic(err)
 			}
-			declarations[i] = r.(string)
+			if nodes, ok := r.(javaNodes); ok {
+				// the members emitted in place of one, such as for a
+				// @future method, are separated like the others
+				class.Members = append(class.Members, v.sourced(d, nodes[0], start))
+				class.Members = append(class.Members, nodes[1:]...)
+				continue
+			}
+			class.Members = append(class.Members, v.sourced(d, r.(javaNode), start))
+		}
+		if lines := v.closingLines(n); len(lines) != 0 {
+			class.Members = append(class.Members, lines)
+		}
+		if isTestClass(n) && !hasTestSetupMethod(n) {
+			class.Members = append(class.Members, v.junitResetMethod())
+		}
+		if isExceptionClass(n) {
+			class.Members = append(class.Members, v.exceptionConstructors(n)...)
 		}
 	})
-	super := ""
+	v.methods = methods
 	if n.SuperClassRef != nil {
 		r, err := n.SuperClassRef.Accept(v)
 		if err != nil {
 			return nil, err
 		}
-		super = "extends " + r.(string)
+		class.Extends = r.(string)
 	}
-	implements := make([]string, len(n.ImplementClassRefs))
-	for i, impl := range n.ImplementClassRefs {
-		r, err := impl.Accept(v)
+	for _, impl := range n.ImplementClassRefs {
+		r, err := implementedType(n, impl).Accept(v)
 		if err != nil {
 			return nil, err
 		}
-		implements[i] = r.(string)
-	}
-	implString := ""
-	if len(implements) != 0 {
-		implString = "implements " + strings.Join(implements, ", ")
+		class.Implements = append(class.Implements, r.(string))
 	}
-	body := ""
-	if len(declarations) != 0 {
-		body = fmt.Sprintf("%s\n", strings.Join(declarations, "\n"))
-	}
-	return fmt.Sprintf(
-		`%s%s class %s %s %s {
-%s%s`,
-		annotationStr,
-		strings.Join(modifiers, " "),
-		n.Name,
-		super,
-		implString,
-		body,
-		v.withIndent("}"),
-	), nil
+	return class, nil
 }
 
 func (v *Generator) VisitModifier(n *ast.Modifier) (interface{}, error) {
+	if name, ok := JavaModifiers[modifierName(n)]; ok {
+		return name, nil
+	}
 	return n.Name, nil
 }
 
-func (v *Generator) VisitAnnotation(n *ast.Annotation) (interface{}, error) {
-	return n.Name, nil
+// declarationHeader returns the Java annotations and modifiers of a
+// declaration.
+func (v *Generator) declarationHeader(as []*ast.Annotation, ms []*ast.Modifier) (javaHeader, error) {
+	annotations := make([]string, len(as))
+	for i, a := range as {
+		r, err := a.Accept(v)
+		if err != nil {
+			return javaHeader{}, err
+		}
+		annotations[i] = r.(string)
+	}
+	modifiers, modifierAnnotations, err := v.modifiers(ms)
+	if err != nil {
+		return javaHeader{}, err
+	}
+	if hasAnnotation(as, "testvisible") {
+		// test classes are emitted to the same package, where they can
+		// access package-private members
+		modifiers = removeModifier(modifiers, "private")
+	}
+	return javaHeader{Annotations: append(annotations, modifierAnnotations...), Modifiers: modifiers}, nil
+}
+
+func (v *Generator) VisitEnumDeclaration(n *EnumDeclaration) (interface{}, error) {
+	header, err := v.declarationHeader(nil, n.Modifiers)
+	if err != nil {
+		return nil, err
+	}
+	enum := &javaTypeDeclaration{javaHeader: header, Kind: "enum", Name: n.Name, Constants: n.Values}
+	if n.Parent == nil && v.RuntimeVersion != "" {
+		enum.Members = append(enum.Members, v.runtimeVersionCheck())
+	}
+	return enum, nil
 }
 
 func (v *Generator) VisitInterfaceDeclaration(n *ast.InterfaceDeclaration) (interface{}, error) {
-	modifiers := make([]string, len(n.Modifiers))
-	for i, m := range n.Modifiers {
+	header, err := v.declarationHeader(n.Annotations, n.Modifiers)
+	if err != nil {
+		return nil, err
+	}
+	iface := &javaTypeDeclaration{javaHeader: header, Kind: "interface", Name: n.Name}
+	for _, m := range n.Methods {
 		r, err := m.Accept(v)
 		if err != nil {
 			return nil, err
 		}
-		modifiers[i] = r.(string)
+		iface.Members = append(iface.Members, v.sourced(m, r.(javaNode), len(v.todos)))
 	}
-	methods := make([]string, len(n.Methods))
-	v.AddIndent(func() {
-		for i, m := range n.Methods {
-			r, err := m.Accept(v)
-			if err != nil {
-				panic(err)
-			}
-			methods[i] = r.(string)
-		}
-	})
-	body := ""
-	if len(methods) != 0 {
-		body = fmt.Sprintf("%s\n", strings.Join(methods, "\n"))
+	if lines := v.closingLines(n); len(lines) != 0 {
+		iface.Members = append(iface.Members, lines)
 	}
-
-	return fmt.Sprintf(
-		`%s interface %s {
-%s%s`,
-		strings.Join(modifiers, " "),
-		n.Name,
-		body,
-		v.withIndent("}"),
-	), nil
+	return iface, nil
 }
 
 func (v *Generator) VisitIntegerLiteral(n *ast.IntegerLiteral) (interface{}, error) {
@@ -140,6 +256,7 @@ func (v *Generator) VisitParameter(n *ast.Parameter) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	v.declare(n.Name, n.TypeRef)
 	return fmt.Sprintf(
 		"%s %s",
 		r.(string),
@@ -158,7 +275,7 @@ func (v *Generator) VisitArrayAccess(n *ast.ArrayAccess) (interface{}, error) {
 	}
 	return fmt.Sprintf(
 		"%s[%s]",
-		r.(string),
+		operand(n.Receiver, r.(string), primaryPrecedence),
 		k.(string),
 	), nil
 }
@@ -179,26 +296,35 @@ func (v *Generator) VisitContinue(n *ast.Continue) (interface{}, error) {
 	return "continue", nil
 }
 
+// VisitDml emits DML statements as calls to the runtime's Database, which
+// keeps the records in memory.
 func (v *Generator) VisitDml(n *ast.Dml) (interface{}, error) {
 	r, err := n.Expression.Accept(v)
 	if err != nil {
 		return nil, err
 	}
-	return fmt.Sprintf("%s %s", n.Type, r.(string)), nil
+	if n.UpsertKey != "" {
+		return fmt.Sprintf("Database.%s(%s, \"%s\")", strings.ToLower(n.Type), r.(string), n.UpsertKey), nil
+	}
+	return fmt.Sprintf("Database.%s(%s)", strings.ToLower(n.Type), r.(string)), nil
 }
 
 func (v *Generator) VisitDoubleLiteral(n *ast.DoubleLiteral) (interface{}, error) {
-	return fmt.Sprintf("%f", n.Value), nil
+	value := strconv.FormatFloat(n.Value, 'f', -1, 64)
+	if v.NullSafeArithmetic {
+		// Apex decimal literals are Decimals, not doubles.
+		return fmt.Sprintf("new BigDecimal(\"%s\")", value), nil
+	}
+	if !strings.Contains(value, ".") {
+		value += ".0"
+	}
+	return value, nil
 }
 
 func (v *Generator) VisitFieldDeclaration(n *ast.FieldDeclaration) (interface{}, error) {
-	modifiers := make([]string, len(n.Modifiers))
-	for i, m := range n.Modifiers {
-		r, err := m.Accept(v)
-		if err != nil {
-			return nil, err
-		}
-		modifiers[i] = r.(string)
+	header, err := v.declarationHeader(n.Annotations, n.Modifiers)
+	if err != nil {
+		return nil, err
 	}
 	r, err := n.TypeRef.Accept(v)
 	if err != nil {
@@ -206,52 +332,32 @@ func (v *Generator) VisitFieldDeclaration(n *ast.FieldDeclaration) (interface{},
 	}
 	declarators := make([]string, len(n.Declarators))
 	for i, decl := range n.Declarators {
-		r, err := decl.Accept(v)
+		r, err := v.declarator(decl, n.TypeRef)
 		if err != nil {
 			return nil, err
 		}
-		declarators[i] = r.(string)
+		declarators[i] = r
 	}
-
-	return fmt.Sprintf(
-		`%s %s %s;`,
-		v.withIndent(strings.Join(modifiers, " ")),
-		r.(string),
-		strings.Join(declarators, ", "),
-	), nil
-
+	return &javaField{javaHeader: header, Type: r.(string), Declarators: declarators}, nil
 }
 
 func (v *Generator) VisitTry(n *ast.Try) (interface{}, error) {
-	stmt := ""
-	v.AddIndent(func() {
-		r, err := n.Block.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		stmt = r.(string)
-	})
-	catches := make([]string, len(n.CatchClause))
-	for i, c := range n.CatchClause {
+	body := v.blockBody(n.Block)
+	if len(n.CatchClause) != 0 {
+		body = v.translateJVMExceptions(body)
+	}
+	try := newCompound("try", body)
+	for _, c := range n.CatchClause {
 		r, err := c.Accept(v)
 		if err != nil {
 			return nil, err
 		}
-		catches[i] = r.(string)
+		try.Clauses = append(try.Clauses, r.(*javaClause))
 	}
-	f, err := n.FinallyBlock.Accept(v)
-	if err != nil {
-		return nil, err
+	if n.FinallyBlock != nil {
+		try.Clauses = append(try.Clauses, &javaClause{Header: "finally", Body: v.blockBody(n.FinallyBlock)})
 	}
-	return fmt.Sprintf(
-		`try {
-%s%s%s
-%s`,
-		stmt,
-		strings.Join(catches, "\n"),
-		f.(string),
-		v.withIndent("}"),
-	), nil
+	return try, nil
 }
 
 func (v *Generator) VisitCatch(n *ast.Catch) (interface{}, error) {
@@ -259,70 +365,27 @@ func (v *Generator) VisitCatch(n *ast.Catch) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	stmt := ""
-	v.AddIndent(func() {
-		r, err := n.Block.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		stmt = r.(string)
-	})
-	if stmt != "" {
-		stmt = fmt.Sprintf("%s\n", stmt)
-	}
-	return fmt.Sprintf(
-		` catch (%s %s) {
-%s%s`,
-		t.(string),
-		n.Identifier,
-		stmt,
-		v.withIndent("}"),
-	), nil
+	v.env = newTypeEnv(v.env)
+	defer func() { v.env = v.env.parent }()
+	v.declare(n.Identifier, n.TypeRef)
+	return &javaClause{
+		Header: fmt.Sprintf("catch (%s %s)", t.(string), n.Identifier),
+		Body:   v.blockBody(n.Block),
+	}, nil
 }
 
 func (v *Generator) VisitFinally(n *ast.Finally) (interface{}, error) {
-	stmt := ""
-	v.AddIndent(func() {
-		r, err := n.Block.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		stmt = r.(string)
-	})
-	if stmt != "" {
-		stmt = fmt.Sprintf("%s\n", stmt)
-	}
-	return fmt.Sprintf(
-		` finally {
-%s%s`,
-		stmt,
-		v.withIndent("}"),
-	), nil
+	return &javaClause{Header: "finally", Body: v.blockBody(n.Block)}, nil
 }
 
 func (v *Generator) VisitFor(n *ast.For) (interface{}, error) {
+	v.env = newTypeEnv(v.env)
+	defer func() { v.env = v.env.parent }()
 	control, err := n.Control.Accept(v)
 	if err != nil {
 		return nil, err
 	}
-	stmt := ""
-	v.AddIndent(func() {
-		r, err := n.Statements.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		stmt = r.(string)
-	})
-	if stmt != "" {
-		stmt = fmt.Sprintf("%s\n", stmt)
-	}
-	return fmt.Sprintf(
-		`for (%s) {
-%s%s`,
-		control.(string),
-		stmt,
-		v.withIndent("}"),
-	), nil
+	return newCompound(fmt.Sprintf("for (%s)", control.(string)), v.blockBody(n.Statements)), nil
 }
 
 func (v *Generator) VisitForControl(n *ast.ForControl) (interface{}, error) {
@@ -334,23 +397,37 @@ func (v *Generator) VisitForControl(n *ast.ForControl) (interface{}, error) {
 		}
 		inits[i] = exp.(string)
 	}
-	exp, err := n.Expression.Accept(v)
-	if err != nil {
-		return nil, err
+	cond := ""
+	if n.Expression != nil {
+		exp, err := n.Expression.Accept(v)
+		if err != nil {
+			return nil, err
+		}
+		cond = " " + exp.(string)
 	}
 	updates := make([]string, len(n.ForUpdate))
 	for i, u := range n.ForUpdate {
+		if u, ok := u.(*ast.UnaryOperator); ok {
+			if r, ok := v.incrementStatement(u); ok {
+				updates[i] = r
+				continue
+			}
+		}
 		r, err := u.Accept(v)
 		if err != nil {
 			return nil, err
 		}
 		updates[i] = r.(string)
 	}
+	update := ""
+	if len(updates) != 0 {
+		update = " " + strings.Join(updates, ", ")
+	}
 	return fmt.Sprintf(
-		`%s %s; %s`,
+		`%s;%s;%s`,
 		strings.Join(inits, ", "),
-		exp.(string),
-		strings.Join(updates, ","),
+		cond,
+		update,
 	), nil
 }
 
@@ -363,6 +440,7 @@ func (v *Generator) VisitEnhancedForControl(n *ast.EnhancedForControl) (interfac
 	if err != nil {
 		return nil, err
 	}
+	v.declare(n.VariableDeclaratorId, n.TypeRef)
 	return fmt.Sprintf(
 		`%s %s : %s`,
 		t.(string),
@@ -376,106 +454,87 @@ func (v *Generator) VisitIf(n *ast.If) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	ifStmt := ""
-	v.AddIndent(func() {
-		r, err := n.IfStatement.Accept(v)
+	stmt := newCompound(fmt.Sprintf("if (%s)", cond.(string)), v.blockBody(n.IfStatement))
+	switch e := n.ElseStatement.(type) {
+	case nil:
+	case *ast.If:
+		// else if chains stay flat instead of nesting an if in an else block.
+		r, err := e.Accept(v)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
-		ifStmt = r.(string)
-	})
-	if ifStmt != "" {
-		ifStmt = fmt.Sprintf("%s\n", ifStmt)
+		clauses := r.(*javaCompound).Clauses
+		stmt.Clauses = append(stmt.Clauses, &javaClause{Header: "else " + clauses[0].Header, Body: clauses[0].Body})
+		stmt.Clauses = append(stmt.Clauses, clauses[1:]...)
+	default:
+		stmt.Clauses = append(stmt.Clauses, &javaClause{Header: "else", Body: v.blockBody(e)})
 	}
-	elseStmt := ""
-	if n.ElseStatement != nil {
-		v.AddIndent(func() {
-			r, err := n.IfStatement.Accept(v)
-			if err != nil {
-				panic(err)
-			}
-			elseStmt = r.(string)
-		})
-		if elseStmt != "" {
-			elseStmt = fmt.Sprintf("%s\n", elseStmt)
-		}
-		elseStmt = fmt.Sprintf(
-			` else {
-%s
-%s`,
-			elseStmt,
-			v.withIndent("}"),
-		)
+	return stmt, nil
+}
+
+// blockBody generates the block of a control flow body. A body that is a
+// single statement is emitted as if it were a block.
+func (v *Generator) blockBody(n ast.Node) *javaBlock {
+	block, ok := n.(*ast.Block)
+	if !ok {
+		block = &ast.Block{Statements: []ast.Node{n}}
 	}
-	return fmt.Sprintf(
-		`if (%s) {
-%s%s%s`,
-		cond.(string),
-		ifStmt,
-		v.withIndent("}"),
-		elseStmt,
-	), nil
+	r, err := block.Accept(v)
+	if err != nil {
+		panic(err)
+	}
+	return r.(*javaBlock)
 }
 
 func (v *Generator) VisitMethodDeclaration(n *ast.MethodDeclaration) (interface{}, error) {
-	annotations := make([]string, len(n.Annotations))
-	for i, a := range n.Annotations {
-		r, err := a.Accept(v)
-		if err != nil {
-			return nil, err
-		}
-		annotations[i] = r.(string)
+	annotations, apexModifiers := n.Annotations, n.Modifiers
+	junit := junitAnnotation(n)
+	if junit != "" {
+		annotations, apexModifiers = junitHeader(n)
 	}
-	annotationStr := ""
-	if len(annotations) != 0 {
-		annotationStr = fmt.Sprintf("%s\n", strings.Join(annotations, "\n"))
+	header, err := v.declarationHeader(annotations, apexModifiers)
+	if err != nil {
+		return nil, err
 	}
-	modifiers := make([]string, len(n.Modifiers))
-	for i, m := range n.Modifiers {
-		r, err := m.Accept(v)
-		if err != nil {
-			return nil, err
-		}
-		modifiers[i] = r.(string)
+	if junit != "" {
+		header.Annotations = append(header.Annotations, junit)
 	}
-	returnType := "void"
+	if isFinalMethod(n) {
+		header.Modifiers = append(header.Modifiers, "final")
+	}
+	method := &javaMethod{javaHeader: header, ReturnType: "void", Name: n.Name}
 	if n.ReturnType != nil {
 		r, err := n.ReturnType.Accept(v)
 		if err != nil {
 			return nil, err
 		}
-		returnType = r.(string)
+		method.ReturnType = r.(string)
 	}
-	parameters := make([]string, len(n.Parameters))
-	for i, p := range n.Parameters {
+	v.env = newTypeEnv(v.env)
+	defer func() { v.env = v.env.parent }()
+	for _, p := range n.Parameters {
 		r, err := p.Accept(v)
 		if err != nil {
 			return nil, err
 		}
-		parameters[i] = r.(string)
+		method.Parameters = append(method.Parameters, r.(string))
 	}
-	block := ""
-	v.AddIndent(func() {
-		r, err := n.Statements.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		block = r.(string)
-	})
-	if block != "" {
-		block = fmt.Sprintf("%s\n", block)
+	if n.Statements == nil {
+		// interface and abstract methods have no body
+		return method, nil
 	}
-	return fmt.Sprintf(
-		`%s%s %s %s (%s) {
-%s%s`,
-		annotationStr,
-		v.withIndent(strings.Join(modifiers, " ")),
-		returnType,
-		n.Name,
-		strings.Join(parameters, ", "),
-		block,
-		v.withIndent("}"),
-	), nil
+	r, err := n.Statements.Accept(v)
+	if err != nil {
+		return nil, err
+	}
+	method.Body = r.(*javaBlock)
+	if junit == junitBeforeEach {
+		method.Body.Statements = append([]javaNode{&javaStatement{Code: testContextReset}}, method.Body.Statements...)
+	}
+	if isFutureMethod(n) {
+		return v.futureMethod(n, method), nil
+	}
+	return method, nil
 }
 
 func (v *Generator) VisitMethodInvocation(n *ast.MethodInvocation) (interface{}, error) {
@@ -491,6 +550,34 @@ func (v *Generator) VisitMethodInvocation(n *ast.MethodInvocation) (interface{},
 		}
 		parameters[i] = r.(string)
 	}
+	if method, ok := staticMethod(n); ok {
+		exp = method
+		if method == "Database.query" && len(parameters) == 1 {
+			if binds, ok := v.queryBinds(); ok {
+				parameters = append(parameters, binds)
+			}
+		}
+	}
+	if receiver, method, ok := v.stringMethod(n); ok {
+		if receiver != nil {
+			r, err := receiver.Accept(v)
+			if err != nil {
+				return nil, err
+			}
+			parameters = append([]string{r.(string)}, parameters...)
+		}
+		v.importClass("apexstring")
+		exp = "ApexString." + method.Name
+	}
+	if enumType, method, ok := v.enumMethod(n); ok {
+		v.importClass("enums")
+		name := "values"
+		if method == "valueof" {
+			name = "valueOf"
+		}
+		parameters = append([]string{strings.Join(enumType, ".") + ".class"}, parameters...)
+		return fmt.Sprintf("Enums.%s(%s)", name, strings.Join(parameters, ", ")), nil
+	}
 	return fmt.Sprintf(
 		"%s(%s)",
 		exp.(string),
@@ -527,13 +614,21 @@ func (v *Generator) VisitUnaryOperator(n *ast.UnaryOperator) (interface{}, error
 	if err != nil {
 		return nil, err
 	}
-	if n.IsPrefix {
-		return fmt.Sprintf("%s%s", n.Op, val.(string)), nil
+	if v.NullSafeArithmetic || typeName(v.typeOf(n.Expression)) == "decimal" {
+		if r, ok := v.nullSafeUnaryOperator(n, val.(string)); ok {
+			return r, nil
+		}
+	}
+	if isPrefix(n) {
+		return fmt.Sprintf("%s%s", n.Op, operand(n.Expression, val.(string), unaryPrecedence)), nil
 	}
-	return fmt.Sprintf("%s%s", val.(string), n.Op), nil
+	return fmt.Sprintf("%s%s", operand(n.Expression, val.(string), postfixPrecedence), n.Op), nil
 }
 
 func (v *Generator) VisitBinaryOperator(n *ast.BinaryOperator) (interface{}, error) {
+	if n.Op == "=" {
+		v.setTarget(n.Right, v.typeOf(n.Left))
+	}
 	l, err := n.Left.Accept(v)
 	if err != nil {
 		return nil, err
@@ -542,7 +637,45 @@ func (v *Generator) VisitBinaryOperator(n *ast.BinaryOperator) (interface{}, err
 	if err != nil {
 		return nil, err
 	}
-	return fmt.Sprintf("%s %s %s", l.(string), n.Op, r.(string)), nil
+	if n.Op == "=" {
+		if literal, ok := decimalLiteral(v.typeOf(n.Left), n.Right); ok {
+			r = literal
+		}
+	}
+	if v.NullSafeArithmetic || v.isDecimalArithmetic(n) {
+		if r, ok := v.nullSafeBinaryOperator(n, l.(string), r.(string)); ok {
+			v.importClass("apexoperator")
+			return r, nil
+		}
+	}
+	switch n.Op {
+	case "===", "!==":
+		// Apex exact equality compares references, as Java == and != do.
+		l, r := binaryOperands(n, l.(string), r.(string))
+		return fmt.Sprintf("%s %s %s", l, strings.TrimSuffix(n.Op, "="), r), nil
+	case "==", "!=":
+		if isNullLiteral(n.Left) || isNullLiteral(n.Right) {
+			// comparisons against null keep their meaning
+			break
+		}
+		method := "equals"
+		if isIdType(v.typeOf(n.Left)) || isIdType(v.typeOf(n.Right)) {
+			method = "equalsId"
+		}
+		equals := fmt.Sprintf("ApexOperator.%s(%s, %s)", method, l.(string), r.(string))
+		if n.Op == "!=" {
+			return "!" + equals, nil
+		}
+		return equals, nil
+	case "<", ">", "<=", ">=":
+		lt, rt := v.typeOf(n.Left), v.typeOf(n.Right)
+		if isStringType(lt) && isStringType(rt) || isTemporalType(lt) || isTemporalType(rt) {
+			v.importClass("apexoperator")
+			return fmt.Sprintf("ApexOperator.compare(%s, %s) %s 0", l.(string), r.(string), n.Op), nil
+		}
+	}
+	ls, rs := binaryOperands(n, l.(string), r.(string))
+	return fmt.Sprintf("%s %s %s", ls, n.Op, rs), nil
 }
 
 func (v *Generator) VisitReturn(n *ast.Return) (interface{}, error) {
@@ -568,83 +701,37 @@ func (v *Generator) VisitThrow(n *ast.Throw) (interface{}, error) {
 }
 
 func (v *Generator) VisitSoql(n *ast.Soql) (interface{}, error) {
-	where := ""
-	fields := make([]string, len(n.SelectFields))
-	from := ""
-	for i, f := range n.SelectFields {
-		switch val := f.(type) {
-		case *ast.SelectField:
-			fields[i] = strings.Join(val.Value, ".")
-		case *ast.SoqlFunction:
-			fields[i] = val.Name + "()"
-		}
-	}
-
-	from = n.FromObject
-
-	if n.Where != nil {
-		where = v.createWhere(n.Where)
-	}
-
-	if where != "" {
-		where = " WHERE " + where
-	}
-	orderBy := ""
-	groupBy := ""
-	limit := ""
-	if n.Limit != nil {
-		i, err := n.Limit.Accept(v)
-		if err != nil {
-			return nil, err
-		}
-		limit = " LIMIT " + i.(string)
-	}
-
-	return fmt.Sprintf(`(List<%s>)Database.execute("SELECT %s FROM %s%s%s%s%s")`,
-		from,
-		strings.Join(fields, ","),
-		from,
-		where,
-		orderBy,
-		groupBy,
-		limit,
-	), nil
-}
-
-func (v *Generator) createWhere(n ast.Node) string {
-	switch val := n.(type) {
-	case *ast.WhereCondition:
-		var field string
-		switch f := val.Field.(type) {
-		case *ast.SelectField:
-			field = strings.Join(f.Value, ".")
-		case *ast.SoqlFunction:
-			field = f.Name + "()"
-		}
-		value, err := val.Expression.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		return fmt.Sprintf("%s %s %s", field, val.Op, value.(string))
-	case *ast.WhereBinaryOperator:
-		where := ""
-		if val.Left != nil {
-			where += v.createWhere(val.Left)
-		}
-		if val.Right != nil {
-			where += fmt.Sprintf("\n%s %s", v.withIndent(val.Op), v.createWhere(val.Right))
-		}
-		return where
-	}
-	return ""
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitSosl(n *ast.Sosl) (interface{}, error) {
-	return ast.VisitSosl(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitStringLiteral(n *ast.StringLiteral) (interface{}, error) {
-	return "\"" + n.Value + "\"", nil
+	return "\"" + escapeQuotes(n.Value) + "\"", nil
+}
+
+// escapeQuotes escapes the double quotes of the source of an Apex string
+// literal, which are plain characters in Apex. The Apex escape sequences
+// are kept, since Java has the same ones.
+func escapeQuotes(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			b.WriteByte(s[i])
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
 }
 
 func (v *Generator) VisitSwitch(n *ast.Switch) (interface{}, error) {
@@ -652,43 +739,24 @@ func (v *Generator) VisitSwitch(n *ast.Switch) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	whenStmts := make([]string, len(n.WhenStatements))
-	v.AddIndent(func() {
-		for i, stmt := range n.WhenStatements {
-			r, err := stmt.Accept(v)
-			if err != nil {
-				panic(err)
-			}
-			whenStmts[i] = r.(string)
+	body := &javaBlock{}
+	for _, stmt := range n.WhenStatements {
+		r, err := stmt.Accept(v)
+		if err != nil {
+			return nil, err
 		}
-	})
-	elseStmt := ""
-	v.AddIndent(func() {
+		body.Statements = append(body.Statements, r.(javaNode))
+	}
+	if n.ElseStatement != nil {
 		r, err := n.ElseStatement.Accept(v)
 		if err != nil {
-			panic(err)
+			return nil, err
+		}
+		if e, ok := r.(*javaBlock); ok && len(e.Statements) != 0 {
+			body.Statements = append(body.Statements, newCompound("when else", e))
 		}
-		elseStmt = r.(string)
-	})
-	if elseStmt != "" {
-		elseStmt = fmt.Sprintf(
-			` else {
-%s
-%s`,
-			elseStmt,
-			v.withIndent("}"),
-		)
 	}
-	return fmt.Sprintf(
-		`switch on %s {
-%s
-%s
-%s`,
-		exp.(string),
-		strings.Join(whenStmts, "\n"),
-		elseStmt,
-		v.withIndent("}"),
-	), nil
+	return newCompound("switch on "+exp.(string), body), nil
 }
 
 func (v *Generator) VisitTrigger(n *ast.Trigger) (interface{}, error) {
@@ -704,15 +772,9 @@ func (v *Generator) VisitTrigger(n *ast.Trigger) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return fmt.Sprintf(
-		`trigger %s on %s (%s) {
-%s
-%s`,
-		n.Name,
-		n.Object,
-		strings.Join(timings, ", "),
-		stmt.(string),
-		v.withIndent("}"),
+	return newCompound(
+		fmt.Sprintf("trigger %s on %s (%s)", n.Name, n.Object, strings.Join(timings, ", ")),
+		stmt.(*javaBlock),
 	), nil
 }
 
@@ -727,11 +789,17 @@ func (v *Generator) VisitVariableDeclaration(n *ast.VariableDeclaration) (interf
 	}
 	declarators := make([]string, len(n.Declarators))
 	for i, decl := range n.Declarators {
-		r, err := decl.Accept(v)
+		r, err := v.declarator(decl, n.TypeRef)
 		if err != nil {
 			return nil, err
 		}
-		declarators[i] = r.(string)
+		declarators[i] = r
+		if decl.Expression == nil {
+			// Apex variables start out null, and Java variables must be
+			// assigned before use, such as passing them to Database.binds.
+			declarators[i] += " = null"
+		}
+		v.declare(decl.Name, n.TypeRef)
 	}
 	return fmt.Sprintf(
 		"%s %s",
@@ -740,6 +808,19 @@ func (v *Generator) VisitVariableDeclaration(n *ast.VariableDeclaration) (interf
 	), nil
 }
 
+// declarator emits a declarator of a variable or field of type t.
+func (v *Generator) declarator(n *ast.VariableDeclarator, t *ast.TypeRef) (string, error) {
+	if literal, ok := decimalLiteral(t, n.Expression); ok {
+		return fmt.Sprintf("%s = %s", n.Name, literal), nil
+	}
+	v.setTarget(n.Expression, t)
+	r, err := n.Accept(v)
+	if err != nil {
+		return "", err
+	}
+	return r.(string), nil
+}
+
 func (v *Generator) VisitVariableDeclarator(n *ast.VariableDeclarator) (interface{}, error) {
 	if n.Expression == nil {
 		return fmt.Sprintf("%s", n.Name), nil
@@ -760,24 +841,11 @@ func (v *Generator) VisitWhen(n *ast.When) (interface{}, error) {
 		}
 		conditions[i] = r.(string)
 	}
-	stmt := ""
-	v.AddIndent(func() {
-		r, err := n.Statements.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		stmt = r.(string)
-	})
-	return fmt.Sprintf(
-		`when %s {
-%s
-%s`,
-		strings.Join(conditions, ", "),
-		stmt,
-		v.withIndent("}"),
-	), nil
-
-	return ast.VisitWhen(v, n)
+	stmt, err := n.Statements.Accept(v)
+	if err != nil {
+		return nil, err
+	}
+	return newCompound("when "+strings.Join(conditions, ", "), stmt.(*javaBlock)), nil
 }
 
 func (v *Generator) VisitWhenType(n *ast.WhenType) (interface{}, error) {
@@ -797,22 +865,12 @@ func (v *Generator) VisitWhile(n *ast.While) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	statements := ""
-	v.AddIndent(func() {
-		r, err := n.Statements.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		statements = r.(string)
-	})
-	return fmt.Sprintf(
-		`while (%s) {
-%s
-%s`,
-		cond.(string),
-		statements,
-		v.withIndent("}"),
-	), nil
+	if n.IsDo {
+		stmt := newCompound("do", v.blockBody(n.Statements))
+		stmt.Trailer = fmt.Sprintf("while (%s)", cond.(string))
+		return stmt, nil
+	}
+	return newCompound(fmt.Sprintf("while (%s)", cond.(string)), v.blockBody(n.Statements)), nil
 }
 
 func (v *Generator) VisitNothingStatement(n *ast.NothingStatement) (interface{}, error) {
@@ -828,7 +886,7 @@ func (v *Generator) VisitCastExpression(n *ast.CastExpression) (interface{}, err
 	if err != nil {
 		return nil, err
 	}
-	return fmt.Sprintf("(%s)%s", t.(string), exp.(string)), nil
+	return fmt.Sprintf("(%s)%s", t.(string), operand(n.Expression, exp.(string), unaryPrecedence)), nil
 }
 
 func (v *Generator) VisitFieldAccess(n *ast.FieldAccess) (interface{}, error) {
@@ -836,7 +894,7 @@ func (v *Generator) VisitFieldAccess(n *ast.FieldAccess) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return fmt.Sprintf("%s.%s", exp.(string), n.FieldName), nil
+	return fmt.Sprintf("%s.%s", operand(n.Expression, exp.(string), primaryPrecedence), n.FieldName), nil
 }
 
 func (v *Generator) VisitType(n *ast.TypeRef) (interface{}, error) {
@@ -856,130 +914,159 @@ func (v *Generator) VisitType(n *ast.TypeRef) (interface{}, error) {
 	for i := 0; i < n.Dimmension; i++ {
 		appendString += "[]"
 	}
+	name := strings.Join(apexTypeName(n.Name), ".")
+	if javaName, ok := JavaTypeNames[strings.ToLower(name)]; ok {
+		name = javaName
+	}
 	return fmt.Sprintf(
 		"%s%s%s",
-		strings.Join(n.Name, "."),
+		name,
 		paramString,
 		appendString,
 	), nil
 }
 
+// apexTypeName drops the implicit System namespace from a qualified type
+// name, since System types are emitted as top level runtime classes.
+func apexTypeName(name []string) []string {
+	if len(name) > 1 && strings.ToLower(name[0]) == "system" {
+		return name[1:]
+	}
+	return name
+}
+
+func javaTypeName(t *ast.TypeRef) string {
+	r, err := (&Generator{}).VisitType(t)
+	if err != nil {
+		panic(err)
+	}
+	return r.(string)
+}
+
 func (v *Generator) VisitBlock(n *ast.Block) (interface{}, error) {
-	statements := make([]string, len(n.Statements))
-	for i, s := range n.Statements {
+	v.env = newTypeEnv(v.env)
+	defer func() { v.env = v.env.parent }()
+	block := &javaBlock{}
+	for _, s := range n.Statements {
+		if u, ok := s.(*ast.UnaryOperator); ok {
+			if r, ok := v.incrementStatement(u); ok {
+				block.Statements = append(block.Statements, v.sourced(s, &javaStatement{Code: r}, len(v.todos)))
+				continue
+			}
+		}
+		if _, ok := s.(*ast.NothingStatement); ok {
+			continue
+		}
+		start := len(v.todos)
 		r, err := s.Accept(v)
 		if err != nil {
 			return nil, err
 		}
-		statements[i] = v.withIndent(r.(string))
-		switch s.(type) {
-		case *ast.For, *ast.If, *ast.Switch:
-		default:
-			statements[i] += ";"
+		var stmt javaNode
+		switch r := r.(type) {
+		case javaNode:
+			stmt = r
+		case string:
+			// expressions and simple statements are emitted as text
+			stmt = &javaStatement{Code: r}
 		}
+		block.Statements = append(block.Statements, v.sourced(s, stmt, start))
 	}
-	return strings.Join(statements, "\n"), nil
+	if lines := v.closingLines(n); len(lines) != 0 {
+		block.Statements = append(block.Statements, lines)
+	}
+	return block, nil
 }
 
 func (v *Generator) VisitGetterSetter(n *ast.GetterSetter) (interface{}, error) {
-	return ast.VisitGetterSetter(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitPropertyDeclaration(n *ast.PropertyDeclaration) (interface{}, error) {
-	return ast.VisitPropertyDeclaration(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitArrayInitializer(n *ast.ArrayInitializer) (interface{}, error) {
-	return ast.VisitArrayInitializer(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitArrayCreator(n *ast.ArrayCreator) (interface{}, error) {
-	return ast.VisitArrayCreator(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitSoqlBindVariable(n *ast.SoqlBindVariable) (interface{}, error) {
-	return ast.VisitSoqlBindVariable(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitTernalyExpression(n *ast.TernalyExpression) (interface{}, error) {
-	return ast.VisitTernalyExpression(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitMapCreator(n *ast.MapCreator) (interface{}, error) {
-	return ast.VisitMapCreator(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitSetCreator(n *ast.SetCreator) (interface{}, error) {
-	return ast.VisitSetCreator(v, n)
+	return v.unsupported(n)
 }
 
 func (v *Generator) VisitName(n *ast.Name) (interface{}, error) {
+	if level, ok := loggingLevel(n); ok {
+		return level, nil
+	}
+	if token, ok := v.schemaToken(n.Value); ok {
+		return token, nil
+	}
 	return strings.Join(n.Value, "."), nil
 }
 
 func (v *Generator) VisitInstanceofOperator(n *ast.InstanceofOperator) (interface{}, error) {
 	exp, _ := n.Expression.Accept(v)
 	typeRef, _ := n.TypeRef.Accept(v)
-	return fmt.Sprintf("%s instanceof %s", exp, typeRef), nil
+	return fmt.Sprintf("%s instanceof %s", operand(n.Expression, exp.(string), instanceofPrecedence), typeRef), nil
 }
 
 func (v *Generator) VisitConstructorDeclaration(n *ast.ConstructorDeclaration) (interface{}, error) {
-	annotations := make([]string, len(n.Annotations))
-	for i, a := range n.Annotations {
-		r, err := a.Accept(v)
-		if err != nil {
-			return nil, err
-		}
-		annotations[i] = r.(string)
-	}
-	annotationStr := ""
-	if len(annotations) != 0 {
-		annotationStr = fmt.Sprintf("%s\n", strings.Join(annotations, "\n"))
-	}
-	modifiers := make([]string, len(n.Modifiers))
-	for i, m := range n.Modifiers {
-		r, err := m.Accept(v)
-		if err != nil {
-			return nil, err
-		}
-		modifiers[i] = r.(string)
+	header, err := v.declarationHeader(n.Annotations, n.Modifiers)
+	if err != nil {
+		return nil, err
 	}
-	parameters := make([]string, len(n.Parameters))
-	for i, p := range n.Parameters {
+	v.env = newTypeEnv(v.env)
+	defer func() { v.env = v.env.parent }()
+	constructor := &javaMethod{javaHeader: header, Name: n.Name}
+	for _, p := range n.Parameters {
 		r, err := p.Accept(v)
 		if err != nil {
 			return nil, err
 		}
-		parameters[i] = r.(string)
+		constructor.Parameters = append(constructor.Parameters, r.(string))
 	}
-	block := ""
-	v.AddIndent(func() {
-		r, err := n.Statements.Accept(v)
-		if err != nil {
-			panic(err)
-		}
-		block = r.(string)
-	})
-	if block != "" {
-		block = fmt.Sprintf("%s\n", block)
+	r, err := n.Statements.Accept(v)
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Sprintf(
-		`%s%s %s (%s) {
-%s%s`,
-		annotationStr,
-		v.withIndent(strings.Join(modifiers, " ")),
-		n.Name,
-		strings.Join(parameters, ", "),
-		block,
-		v.withIndent("}"),
-	), nil
+	constructor.Body = r.(*javaBlock)
+	return constructor, nil
 }
 
 func Generate(n ast.Node) string {
-	visitor := &Generator{}
-	r, err := n.Accept(visitor)
+	return (&Generator{}).Generate(n)
+}
+
+func (v *Generator) Generate(n ast.Node) string {
+	v.enums = enumNames(n)
+	for name := range v.Enums {
+		v.enums[name] = struct{}{}
+	}
+	v.root = n
+	if v.Source != "" {
+		v.attachComments(n)
+	}
+	r, err := n.Accept(v)
 	if err != nil {
 		panic(err)
 	}
-	return r.(string)
+	src, lines := printJava(javaNodes{v.sourced(n, r.(javaNode), len(v.todos)), javaLines(v.rootComments())})
+	v.lines = lines
+	return src
 }
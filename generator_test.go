@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// goldenOptions configures the generator for test cases that exercise a
+// non-default mode, keyed by the base name of the Apex file.
+var goldenOptions = map[string]func(*Generator){
+	"null_safe_arithmetic": func(g *Generator) { g.NullSafeArithmetic = true },
+	"runtime_version":      func(g *Generator) { g.RuntimeVersion = runtimeVersion() },
+	"lenient":              func(g *Generator) { g.Lenient = true },
+	// Priority is declared in enum.cls
+	"enum_other_file": func(g *Generator) { g.Enums = map[string]struct{}{"priority": {}} },
+}
+
+type goldenCase struct {
+	name   string
+	node   ast.Node
+	actual string
+}
+
+func convertGoldenCases(t *testing.T) []*goldenCase {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.cls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := make([]*goldenCase, len(files))
+	for i, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".cls")
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		node := parse(string(src), filepath.Base(file))
+		generator := &Generator{Source: string(src)}
+		if option, ok := goldenOptions[name]; ok {
+			option(generator)
+		}
+		actual, err := Convert(node, generator)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		cases[i] = &goldenCase{name: name, node: node, actual: actual}
+	}
+	return cases
+}
+
+func TestGolden(t *testing.T) {
+	for _, c := range convertGoldenCases(t) {
+		t.Run(c.name, func(t *testing.T) {
+			golden := filepath.Join("testdata", c.name+".java")
+			if *update {
+				if err := ioutil.WriteFile(golden, []byte(c.actual), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.actual != string(expected) {
+				t.Errorf("%s differs from golden (run go test -update)\n--- expected\n%s\n--- actual\n%s", golden, expected, c.actual)
+			}
+		})
+	}
+}
+
+// TestGoldenJavac compiles every golden file against the runtime, and needs
+// a JDK, see lookJDK. Converted test classes are compiled only if
+// JUNIT_CLASSPATH points to the JUnit platform, which CI requires too.
+func TestGoldenJavac(t *testing.T) {
+	javac := lookJDK(t, "javac")
+	dir, err := ioutil.TempDir("", "apex2java")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sources, err := filepath.Glob(filepath.Join("com", "freedom_man", "system", "*.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	junit := os.Getenv("JUNIT_CLASSPATH")
+	if junit == "" && os.Getenv("CI") != "" {
+		t.Fatal("JUNIT_CLASSPATH is not set: CI requires the JUnit platform")
+	}
+	for _, c := range convertGoldenCases(t) {
+		if junit == "" && strings.Contains(c.actual, "org.junit") {
+			t.Logf("%s: skipped, JUNIT_CLASSPATH is not set", c.name)
+			continue
+		}
+		file := filepath.Join(dir, className(c.node)+".java")
+		if err := ioutil.WriteFile(file, []byte(c.actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, file)
+	}
+	args := []string{"-d", filepath.Join(dir, "classes")}
+	if junit != "" {
+		args = append(args, "-cp", junit)
+	}
+	args = append(args, sources...)
+	out, err := exec.Command(javac, args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("javac failed: %s\n%s", err, out)
+	}
+}
+
+func TestUnknownAnnotationParameter(t *testing.T) {
+	src := "public class Annotated {\n    @Future(delay=5)\n    public static void sync() {}\n}\n"
+	defer func() {
+		err, _ := recover().(error)
+		if err == nil || !strings.Contains(err.Error(), "parameter delay of @Future at Annotated.cls:2 is not supported") {
+			t.Fatalf("want the unknown parameter rejected, got %v", err)
+		}
+	}()
+	Convert(parse(src, "Annotated.cls"), &Generator{Source: src})
+}
@@ -6,14 +6,102 @@ import (
 )
 
 var ImportClasses = map[string]string{
-	"system":   "com.freedom_man.system.System",
-	"database": "com.freedom_man.system.Database",
-	"list":     "com.freedom_man.system.List",
-	"account":  "com.freedom_man.system.Account",
-	"sobject":  "com.freedom_man.system.SObject",
+	"system":             "com.freedom_man.system.System",
+	"database":           "com.freedom_man.system.Database",
+	"list":               "com.freedom_man.system.List",
+	"map":                "com.freedom_man.system.Map",
+	"account":            "com.freedom_man.system.Account",
+	"sobject":            "com.freedom_man.system.SObject",
+	"user":               "com.freedom_man.system.User",
+	"decimal":            "java.math.BigDecimal",
+	"date":               "com.freedom_man.system.Date",
+	"datetime":           "com.freedom_man.system.Datetime",
+	"time":               "com.freedom_man.system.Time",
+	"type":               "com.freedom_man.system.Type",
+	"http":               "com.freedom_man.system.Http",
+	"httpcalloutmock":    "com.freedom_man.system.HttpCalloutMock",
+	"httprequest":        "com.freedom_man.system.HttpRequest",
+	"httpresponse":       "com.freedom_man.system.HttpResponse",
+	"json":               "com.freedom_man.system.JSON",
+	"schema":             "com.freedom_man.system.Schema",
+	"jsongenerator":      "com.freedom_man.system.JSONGenerator",
+	"jsonparser":         "com.freedom_man.system.JSONParser",
+	"jsontoken":          "com.freedom_man.system.JSONToken",
+	"logginglevel":       "com.freedom_man.system.LoggingLevel",
+	"asyncapexjob":       "com.freedom_man.system.AsyncApexJob",
+	"crontrigger":        "com.freedom_man.system.CronTrigger",
+	"queueable":          "com.freedom_man.system.Queueable",
+	"queueablecontext":   "com.freedom_man.system.QueueableContext",
+	"schedulable":        "com.freedom_man.system.Schedulable",
+	"schedulablecontext": "com.freedom_man.system.SchedulableContext",
+
+	// Schema types Apex code may name without the namespace
+	"describefieldresult":   "com.freedom_man.system.Schema",
+	"describesobjectresult": "com.freedom_man.system.Schema",
+	"displaytype":           "com.freedom_man.system.Schema",
+	"picklistentry":         "com.freedom_man.system.Schema",
+	"recordtypeinfo":        "com.freedom_man.system.Schema",
+	"sobjectfield":          "com.freedom_man.system.Schema",
+	"sobjecttype":           "com.freedom_man.system.Schema",
+
+	"accesslevel":    "com.freedom_man.system.AccessLevel",
+	"apexoperator":   "com.freedom_man.system.ApexOperator",
+	"asyncapex":      "com.freedom_man.system.AsyncApex",
+	"apexstring":     "com.freedom_man.system.ApexString",
+	"enums":          "com.freedom_man.system.Enums",
+	"limits":         "com.freedom_man.system.Limits",
+	"runtimeversion": "com.freedom_man.system.RuntimeVersion",
+	"sharing":        "com.freedom_man.system.Sharing",
+	"test":           "com.freedom_man.system.Test",
+	"unsupported":    "com.freedom_man.system.Unsupported",
+
+	"auraenabled":       "com.freedom_man.system.AuraEnabled",
+	"future":            "com.freedom_man.system.Future",
+	"invocablemethod":   "com.freedom_man.system.InvocableMethod",
+	"invocablevariable": "com.freedom_man.system.InvocableVariable",
+	"istest":            "com.freedom_man.system.IsTest",
+	"readonly":          "com.freedom_man.system.ReadOnly",
+	"remoteaction":      "com.freedom_man.system.RemoteAction",
+	"testsetup":         "com.freedom_man.system.TestSetup",
+	"testvisible":       "com.freedom_man.system.TestVisible",
+
+	"exception":                       "com.freedom_man.system.Exception",
+	"assertexception":                 "com.freedom_man.system.AssertException",
+	"asyncexception":                  "com.freedom_man.system.AsyncException",
+	"calloutexception":                "com.freedom_man.system.CalloutException",
+	"dmlexception":                    "com.freedom_man.system.DmlException",
+	"emailexception":                  "com.freedom_man.system.EmailException",
+	"finalexception":                  "com.freedom_man.system.FinalException",
+	"handledexception":                "com.freedom_man.system.HandledException",
+	"illegalargumentexception":        "com.freedom_man.system.IllegalArgumentException",
+	"invalidparametervalueexception":  "com.freedom_man.system.InvalidParameterValueException",
+	"jsonexception":                   "com.freedom_man.system.JSONException",
+	"limitexception":                  "com.freedom_man.system.LimitException",
+	"listexception":                   "com.freedom_man.system.ListException",
+	"mathexception":                   "com.freedom_man.system.MathException",
+	"noaccessexception":               "com.freedom_man.system.NoAccessException",
+	"nodatafoundexception":            "com.freedom_man.system.NoDataFoundException",
+	"nosuchelementexception":          "com.freedom_man.system.NoSuchElementException",
+	"nullpointerexception":            "com.freedom_man.system.NullPointerException",
+	"queryexception":                  "com.freedom_man.system.QueryException",
+	"requiredfeaturemissingexception": "com.freedom_man.system.RequiredFeatureMissingException",
+	"searchexception":                 "com.freedom_man.system.SearchException",
+	"securityexception":               "com.freedom_man.system.SecurityException",
+	"serializationexception":          "com.freedom_man.system.SerializationException",
+	"sobjectexception":                "com.freedom_man.system.SObjectException",
+	"stringexception":                 "com.freedom_man.system.StringException",
+	"typeexception":                   "com.freedom_man.system.TypeException",
+	"unexpectedexception":             "com.freedom_man.system.UnexpectedException",
+	"visualforceexception":            "com.freedom_man.system.VisualforceException",
+	"xmlexception":                    "com.freedom_man.system.XmlException",
 }
 
 type ImportTypeResolver struct {
+	// NullSafeArithmetic mirrors Generator.NullSafeArithmetic.
+	NullSafeArithmetic bool
+	// RuntimeVersion mirrors Generator.RuntimeVersion.
+	RuntimeVersion string
+
 	importClasses map[string]struct{}
 }
 
@@ -24,29 +112,64 @@ func NewImportTypeResolver() *ImportTypeResolver {
 }
 
 func (v *ImportTypeResolver) Resolve(n ast.Node) (interface{}, error) {
+	if v.RuntimeVersion != "" {
+		switch n.(type) {
+		case *ast.ClassDeclaration, *EnumDeclaration:
+			v.importClasses[ImportClasses["runtimeversion"]] = struct{}{}
+		}
+	}
 	return n.Accept(v)
 }
 
 func (v *ImportTypeResolver) VisitClassDeclaration(n *ast.ClassDeclaration) (interface{}, error) {
+	v.visitHeader(n.Annotations, n.Modifiers)
+	if isTestClass(n) {
+		// the generated test setup resets the runtime's test context
+		v.importClasses[ImportClasses["test"]] = struct{}{}
+	}
+	if n.SuperClassRef != nil {
+		n.SuperClassRef.Accept(v)
+	}
+	for _, impl := range n.ImplementClassRefs {
+		impl.Accept(v)
+	}
 	for _, d := range n.Declarations {
 		d.Accept(v)
 	}
-	for _, c := range n.InnerClasses {
-		c.Accept(v)
-	}
 	return nil, nil
 }
 
 func (v *ImportTypeResolver) VisitModifier(n *ast.Modifier) (interface{}, error) {
-	return ast.VisitModifier(v, n)
+	if a, ok := ModifierAnnotations[modifierName(n)]; ok && a.Import != "" {
+		v.importClasses[ImportClasses[a.Import]] = struct{}{}
+	}
+	return nil, nil
 }
 
 func (v *ImportTypeResolver) VisitAnnotation(n *ast.Annotation) (interface{}, error) {
-	return ast.VisitAnnotation(v, n)
+	if a, ok := JavaAnnotations[strings.ToLower(n.Name)]; ok && a.Import != "" {
+		v.importClasses[ImportClasses[a.Import]] = struct{}{}
+	}
+	return nil, nil
+}
+
+// visitHeader visits the annotations and modifiers of a declaration, which
+// may be emitted as runtime annotations.
+func (v *ImportTypeResolver) visitHeader(as []*ast.Annotation, ms []*ast.Modifier) {
+	for _, a := range as {
+		a.Accept(v)
+	}
+	for _, m := range ms {
+		m.Accept(v)
+	}
 }
 
 func (v *ImportTypeResolver) VisitInterfaceDeclaration(n *ast.InterfaceDeclaration) (interface{}, error) {
-	return ast.VisitInterfaceDeclaration(v, n)
+	v.visitHeader(n.Annotations, n.Modifiers)
+	for _, m := range n.Methods {
+		m.Accept(v)
+	}
+	return nil, nil
 }
 
 func (v *ImportTypeResolver) VisitIntegerLiteral(n *ast.IntegerLiteral) (interface{}, error) {
@@ -74,19 +197,35 @@ func (v *ImportTypeResolver) VisitContinue(n *ast.Continue) (interface{}, error)
 }
 
 func (v *ImportTypeResolver) VisitDml(n *ast.Dml) (interface{}, error) {
-	return ast.VisitDml(v, n)
+	v.importClasses[ImportClasses["database"]] = struct{}{}
+	return n.Expression.Accept(v)
 }
 
 func (v *ImportTypeResolver) VisitDoubleLiteral(n *ast.DoubleLiteral) (interface{}, error) {
+	if v.NullSafeArithmetic {
+		v.importClasses[ImportClasses["decimal"]] = struct{}{}
+	}
 	return ast.VisitDoubleLiteral(v, n)
 }
 
 func (v *ImportTypeResolver) VisitFieldDeclaration(n *ast.FieldDeclaration) (interface{}, error) {
+	v.visitHeader(n.Annotations, n.Modifiers)
 	return n.TypeRef.Accept(v)
 }
 
 func (v *ImportTypeResolver) VisitTry(n *ast.Try) (interface{}, error) {
-	return ast.VisitTry(v, n)
+	if len(n.CatchClause) != 0 {
+		// for Exception.translate, see Generator.translateJVMExceptions
+		v.importClasses[ImportClasses["exception"]] = struct{}{}
+	}
+	n.Block.Accept(v)
+	for _, c := range n.CatchClause {
+		c.Accept(v)
+	}
+	if n.FinallyBlock != nil {
+		n.FinallyBlock.Accept(v)
+	}
+	return nil, nil
 }
 
 func (v *ImportTypeResolver) VisitCatch(n *ast.Catch) (interface{}, error) {
@@ -114,6 +253,23 @@ func (v *ImportTypeResolver) VisitIf(n *ast.If) (interface{}, error) {
 }
 
 func (v *ImportTypeResolver) VisitMethodDeclaration(n *ast.MethodDeclaration) (interface{}, error) {
+	if junitAnnotation(n) != "" {
+		v.visitHeader(junitHeader(n))
+	} else {
+		v.visitHeader(n.Annotations, n.Modifiers)
+	}
+	if n.ReturnType != nil {
+		n.ReturnType.Accept(v)
+	}
+	for _, p := range n.Parameters {
+		p.TypeRef.Accept(v)
+	}
+	if n.Statements == nil {
+		return nil, nil
+	}
+	if isFutureMethod(n) {
+		v.importClasses[ImportClasses["asyncapex"]] = struct{}{}
+	}
 	return n.Statements.Accept(v)
 }
 
@@ -126,7 +282,11 @@ func (v *ImportTypeResolver) VisitMethodInvocation(n *ast.MethodInvocation) (int
 }
 
 func (v *ImportTypeResolver) VisitNew(n *ast.New) (interface{}, error) {
-	return ast.VisitNew(v, n)
+	n.TypeRef.Accept(v)
+	for _, p := range n.Parameters {
+		p.Accept(v)
+	}
+	return nil, nil
 }
 
 func (v *ImportTypeResolver) VisitNullLiteral(n *ast.NullLiteral) (interface{}, error) {
@@ -134,10 +294,22 @@ func (v *ImportTypeResolver) VisitNullLiteral(n *ast.NullLiteral) (interface{},
 }
 
 func (v *ImportTypeResolver) VisitUnaryOperator(n *ast.UnaryOperator) (interface{}, error) {
-	return ast.VisitUnaryOperator(v, n)
+	return n.Expression.Accept(v)
 }
 
 func (v *ImportTypeResolver) VisitBinaryOperator(n *ast.BinaryOperator) (interface{}, error) {
+	switch n.Op {
+	case "==", "!=":
+		if !isNullLiteral(n.Left) && !isNullLiteral(n.Right) {
+			v.importClasses[ImportClasses["apexoperator"]] = struct{}{}
+		}
+	default:
+		_, arithmetic := arithmeticOperators[n.Op]
+		_, compound := compoundAssignmentOperators[n.Op]
+		if v.NullSafeArithmetic && (arithmetic || compound) {
+			v.importClasses[ImportClasses["apexoperator"]] = struct{}{}
+		}
+	}
 	if _, err := n.Left.Accept(v); err != nil {
 		return nil, err
 	}
@@ -160,11 +332,7 @@ func (v *ImportTypeResolver) VisitThrow(n *ast.Throw) (interface{}, error) {
 }
 
 func (v *ImportTypeResolver) VisitSoql(n *ast.Soql) (interface{}, error) {
-	if packageName, ok := ImportClasses[strings.ToLower(n.FromObject)]; ok {
-		v.importClasses[packageName] = struct{}{}
-	}
-	v.importClasses["database"] = struct{}{}
-	return nil, nil
+	return ast.VisitSoql(v, n)
 }
 
 func (v *ImportTypeResolver) VisitSosl(n *ast.Sosl) (interface{}, error) {
@@ -196,6 +364,9 @@ func (v *ImportTypeResolver) VisitVariableDeclaration(n *ast.VariableDeclaration
 }
 
 func (v *ImportTypeResolver) VisitVariableDeclarator(n *ast.VariableDeclarator) (interface{}, error) {
+	if n.Expression == nil {
+		return nil, nil
+	}
 	return n.Expression.Accept(v)
 }
 
@@ -225,7 +396,7 @@ func (v *ImportTypeResolver) VisitFieldAccess(n *ast.FieldAccess) (interface{},
 
 func (v *ImportTypeResolver) VisitType(n *ast.TypeRef) (interface{}, error) {
 	// TODO: impl
-	if packageName, ok := ImportClasses[strings.ToLower(n.Name[0])]; ok {
+	if packageName, ok := ImportClasses[strings.ToLower(apexTypeName(n.Name)[0])]; ok {
 		v.importClasses[packageName] = struct{}{}
 	}
 	for _, p := range n.Parameters {
@@ -274,6 +445,10 @@ func (v *ImportTypeResolver) VisitSetCreator(n *ast.SetCreator) (interface{}, er
 }
 
 func (v *ImportTypeResolver) VisitName(n *ast.Name) (interface{}, error) {
+	if _, ok := loggingLevel(n); ok {
+		v.importClasses[ImportClasses["logginglevel"]] = struct{}{}
+		return nil, nil
+	}
 	if packageName, ok := ImportClasses[strings.ToLower(n.Value[0])]; ok {
 		v.importClasses[packageName] = struct{}{}
 	}
@@ -281,5 +456,9 @@ func (v *ImportTypeResolver) VisitName(n *ast.Name) (interface{}, error) {
 }
 
 func (v *ImportTypeResolver) VisitConstructorDeclaration(n *ast.ConstructorDeclaration) (interface{}, error) {
-	return ast.VisitConstructorDeclaration(v, n)
+	v.visitHeader(n.Annotations, n.Modifiers)
+	for _, p := range n.Parameters {
+		p.TypeRef.Accept(v)
+	}
+	return n.Statements.Accept(v)
 }
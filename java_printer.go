@@ -0,0 +1,229 @@
+package main
+
+import (
+	"strings"
+)
+
+const (
+	// javaIndent is the indent of a block.
+	javaIndent = 4
+	// javaContinuationIndent is the indent of the wrapped parameters of a
+	// method declaration.
+	javaContinuationIndent = 8
+	// javaLineWidth is the width beyond which parameter lists are wrapped.
+	javaLineWidth = 100
+)
+
+// javaPrinter prints the declarations and statements of the Java syntax
+// tree. It handles their indentation, the blank lines between members, the
+// semicolons ending statements and the wrapping of long parameter lists, and
+// records the Apex line each line comes from. Expressions are printed as
+// the generator wrote them.
+type javaPrinter struct {
+	indent int
+	lines  []string
+	// sourceLines are the Apex lines of lines, for SourceMap. A line comes
+	// from the line of the javaSourced node printed last.
+	sourceLines []int
+	sourceLine  int
+	// trailing are the comments going at the end of the next line of code.
+	trailing []string
+}
+
+// printJava returns the Java source of n and the Apex line of each of its
+// lines.
+func printJava(n javaNode) (string, []int) {
+	p := &javaPrinter{}
+	n.printJava(p)
+	return strings.Join(p.lines, "\n"), p.sourceLines
+}
+
+// println prints a line at the current indent. Empty lines are not indented.
+func (p *javaPrinter) println(text string) {
+	if text != "" {
+		text = strings.Repeat(" ", p.indent) + text
+	}
+	if len(p.trailing) != 0 {
+		text += " " + strings.Join(p.trailing, " ")
+		p.trailing = nil
+	}
+	p.lines = append(p.lines, text)
+	p.sourceLines = append(p.sourceLines, p.sourceLine)
+}
+
+// printIndented prints the lines printed by f one level deeper.
+func (p *javaPrinter) printIndented(f func()) {
+	p.indent += javaIndent
+	f()
+	p.indent -= javaIndent
+}
+
+func (p *javaPrinter) printStatements(b *javaBlock) {
+	p.printIndented(func() {
+		for _, s := range b.Statements {
+			s.printJava(p)
+		}
+	})
+}
+
+// printHeader prints the annotations of a declaration and returns its
+// modifiers followed by words, separated by single spaces. Trailing
+// comments are kept for the line of the declaration itself.
+func (p *javaPrinter) printHeader(h javaHeader, words ...string) string {
+	trailing := p.trailing
+	p.trailing = nil
+	for _, a := range h.Annotations {
+		p.println(a)
+	}
+	p.trailing = trailing
+	parts := []string{}
+	for _, w := range append(append([]string{}, h.Modifiers...), words...) {
+		if w != "" {
+			parts = append(parts, w)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (n *javaTypeDeclaration) printJava(p *javaPrinter) {
+	words := []string{n.Kind, n.Name}
+	if n.Extends != "" {
+		words = append(words, "extends", n.Extends)
+	}
+	if len(n.Implements) != 0 {
+		words = append(words, "implements", strings.Join(n.Implements, ", "))
+	}
+	p.println(p.printHeader(n.javaHeader, words...) + " {")
+	p.printIndented(func() {
+		for i, c := range n.Constants {
+			switch {
+			case i < len(n.Constants)-1:
+				c += ","
+			case len(n.Members) != 0:
+				c += ";"
+			}
+			p.println(c)
+		}
+		for i, m := range n.Members {
+			if i > 0 || len(n.Constants) != 0 {
+				separateMembers(p, n.Members, i)
+			}
+			m.printJava(p)
+		}
+	})
+	p.println("}")
+}
+
+// separateMembers prints the blank line going before members[i]: members
+// are separated by one, except consecutive fields and the comments closing
+// the declaration.
+func separateMembers(p *javaPrinter, members []javaNode, i int) {
+	if _, ok := members[i].(javaLines); ok {
+		return
+	}
+	if i > 0 && isJavaField(members[i-1]) && isJavaField(members[i]) {
+		return
+	}
+	p.println("")
+}
+
+func isJavaField(n javaNode) bool {
+	if s, ok := n.(*javaSourced); ok {
+		n = s.Node
+	}
+	_, ok := n.(*javaField)
+	return ok
+}
+
+func (n *javaField) printJava(p *javaPrinter) {
+	p.println(p.printHeader(n.javaHeader, n.Type, strings.Join(n.Declarators, ", ")) + ";")
+}
+
+// printJava prints the method on one line up to its body, or with one
+// parameter per line if that is wider than javaLineWidth.
+func (n *javaMethod) printJava(p *javaPrinter) {
+	end := ";"
+	if n.Body != nil {
+		end = " {"
+	}
+	header := p.printHeader(n.javaHeader, n.ReturnType, n.Name)
+	line := header + "(" + strings.Join(n.Parameters, ", ") + ")" + end
+	if p.indent+len(line) <= javaLineWidth || len(n.Parameters) < 2 {
+		p.println(line)
+	} else {
+		p.println(header + "(")
+		continuation := strings.Repeat(" ", javaContinuationIndent)
+		for i, param := range n.Parameters {
+			if i < len(n.Parameters)-1 {
+				p.println(continuation + param + ",")
+			} else {
+				p.println(continuation + param + ")" + end)
+			}
+		}
+	}
+	if n.Body != nil {
+		p.printStatements(n.Body)
+		p.println("}")
+	}
+}
+
+func (n *javaInitializer) printJava(p *javaPrinter) {
+	if n.Static {
+		p.println("static {")
+	} else {
+		p.println("{")
+	}
+	p.printStatements(n.Body)
+	p.println("}")
+}
+
+func (n *javaBlock) printJava(p *javaPrinter) {
+	p.println("{")
+	p.printStatements(n)
+	p.println("}")
+}
+
+func (n *javaStatement) printJava(p *javaPrinter) {
+	if n.Code == "" {
+		return
+	}
+	p.println(n.Code + ";")
+}
+
+func (n *javaCompound) printJava(p *javaPrinter) {
+	for i, c := range n.Clauses {
+		if i == 0 {
+			p.println(c.Header + " {")
+		} else {
+			p.println("} " + c.Header + " {")
+		}
+		p.printStatements(c.Body)
+	}
+	if n.Trailer != "" {
+		p.println("} " + n.Trailer + ";")
+	} else {
+		p.println("}")
+	}
+}
+
+func (n javaNodes) printJava(p *javaPrinter) {
+	for _, node := range n {
+		node.printJava(p)
+	}
+}
+
+func (n javaLines) printJava(p *javaPrinter) {
+	for _, line := range n {
+		p.println(line)
+	}
+}
+
+func (n *javaSourced) printJava(p *javaPrinter) {
+	if n.Line > 0 {
+		p.sourceLine = n.Line
+	}
+	javaLines(n.Leading).printJava(p)
+	p.trailing = n.Trailing
+	n.Node.printJava(p)
+	p.trailing = nil
+}
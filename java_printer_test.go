@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestPrintJava(t *testing.T) {
+	loop := newCompound("do", newBlock("i++"))
+	loop.Trailer = "while (i < 10)"
+	tree := &javaTypeDeclaration{
+		javaHeader: javaHeader{Modifiers: []string{"public"}},
+		Kind:       "class",
+		Name:       "Printed",
+		Members: []javaNode{
+			&javaTypeDeclaration{Kind: "enum", Name: "Color", Constants: []string{"RED", "GREEN"}},
+			&javaField{Type: "Integer", Declarators: []string{"i = 0"}},
+			&javaSourced{Node: &javaField{Type: "Integer", Declarators: []string{"j"}}},
+			&javaSourced{
+				Node: &javaMethod{
+					javaHeader: javaHeader{Annotations: []string{"@Override"}, Modifiers: []string{"public", "static"}},
+					ReturnType: "String",
+					Name:       "describe",
+					Parameters: []string{"String accountName", "Integer numberOfEmployees", "Boolean includeOpportunities"},
+					Body:       &javaBlock{Statements: []javaNode{loop, &javaBlock{}}},
+				},
+				Leading:  []string{"// describes"},
+				Trailing: []string{"// wrapped"},
+			},
+			&javaMethod{ReturnType: "void", Name: "run"},
+			javaLines{"// closing"},
+		},
+	}
+	expected := `public class Printed {
+    enum Color {
+        RED,
+        GREEN
+    }
+
+    Integer i = 0;
+    Integer j;
+
+    // describes
+    @Override
+    public static String describe( // wrapped
+            String accountName,
+            Integer numberOfEmployees,
+            Boolean includeOpportunities) {
+        do {
+            i++;
+        } while (i < 10);
+        {
+        }
+    }
+
+    void run();
+    // closing
+}`
+	if actual, _ := printJava(tree); actual != expected {
+		t.Errorf("--- expected\n%s\n--- actual\n%s", expected, actual)
+	}
+}
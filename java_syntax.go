@@ -0,0 +1,109 @@
+package main
+
+// javaNode is a declaration or statement of the Java syntax tree Generator
+// builds, which javaPrinter prints. The tree stops at statements:
+// expressions, types and parameters are kept as their source text, on one
+// line.
+type javaNode interface {
+	printJava(p *javaPrinter)
+}
+
+// javaHeader is the annotations and modifiers of a declaration.
+type javaHeader struct {
+	Annotations []string
+	Modifiers   []string
+}
+
+// javaTypeDeclaration is a class, interface or enum.
+type javaTypeDeclaration struct {
+	javaHeader
+	// Kind is class, interface or enum.
+	Kind       string
+	Name       string
+	Extends    string
+	Implements []string
+	// Constants are the constants of an enum.
+	Constants []string
+	Members   []javaNode
+}
+
+// javaField is a field declaration.
+type javaField struct {
+	javaHeader
+	Type        string
+	Declarators []string
+}
+
+// javaMethod is a method or, without a return type, a constructor. Methods
+// without a body are abstract.
+type javaMethod struct {
+	javaHeader
+	ReturnType string
+	Name       string
+	Parameters []string
+	Body       *javaBlock
+}
+
+// javaInitializer is an instance or static initializer.
+type javaInitializer struct {
+	Static bool
+	Body   *javaBlock
+}
+
+// javaBlock is a block of statements, which is printed with braces when it
+// is a statement itself.
+type javaBlock struct {
+	Statements []javaNode
+}
+
+// javaStatement is a statement without a body, such as an expression or a
+// return, which the printer ends with a semicolon.
+type javaStatement struct {
+	Code string
+}
+
+// javaCompound is a statement made of clauses with blocks, such as if-else,
+// try-catch-finally and the loops. Trailer follows the last block and ends
+// the statement with a semicolon, as the condition of a do-while does.
+type javaCompound struct {
+	Clauses []*javaClause
+	Trailer string
+}
+
+// javaClause is a clause of a javaCompound, such as "else if (a)" or
+// "catch (Exception e)", and its block.
+type javaClause struct {
+	Header string
+	Body   *javaBlock
+}
+
+// javaNodes are nodes emitted in place of one, such as the method queueing
+// a @future call and the method it calls, which become members of their
+// own.
+type javaNodes []javaNode
+
+// javaLines are lines printed as they are at the current indent, such as
+// comments.
+type javaLines []string
+
+// javaSourced is a node converted from the Apex source line Line, with the
+// comment lines that go above it and the comments that go at the end of
+// its first line of code.
+type javaSourced struct {
+	Node     javaNode
+	Line     int
+	Leading  []string
+	Trailing []string
+}
+
+func newCompound(header string, body *javaBlock) *javaCompound {
+	return &javaCompound{Clauses: []*javaClause{{Header: header, Body: body}}}
+}
+
+func newBlock(statements ...string) *javaBlock {
+	b := &javaBlock{}
+	for _, s := range statements {
+		b.Statements = append(b.Statements, &javaStatement{Code: s})
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// Apex test classes are emitted as JUnit 5 test classes. The JUnit
+// annotations are fully qualified, since the runtime's Test class would
+// clash with an import of org.junit.jupiter.api.Test.
+const (
+	junitTest       = "@org.junit.jupiter.api.Test"
+	junitBeforeEach = "@org.junit.jupiter.api.BeforeEach"
+)
+
+func hasAnnotation(annotations []*ast.Annotation, name string) bool {
+	for _, a := range annotations {
+		if strings.ToLower(a.Name) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestClass reports whether n is annotated @IsTest or, as older Apex
+// test classes are, only declares testMethod methods.
+func isTestClass(n *ast.ClassDeclaration) bool {
+	if hasAnnotation(n.Annotations, "istest") {
+		return true
+	}
+	for _, d := range n.Declarations {
+		if m, ok := d.(*ast.MethodDeclaration); ok && isTestMethod(m) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTestMethod(n *ast.MethodDeclaration) bool {
+	return hasAnnotation(n.Annotations, "istest") || hasModifier(n.Modifiers, "testmethod")
+}
+
+func isTestSetupMethod(n *ast.MethodDeclaration) bool {
+	return hasAnnotation(n.Annotations, "testsetup")
+}
+
+// junitAnnotation returns the JUnit annotation of the test or test setup
+// method n, or "" if n is neither.
+func junitAnnotation(n *ast.MethodDeclaration) string {
+	switch {
+	case isTestMethod(n):
+		return junitTest
+	case isTestSetupMethod(n):
+		return junitBeforeEach
+	}
+	return ""
+}
+
+// junitHeader returns the annotations and modifiers of the test method n
+// without the ones JUnit replaces. JUnit runs test methods on an instance,
+// so they cannot be static or private.
+func junitHeader(n *ast.MethodDeclaration) ([]*ast.Annotation, []*ast.Modifier) {
+	annotations := []*ast.Annotation{}
+	for _, a := range n.Annotations {
+		switch strings.ToLower(a.Name) {
+		case "istest", "testsetup":
+			continue
+		}
+		annotations = append(annotations, a)
+	}
+	modifiers := []*ast.Modifier{}
+	for _, m := range n.Modifiers {
+		switch modifierName(m) {
+		case "static", "private", "testmethod":
+			continue
+		}
+		modifiers = append(modifiers, m)
+	}
+	return annotations, modifiers
+}
+
+// hasTestSetupMethod reports whether the test class n declares a method
+// that runs before each test.
+func hasTestSetupMethod(n *ast.ClassDeclaration) bool {
+	for _, d := range n.Declarations {
+		if m, ok := d.(*ast.MethodDeclaration); ok && isTestSetupMethod(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// testContextReset is the first statement run before each test, which
+// discards the records and state left by the previous one. Apex rolls
+// them back after every test method.
+const testContextReset = "Test.reset()"
+
+// junitResetMethod returns the method that resets the test context of a
+// test class without a test setup method.
+func (v *Generator) junitResetMethod() javaNode {
+	return &javaMethod{
+		javaHeader: javaHeader{Annotations: []string{junitBeforeEach}, Modifiers: []string{"public"}},
+		ReturnType: "void",
+		Name:       "resetTestContext",
+		Body:       newBlock(testContextReset),
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// todoPrefix starts the comments emitted for the Apex a lenient conversion
+// leaves out.
+const todoPrefix = "// TODO(apex2java): "
+
+// unsupported emits the placeholder for a node Generator cannot convert. In
+// lenient mode, statements become a throw of UnsupportedOperationException,
+// expressions a call of the runtime's Unsupported.raise, which throws it and
+// takes the declared or assigned type of the expression, else its inferred
+// type, and properties a field; a TODO comment with the Apex source goes above the
+// statement or declaration. Otherwise the conversion fails.
+func (v *Generator) unsupported(n ast.Node) (interface{}, error) {
+	construct, ok := UnsupportedNodes[n.GetType()]
+	if !ok {
+		construct = n.GetType()
+	}
+	return v.unsupportedConstruct(n, construct)
+}
+
+// unsupportedConstruct emits the placeholder for n, described as construct,
+// as unsupported does.
+func (v *Generator) unsupportedConstruct(n ast.Node, construct string) (interface{}, error) {
+	message, err := v.leaveOut(construct, nodeLocation(n))
+	if err != nil {
+		return nil, err
+	}
+	switch n := n.(type) {
+	case *ast.PropertyDeclaration:
+		return v.propertyField(n)
+	}
+	if _, ok := n.GetParent().(*ast.Block); ok {
+		return fmt.Sprintf("throw new UnsupportedOperationException(%s)", javaString(message)), nil
+	}
+	v.importClass("unsupported")
+	typeArgument := ""
+	t, ok := v.targets[n]
+	if !ok {
+		t = v.typeOf(n)
+	}
+	if t != nil {
+		// the type argument makes the call compile where Java infers none,
+		// such as in conditions
+		v.importType(t)
+		r, err := t.Accept(v)
+		if err != nil {
+			return nil, err
+		}
+		typeArgument = "<" + r.(string) + ">"
+	}
+	return fmt.Sprintf("Unsupported.%sraise(%s)", typeArgument, javaString(message)), nil
+}
+
+// leaveOut fails the conversion of the construct at loc or, in lenient
+// mode, adds its TODO comment and returns its description for the
+// placeholder.
+func (v *Generator) leaveOut(construct string, loc *ast.Location) (string, error) {
+	where := "unknown location"
+	if loc != nil {
+		where = fmt.Sprintf("%s:%d", filepath.Base(loc.FileName), loc.Line)
+	}
+	message := construct + " at " + where
+	if !v.Lenient {
+		return "", fmt.Errorf("%s is not supported (-lenient emits a placeholder)", message)
+	}
+	todo := message
+	if loc != nil {
+		if line := v.sourceLine(loc.Line); line != "" {
+			todo += ": " + line
+		}
+	}
+	v.todos = append(v.todos, todo)
+	return message, nil
+}
+
+// propertyField returns the field standing in for a property.
+func (v *Generator) propertyField(n *ast.PropertyDeclaration) (interface{}, error) {
+	header, err := v.declarationHeader(n.Annotations, n.Modifiers)
+	if err != nil {
+		return nil, err
+	}
+	t, err := n.TypeRef.Accept(v)
+	if err != nil {
+		return nil, err
+	}
+	return &javaField{javaHeader: header, Type: t.(string), Declarators: []string{n.Identifier}}, nil
+}
+
+// takeTodos returns the TODO comments added since the todos had length
+// start and removes them.
+func (v *Generator) takeTodos(start int) []string {
+	comments := []string{}
+	for _, todo := range v.todos[start:] {
+		comments = append(comments, todoPrefix+todo)
+	}
+	v.todos = v.todos[:start]
+	return comments
+}
+
+// sourceLine returns the line of the Apex source, trimmed.
+func (v *Generator) sourceLine(line int) string {
+	lines := strings.Split(v.Source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+// nodeLocation returns the location of n or, for nodes the AST builder does
+// not locate, such as properties, of the first node under n with one.
+func nodeLocation(n ast.Node) *ast.Location {
+	if l := n.GetLocation(); l != nil {
+		return l
+	}
+	return firstLocation(reflect.ValueOf(n))
+}
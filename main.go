@@ -1,9 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/antlr/antlr4/runtime/Go/antlr"
 	"github.com/k0kubun/pp"
@@ -11,25 +14,135 @@ import (
 	"github.com/tzmfreedom/land/parser"
 )
 
+// Version is the version of apex2java, set at build time.
+var Version = "0.1.0"
+
 func main() {
-	file := os.Args[1]
-	node, err := ParseFile(file)
-	if err != nil {
-		panic(err)
+	if len(os.Args) > 1 && os.Args[1] == "runtime" {
+		runtimeCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "report" {
+		reportCommand(os.Args[2:])
+		return
+	}
+	version := flag.Bool("version", false, "print the version and exit")
+	nullSafe := flag.Bool("null-safe", false, "emit arithmetic on boxed primitives with Apex null semantics")
+	lenient := flag.Bool("lenient", false, "emit placeholders and TODO comments for unsupported Apex instead of failing")
+	srcDir := flag.String("d", "", "convert every Apex class in `DIR`")
+	outDir := flag.String("o", "", "write a build project with the runtime and the converted classes to `DIR`")
+	build := flag.String("build", "maven", "build tool of the project written by -o: maven or gradle")
+	sourceMap := flag.String("source-map", "", "write the source map of the converted FILE to `MAP`")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: apex2java [-null-safe] [-lenient] [-source-map MAP] FILE")
+		fmt.Fprintln(os.Stderr, "       apex2java [-null-safe] [-lenient] [-build maven|gradle] -o DIR [-d DIR] [FILE...]")
+		fmt.Fprintln(os.Stderr, "       apex2java runtime [-o DIR]")
+		fmt.Fprintln(os.Stderr, "       apex2java report [-format json|html] [-o FILE] [-d DIR] [FILE...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *version {
+		fmt.Printf("apex2java %s (runtime %s)\n", Version, runtimeVersion())
+		return
+	}
+	files := flag.Args()
+	if *srcDir != "" {
+		classes, err := apexSources(*srcDir)
+		if err != nil {
+			panic(err)
+		}
+		files = append(files, classes...)
+	}
+	if len(files) == 0 || (*outDir == "" && len(files) != 1) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	sources := make([]string, len(files))
+	nodes := make([]ast.Node, len(files))
+	for i, file := range files {
+		apex, err := ioutil.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+		sources[i] = string(apex)
+		nodes[i] = parse(sources[i], file)
+	}
+	// values() and valueOf() are converted on the enums of every file
+	enums := enumNames(nodes...)
+	javaFiles := make([]*JavaFile, len(files))
+	for i, file := range files {
+		node := nodes[i]
+		generator := &Generator{
+			NullSafeArithmetic: *nullSafe,
+			RuntimeVersion:     runtimeVersion(),
+			Lenient:            *lenient,
+			Source:             sources[i],
+			Enums:              enums,
+		}
+		src, err := Convert(node, generator)
+		if err != nil {
+			panic(err)
+		}
+		javaFiles[i] = NewJavaFile(node, src)
+		javaFiles[i].SourceMap = generator.SourceMap(file)
 	}
+	if *outDir == "" {
+		if *sourceMap != "" {
+			if err := writeFile(*sourceMap, javaFiles[0].SourceMap.JSON()); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+		fmt.Print(javaFiles[0].Source)
+		return
+	}
+	if err := WriteProject(*outDir, *build, javaFiles); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// runtimeCommand writes the sources of the runtime the converted classes
+// need, for builds that do not use the project written by -o.
+func runtimeCommand(args []string) {
+	flags := flag.NewFlagSet("runtime", flag.ExitOnError)
+	outDir := flags.String("o", ".", "write the runtime sources under `DIR`")
+	flags.Parse(args)
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if err := writeRuntime(*outDir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Convert generates the Java source for the parsed Apex file n, headed by
+// imports of the runtime classes it refers to.
+func Convert(n ast.Node, generator *Generator) (string, error) {
 	resolver := NewImportTypeResolver()
-	_, err = resolver.Resolve(node)
-	if err != nil {
-		panic(err)
+	resolver.NullSafeArithmetic = generator.NullSafeArithmetic
+	resolver.RuntimeVersion = generator.RuntimeVersion
+	if _, err := resolver.Resolve(n); err != nil {
+		return "", err
+	}
+	src := generator.Generate(n)
+	for key := range generator.imports {
+		resolver.importClasses[ImportClasses[key]] = struct{}{}
 	}
-	for importClass, _ := range resolver.importClasses {
+	imports := []string{}
+	for importClass := range resolver.importClasses {
 		if importClass == "" {
 			continue
 		}
-		fmt.Printf("import %s;\n", importClass)
+		imports = append(imports, fmt.Sprintf("import %s;\n", importClass))
 	}
-	fmt.Println()
-	fmt.Println(Generate(node))
+	sort.Strings(imports)
+	header := strings.Join(imports, "") + "\n"
+	// the source map covers the imports too
+	generator.lines = append(make([]int, strings.Count(header, "\n")), generator.lines...)
+	return header + src + "\n", nil
 }
 
 func ParseFile(f string) (ast.Node, error) {
@@ -37,27 +150,42 @@ func ParseFile(f string) (ast.Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	input := antlr.NewInputStream(string(bytes))
-	return parse(input, f), nil
+	return parse(string(bytes), f), nil
 }
 
 func ParseString(src string) (ast.Node, error) {
-	input := antlr.NewInputStream(src)
-	return parse(input, "<string>"), nil
+	return parse(src, "<string>"), nil
 }
 
-func parse(input antlr.CharStream, src string) ast.Node {
-	lexer := parser.NewapexLexer(input)
+func parse(code string, src string) ast.Node {
+	code, inheritedSharing := rewriteInheritedSharing(code, src)
+	code, searches := rewriteSearches(code, src)
+	code, exactInequalities := rewriteExactInequalities(code, src)
+	lexer := parser.NewapexLexer(antlr.NewInputStream(rewriteDateLiterals(code)))
 	stream := antlr.NewCommonTokenStream(lexer, 0)
 	p := parser.NewapexParser(stream)
 	p.AddErrorListener(antlr.NewDiagnosticErrorListener(true))
 	p.BuildParseTrees = true
-	tree := p.CompilationUnit()
+	tree := p.CompilationUnit().(*parser.CompilationUnitContext)
+	if e := topLevelEnum(tree, src); e != nil {
+		return e
+	}
+	restoreExactInequalities(tree, exactInequalities, src)
+	enums := liftEnums(tree, src)
+	annotations := annotationParameters(tree, src)
+	queries := readQueries(tree, code, src)
+	runAs := readRunAs(tree, src)
 	t := tree.Accept(&ast.Builder{
 		Source: src,
 	})
+	attachEnums(t.(ast.Node), enums)
+	attachAnnotationParameters(t.(ast.Node), annotations)
+	replaceQueries(t.(ast.Node), queries)
+	replaceRunAs(t.(ast.Node), runAs)
+	replaceTypeLiterals(t.(ast.Node))
+	restoreInheritedSharing(t.(ast.Node), inheritedSharing)
+	restoreSearches(t.(ast.Node), searches)
 	return t.(ast.Node)
-	//return nil
 }
 
 func debug(args ...interface{}) {
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// JavaModifiers maps Apex modifiers to the Java modifier they are emitted
+// as. An empty string drops the modifier; modifiers not listed are emitted
+// unchanged.
+var JavaModifiers = map[string]string{
+	"global":            "public",
+	"webservice":        "public",
+	"virtual":           "",
+	"override":          "",
+	"testmethod":        "",
+	"with sharing":      "",
+	"without sharing":   "",
+	"inherited sharing": "",
+}
+
+// ModifierAnnotations holds the Apex modifiers that have no Java keyword
+// and are emitted as annotations instead, with the runtime class they need.
+var ModifierAnnotations = map[string]struct {
+	Annotation string
+	Import     string
+}{
+	"override":          {"@Override", ""},
+	"testmethod":        {"@IsTest", "istest"},
+	"with sharing":      {"@Sharing(Sharing.Mode.WITH)", "sharing"},
+	"without sharing":   {"@Sharing(Sharing.Mode.WITHOUT)", "sharing"},
+	"inherited sharing": {"@Sharing(Sharing.Mode.INHERITED)", "sharing"},
+}
+
+// modifierName normalizes an Apex modifier for the lookup tables: modifiers
+// are case-insensitive and sharing modifiers are two words.
+func modifierName(m *ast.Modifier) string {
+	return strings.Join(strings.Fields(strings.ToLower(m.Name)), " ")
+}
+
+func hasModifier(modifiers []*ast.Modifier, names ...string) bool {
+	for _, m := range modifiers {
+		for _, name := range names {
+			if modifierName(m) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func removeModifier(modifiers []string, name string) []string {
+	kept := []string{}
+	for _, m := range modifiers {
+		if m != name {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// modifiers returns the Java modifiers for the Apex modifiers ms, and the
+// annotations that replace the ones Java has no keyword for.
+func (v *Generator) modifiers(ms []*ast.Modifier) ([]string, []string, error) {
+	modifiers := []string{}
+	annotations := []string{}
+	for _, m := range ms {
+		if a, ok := ModifierAnnotations[modifierName(m)]; ok {
+			annotations = append(annotations, a.Annotation)
+		}
+		r, err := m.Accept(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		if r.(string) != "" {
+			modifiers = append(modifiers, r.(string))
+		}
+	}
+	return modifiers, annotations, nil
+}
+
+// isFinalMethod reports whether the Apex method n cannot be overridden.
+// Apex methods are final unless declared virtual, abstract or override.
+func isFinalMethod(n *ast.MethodDeclaration) bool {
+	if _, ok := n.Parent.(*ast.ClassDeclaration); !ok || n.Statements == nil {
+		return false
+	}
+	return !hasModifier(n.Modifiers, "virtual", "abstract", "override", "static", "private", "final")
+}
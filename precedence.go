@@ -0,0 +1,74 @@
+package main
+
+import "github.com/tzmfreedom/land/ast"
+
+// land drops the parentheses of expressions, leaving their grouping to the
+// shape of the tree, so operands are parenthesized again where Java would
+// group them differently without.
+
+// binaryPrecedence is the precedence of the binary operators in Java, and
+// so in Apex; higher binds tighter.
+var binaryPrecedence = map[string]int{
+	"=": 1, "+=": 1, "-=": 1, "*=": 1, "/=": 1, "%=": 1,
+	"&=": 1, "|=": 1, "^=": 1, "<<=": 1, ">>=": 1, ">>>=": 1,
+	"||": 3,
+	"&&": 4,
+	"|":  5,
+	"^":  6,
+	"&":  7,
+	"==": 8, "!=": 8, "===": 8, "!==": 8,
+	"<": 9, ">": 9, "<=": 9, ">=": 9,
+	"<<": 10, ">>": 10, ">>>": 10,
+	"+": 11, "-": 11,
+	"*": 12, "/": 12, "%": 12,
+}
+
+const (
+	ternaryPrecedence    = 2
+	instanceofPrecedence = 9
+	unaryPrecedence      = 13
+	postfixPrecedence    = 14
+	primaryPrecedence    = 15
+)
+
+// precedence returns the precedence of the expression n.
+func precedence(n ast.Node) int {
+	switch e := n.(type) {
+	case *ast.BinaryOperator:
+		if p, ok := binaryPrecedence[e.Op]; ok {
+			return p
+		}
+	case *ast.TernalyExpression:
+		return ternaryPrecedence
+	case *ast.InstanceofOperator:
+		return instanceofPrecedence
+	case *ast.UnaryOperator:
+		if isPrefix(e) {
+			return unaryPrecedence
+		}
+		return postfixPrecedence
+	case *ast.CastExpression:
+		return unaryPrecedence
+	}
+	return primaryPrecedence
+}
+
+// operand returns the generated expression code of n, parenthesized if n
+// binds looser than min, the precedence its position requires.
+func operand(n ast.Node, code string, min int) string {
+	if precedence(n) < min {
+		return "(" + code + ")"
+	}
+	return code
+}
+
+// binaryOperands returns the operands of the binary operator n,
+// parenthesized for emitting them around it.
+func binaryOperands(n *ast.BinaryOperator, l, r string) (string, string) {
+	p := binaryPrecedence[n.Op]
+	if p == binaryPrecedence["="] {
+		// assignments group to the right
+		return operand(n.Left, l, p+1), operand(n.Right, r, p)
+	}
+	return operand(n.Left, l, p), operand(n.Right, r, p+1)
+}
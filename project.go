@@ -0,0 +1,276 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+//go:embed com/freedom_man/system/*.java
+var runtimeSources embed.FS
+
+const (
+	junitVersion    = "5.10.2"
+	surefireVersion = "3.2.5"
+	javaRelease     = "11"
+)
+
+// JavaFile is a converted Apex class.
+type JavaFile struct {
+	Name   string
+	Source string
+	// Test is set for Apex test classes, which go to the test sources.
+	Test bool
+	// SourceMap, if set, is written to the resources next to the class for
+	// the runtime to report Apex lines with.
+	SourceMap *SourceMap
+}
+
+func NewJavaFile(n ast.Node, source string) *JavaFile {
+	f := &JavaFile{Name: className(n), Source: source}
+	if class, ok := n.(*ast.ClassDeclaration); ok {
+		f.Test = isTestClass(class)
+	}
+	return f
+}
+
+func className(n ast.Node) string {
+	switch decl := n.(type) {
+	case *ast.ClassDeclaration:
+		return decl.Name
+	case *ast.InterfaceDeclaration:
+		return decl.Name
+	case *EnumDeclaration:
+		return decl.Name
+	}
+	return ""
+}
+
+// WriteProject writes a build project to dir with two modules: runtime,
+// holding the Java runtime, and app, holding the converted files with
+// JUnit set up for the converted tests. build is "maven" or "gradle".
+func WriteProject(dir, build string, files []*JavaFile) error {
+	var buildFiles map[string]string
+	switch build {
+	case "maven":
+		buildFiles = map[string]string{
+			"pom.xml":         mavenParentPom,
+			"runtime/pom.xml": mavenRuntimePom,
+			"app/pom.xml":     mavenAppPom,
+		}
+	case "gradle":
+		buildFiles = map[string]string{
+			"settings.gradle":      gradleSettings,
+			"runtime/build.gradle": gradleRuntimeBuild,
+			"app/build.gradle":     gradleAppBuild,
+		}
+	default:
+		return fmt.Errorf("unknown build tool: %s", build)
+	}
+	for name, content := range buildFiles {
+		if err := writeFile(filepath.Join(dir, name), content); err != nil {
+			return err
+		}
+	}
+	if err := writeRuntime(filepath.Join(dir, "runtime", "src", "main", "java")); err != nil {
+		return err
+	}
+	for _, f := range files {
+		sourceSet := "main"
+		if f.Test {
+			sourceSet = "test"
+		}
+		file := filepath.Join(dir, "app", "src", sourceSet, "java", f.Name+".java")
+		if err := writeFile(file, f.Source); err != nil {
+			return err
+		}
+		if f.SourceMap != nil {
+			file := filepath.Join(dir, "app", "src", sourceSet, "resources", "apex2java", f.Name+".map.json")
+			if err := writeFile(file, f.SourceMap.JSON()); err != nil {
+				return err
+			}
+		}
+	}
+	for name, content := range testFiles {
+		if err := writeFile(filepath.Join(dir, "app", "src", "test", filepath.FromSlash(name)), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testFiles are the files of the app tests, which register a JUnit
+// extension rewriting the stack traces of failed tests to Apex lines.
+var testFiles = map[string]string{
+	"java/com/freedom_man/system/junit/ApexStackTraces.java":                junitApexStackTraces,
+	"resources/META-INF/services/org.junit.jupiter.api.extension.Extension": "com.freedom_man.system.junit.ApexStackTraces\n",
+	"resources/junit-platform.properties":                                   "junit.jupiter.extensions.autodetection.enabled=true\n",
+}
+
+var junitApexStackTraces = `package com.freedom_man.system.junit;
+
+import com.freedom_man.system.SourceMap;
+import org.junit.jupiter.api.extension.ExtensionContext;
+import org.junit.jupiter.api.extension.TestExecutionExceptionHandler;
+
+// ApexStackTraces reports the exceptions failing tests with the Apex lines
+// of the converted classes.
+public class ApexStackTraces implements TestExecutionExceptionHandler {
+    @Override
+    public void handleTestExecutionException(ExtensionContext context, Throwable throwable) throws Throwable {
+        throw SourceMap.rewrite(throwable);
+    }
+}
+`
+
+// writeRuntime writes the embedded runtime sources under dir.
+func writeRuntime(dir string) error {
+	return fs.WalkDir(runtimeSources, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		src, err := runtimeSources.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		return writeFile(filepath.Join(dir, filepath.FromSlash(name)), string(src))
+	})
+}
+
+func writeFile(file, content string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, []byte(content), 0644)
+}
+
+// apexSources returns the Apex class files in dir.
+func apexSources(dir string) ([]string, error) {
+	files := []string{}
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && path.Ext(file) == ".cls" {
+			files = append(files, file)
+		}
+		return nil
+	})
+	return files, err
+}
+
+var mavenParentPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 http://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.freedom_man</groupId>
+  <artifactId>apex2java-project</artifactId>
+  <version>1.0-SNAPSHOT</version>
+  <packaging>pom</packaging>
+
+  <modules>
+    <module>runtime</module>
+    <module>app</module>
+  </modules>
+
+  <properties>
+    <project.build.sourceEncoding>UTF-8</project.build.sourceEncoding>
+    <maven.compiler.release>` + javaRelease + `</maven.compiler.release>
+  </properties>
+</project>
+`
+
+var mavenRuntimePom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 http://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.freedom_man</groupId>
+    <artifactId>apex2java-project</artifactId>
+    <version>1.0-SNAPSHOT</version>
+  </parent>
+  <artifactId>runtime</artifactId>
+</project>
+`
+
+var mavenAppPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 http://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>com.freedom_man</groupId>
+    <artifactId>apex2java-project</artifactId>
+    <version>1.0-SNAPSHOT</version>
+  </parent>
+  <artifactId>app</artifactId>
+
+  <dependencies>
+    <dependency>
+      <groupId>com.freedom_man</groupId>
+      <artifactId>runtime</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>org.junit.jupiter</groupId>
+      <artifactId>junit-jupiter</artifactId>
+      <version>` + junitVersion + `</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>` + surefireVersion + `</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+
+var gradleSettings = `rootProject.name = 'apex2java-project'
+include 'runtime', 'app'
+`
+
+var gradleRuntimeBuild = `plugins {
+    id 'java-library'
+}
+
+tasks.withType(JavaCompile).configureEach {
+    options.release = ` + javaRelease + `
+}
+`
+
+var gradleAppBuild = `plugins {
+    id 'java'
+}
+
+repositories {
+    mavenCentral()
+}
+
+tasks.withType(JavaCompile).configureEach {
+    options.release = ` + javaRelease + `
+}
+
+dependencies {
+    implementation project(':runtime')
+    testImplementation 'org.junit.jupiter:junit-jupiter:` + junitVersion + `'
+    testRuntimeOnly 'org.junit.platform:junit-platform-launcher'
+}
+
+test {
+    useJUnitPlatform()
+}
+`
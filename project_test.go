@@ -0,0 +1,59 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteProject(t *testing.T) {
+	expected := map[string][]string{
+		"maven":  {"pom.xml", "runtime/pom.xml", "app/pom.xml"},
+		"gradle": {"settings.gradle", "runtime/build.gradle", "app/build.gradle"},
+	}
+	for build, buildFiles := range expected {
+		t.Run(build, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "apex2java")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			files := []*JavaFile{
+				{Name: "Service", Source: "public class Service {}\n", SourceMap: &SourceMap{Lines: []int{1}}},
+				{Name: "ServiceTest", Source: "class ServiceTest {}\n", Test: true},
+			}
+			if err := WriteProject(dir, build, files); err != nil {
+				t.Fatal(err)
+			}
+			paths := append(buildFiles,
+				"runtime/src/main/java/com/freedom_man/system/System.java",
+				"app/src/main/java/Service.java",
+				"app/src/test/java/ServiceTest.java",
+				"app/src/main/resources/apex2java/Service.map.json",
+				"app/src/test/java/com/freedom_man/system/junit/ApexStackTraces.java",
+				"app/src/test/resources/junit-platform.properties",
+			)
+			for _, p := range paths {
+				if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(p))); err != nil {
+					t.Errorf("%s not written: %s", p, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteProjectUnknownBuild(t *testing.T) {
+	if err := WriteProject(os.TempDir(), "ant", nil); err == nil {
+		t.Error("expected an error for an unknown build tool")
+	}
+}
+
+// TestRuntimeVersion fails when a release bumps the version of apex2java or
+// of the embedded runtime without the other.
+func TestRuntimeVersion(t *testing.T) {
+	if v := runtimeVersion(); v != Version {
+		t.Errorf("runtime version %s differs from apex2java version %s", v, Version)
+	}
+}
@@ -0,0 +1,481 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// Kinds of report findings.
+const (
+	UnsupportedNode = "unsupported"
+	UnresolvedType  = "unresolved type"
+	UnmappedMethod  = "unmapped method"
+	ParseFailure    = "parse error"
+)
+
+// UnsupportedNodes describes the nodes Generator leaves to the default land
+// visitor, which converts nothing for them, keyed by node type.
+var UnsupportedNodes = map[string]string{
+	"ArrayCreator":        "array creation",
+	"ArrayInitializer":    "array initializer",
+	"MapCreator":          "map initializer",
+	"PropertyDeclaration": "property",
+	"SetCreator":          "set initializer",
+	"Sosl":                "SOSL query",
+	"TernalyExpression":   "ternary expression",
+}
+
+// javaLangTypes are the Apex types emitted as the java.lang types of the
+// same name.
+var javaLangTypes = map[string]struct{}{
+	"boolean": {},
+	"double":  {},
+	"integer": {},
+	"long":    {},
+	"object":  {},
+	"string":  {},
+	"void":    {},
+}
+
+// systemClasses are the Apex system classes whose static methods are
+// converted only if they are StaticMethods or StringStaticMethods.
+var systemClasses = map[string]struct{}{
+	"apexpages":    {},
+	"approval":     {},
+	"crypto":       {},
+	"database":     {},
+	"date":         {},
+	"datetime":     {},
+	"encodingutil": {},
+	"eventbus":     {},
+	"json":         {},
+	"limits":       {},
+	"messaging":    {},
+	"schema":       {},
+	"search":       {},
+	"security":     {},
+	"string":       {},
+	"system":       {},
+	"test":         {},
+	"time":         {},
+	"type":         {},
+	"url":          {},
+	"userinfo":     {},
+}
+
+// Finding is an Apex construct apex2java does not convert.
+type Finding struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// FileReport holds the findings of an Apex file.
+type FileReport struct {
+	File     string         `json:"file"`
+	Findings []*Finding     `json:"findings"`
+	Counts   map[string]int `json:"counts"`
+}
+
+// ConstructCount is the number of findings of a construct in all files.
+type ConstructCount struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Files int    `json:"files"`
+}
+
+// Report is the result of analyzing Apex files for the constructs
+// apex2java does not convert.
+type Report struct {
+	Files      []*FileReport     `json:"files"`
+	CleanFiles int               `json:"cleanFiles"`
+	Totals     map[string]int    `json:"totals"`
+	Constructs []*ConstructCount `json:"constructs"`
+}
+
+// reportCommand writes a report of the constructs the Apex files do not
+// convert, to decide how much of a code base converts cleanly.
+func reportCommand(args []string) {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	srcDir := flags.String("d", "", "report on every Apex class in `DIR`")
+	format := flags.String("format", "json", "format of the report: json or html")
+	outFile := flags.String("o", "", "write the report to `FILE` instead of standard output")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: apex2java report [-format json|html] [-o FILE] [-d DIR] [FILE...]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	files := flags.Args()
+	if *srcDir != "" {
+		classes, err := apexSources(*srcDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		files = append(files, classes...)
+	}
+	if len(files) == 0 || (*format != "json" && *format != "html") {
+		flags.Usage()
+		os.Exit(2)
+	}
+	out := io.Writer(os.Stdout)
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	report := Analyze(files)
+	var err error
+	if *format == "html" {
+		err = report.WriteHTML(out)
+	} else {
+		err = report.WriteJSON(out)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Analyze reports on the Apex files. Types declared by any of the files
+// are resolved; a file that fails to parse is reported as a parse error.
+func Analyze(files []string) *Report {
+	nodes := make([]ast.Node, len(files))
+	parseErrors := make([]error, len(files))
+	declared := map[string]struct{}{}
+	for i, file := range files {
+		nodes[i], parseErrors[i] = parseForReport(file)
+		if nodes[i] != nil {
+			for name := range declaredTypes(nodes[i]) {
+				declared[name] = struct{}{}
+			}
+		}
+	}
+	report := &Report{Files: []*FileReport{}, Totals: map[string]int{}}
+	for i, file := range files {
+		fileReport := &FileReport{File: file, Findings: []*Finding{}, Counts: map[string]int{}}
+		if parseErrors[i] != nil {
+			fileReport.Findings = append(fileReport.Findings, &Finding{Kind: ParseFailure, Name: parseErrors[i].Error()})
+		} else {
+			fileReport.Findings = findings(nodes[i], declared)
+		}
+		for _, f := range fileReport.Findings {
+			fileReport.Counts[f.Kind]++
+			report.Totals[f.Kind]++
+		}
+		if len(fileReport.Findings) == 0 {
+			report.CleanFiles++
+		}
+		report.Files = append(report.Files, fileReport)
+	}
+	report.Constructs = constructCounts(report.Files)
+	return report
+}
+
+// parseForReport parses an Apex file, turning the panics of the parser on
+// Apex it cannot read into an error.
+func parseForReport(file string) (n ast.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			n, err = nil, fmt.Errorf("%v", r)
+		}
+	}()
+	return ParseFile(file)
+}
+
+// declaredTypes returns the lowercased names of the classes, interfaces and
+// enums declared in n.
+func declaredTypes(n ast.Node) map[string]struct{} {
+	names := map[string]struct{}{}
+	walkNodes(n, func(n ast.Node, _ *ast.Location) {
+		switch decl := n.(type) {
+		case *ast.ClassDeclaration:
+			names[strings.ToLower(decl.Name)] = struct{}{}
+		case *ast.InterfaceDeclaration:
+			names[strings.ToLower(decl.Name)] = struct{}{}
+		case *EnumDeclaration:
+			names[strings.ToLower(decl.Name)] = struct{}{}
+		}
+	})
+	return names
+}
+
+// findings returns the unsupported nodes, unresolved types and unmapped
+// system methods of n, in source order.
+func findings(n ast.Node, declared map[string]struct{}) []*Finding {
+	found := []*Finding{}
+	add := func(kind, name string, loc *ast.Location) {
+		f := &Finding{Kind: kind, Name: name}
+		if loc != nil {
+			f.Line, f.Column = loc.Line, loc.Column
+		}
+		found = append(found, f)
+	}
+	walkNodes(n, func(n ast.Node, loc *ast.Location) {
+		if name, ok := UnsupportedNodes[n.GetType()]; ok {
+			add(UnsupportedNode, name, loc)
+		}
+		switch n := n.(type) {
+		case *ast.New:
+			if name, ok := initializer(n); ok {
+				add(UnsupportedNode, name, loc)
+			}
+		case *ast.TypeRef:
+			if n.Dimmension > 0 {
+				add(UnsupportedNode, "array type", loc)
+			}
+			if !resolvedType(n, declared) {
+				add(UnresolvedType, strings.Join(n.Name, "."), loc)
+			}
+		case *ast.MethodInvocation:
+			if name, ok := unmappedMethod(n); ok {
+				add(UnmappedMethod, name, loc)
+			}
+		}
+	})
+	sort.SliceStable(found, func(i, j int) bool {
+		if found[i].Line != found[j].Line {
+			return found[i].Line < found[j].Line
+		}
+		return found[i].Column < found[j].Column
+	})
+	return found
+}
+
+// initializer returns the construct of the initializer of n, which the AST
+// builder reads but Generator drops.
+func initializer(n *ast.New) (string, bool) {
+	switch {
+	case n.Init == nil:
+		return "", false
+	case len(n.Init.Sizes) > 0:
+		return "array creation", true
+	case len(n.Init.Values) > 0:
+		return "map initializer", true
+	case len(n.Init.Records) > 0:
+		return "collection initializer", true
+	}
+	return "", false
+}
+
+// resolvedType reports whether the type t refers to is emitted as a Java,
+// runtime or converted type.
+func resolvedType(t *ast.TypeRef, declared map[string]struct{}) bool {
+	name := strings.ToLower(apexTypeName(t.Name)[0])
+	if _, ok := javaLangTypes[name]; ok {
+		return true
+	}
+	if _, ok := ImportClasses[name]; ok {
+		return true
+	}
+	if _, ok := JavaTypeNames[name]; ok {
+		return true
+	}
+	_, ok := declared[name]
+	return ok
+}
+
+// unmappedMethod returns the name of the static method of a system class n
+// calls if it is not converted.
+func unmappedMethod(n *ast.MethodInvocation) (string, bool) {
+	name, ok := n.NameOrExpression.(*ast.Name)
+	if !ok || len(name.Value) < 2 {
+		return "", false
+	}
+	receiver := apexTypeName(name.Value[:len(name.Value)-1])
+	if len(receiver) != 1 {
+		return "", false
+	}
+	class := strings.ToLower(receiver[0])
+	if _, ok := systemClasses[class]; !ok {
+		return "", false
+	}
+	method := strings.ToLower(name.Value[len(name.Value)-1])
+	if class == "string" {
+		_, ok := StringStaticMethods[method]
+		return strings.Join(name.Value, "."), !ok
+	}
+	if _, ok := staticMethod(n); ok {
+		return "", false
+	}
+	if _, ok := StaticMethods[class+"."+method]; ok {
+		return "", false
+	}
+	return strings.Join(name.Value, "."), true
+}
+
+// walkNodes calls f with every node reachable from n, including nodes held
+// in fields of concrete node types such as *ast.TypeRef, and the location
+// of the node or, for nodes without one, of the closest enclosing node.
+func walkNodes(n ast.Node, f func(n ast.Node, loc *ast.Location)) {
+	nodeType := reflect.TypeOf((*ast.Node)(nil)).Elem()
+	var walk func(v reflect.Value, loc *ast.Location)
+	walk = func(v reflect.Value, loc *ast.Location) {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Ptr:
+			if v.IsNil() {
+				return
+			}
+			if v.Kind() == reflect.Ptr && v.Type().Implements(nodeType) {
+				n := v.Interface().(ast.Node)
+				if l := nodeLocation(n); l != nil {
+					loc = l
+				}
+				f(n, loc)
+			}
+			walk(v.Elem(), loc)
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				walk(v.Index(i), loc)
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).Name != "Parent" && v.Type().Field(i).IsExported() {
+					walk(v.Field(i), loc)
+				}
+			}
+		}
+	}
+	walk(reflect.ValueOf(n), nil)
+}
+
+// firstLocation returns the location of the first node under v that has
+// one, for nodes the AST builder does not locate, such as properties.
+func firstLocation(v reflect.Value) *ast.Location {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if n, ok := v.Interface().(ast.Node); ok && v.Kind() == reflect.Ptr {
+			if l := n.GetLocation(); l != nil {
+				return l
+			}
+		}
+		return firstLocation(v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if l := firstLocation(v.Index(i)); l != nil {
+				return l
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Name != "Parent" && v.Type().Field(i).IsExported() {
+				if l := firstLocation(v.Field(i)); l != nil {
+					return l
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// constructCounts aggregates the findings of the files by construct, most
+// frequent first.
+func constructCounts(files []*FileReport) []*ConstructCount {
+	counts := map[[2]string]*ConstructCount{}
+	for _, file := range files {
+		seen := map[[2]string]bool{}
+		for _, f := range file.Findings {
+			key := [2]string{f.Kind, f.Name}
+			c, ok := counts[key]
+			if !ok {
+				c = &ConstructCount{Kind: f.Kind, Name: f.Name}
+				counts[key] = c
+			}
+			c.Count++
+			if !seen[key] {
+				seen[key] = true
+				c.Files++
+			}
+		}
+	}
+	constructs := []*ConstructCount{}
+	for _, c := range counts {
+		constructs = append(constructs, c)
+	}
+	sort.Slice(constructs, func(i, j int) bool {
+		a, b := constructs[i], constructs[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return constructs
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func (r *Report) WriteHTML(w io.Writer) error {
+	return reportTemplate.Execute(w, r)
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>apex2java report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+th { background: #f4f4f4; }
+.clean { color: #2a7a2a; }
+</style>
+</head>
+<body>
+<h1>apex2java report</h1>
+<p>{{len .Files}} files, {{.CleanFiles}} convert without findings.</p>
+<table>
+<tr><th>Finding</th><th>Count</th></tr>
+{{- range $kind, $count := .Totals}}
+<tr><td>{{$kind}}</td><td>{{$count}}</td></tr>
+{{- end}}
+</table>
+<h2>Constructs</h2>
+<table>
+<tr><th>Finding</th><th>Construct</th><th>Count</th><th>Files</th></tr>
+{{- range .Constructs}}
+<tr><td>{{.Kind}}</td><td>{{.Name}}</td><td>{{.Count}}</td><td>{{.Files}}</td></tr>
+{{- end}}
+</table>
+<h2>Files</h2>
+{{- range .Files}}
+<h3>{{.File}}</h3>
+{{- if .Findings}}
+<table>
+<tr><th>Line</th><th>Column</th><th>Finding</th><th>Construct</th></tr>
+{{- range .Findings}}
+<tr><td>{{.Line}}</td><td>{{.Column}}</td><td>{{.Kind}}</td><td>{{.Name}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p class="clean">No findings.</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
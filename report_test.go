@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var reportSource = `public class Unsupported {
+    public Integer count { get; set; }
+    public static String pick(Boolean b, Blob data) {
+        List<List<SObject>> found = [FIND 'Acme' IN ALL FIELDS RETURNING Account(Id, Name)];
+        Map<String, Integer> m = new Map<String, Integer>{'a' => 1};
+        System.debug(UserInfo.getUserId() + Helper.name());
+        return b ? 'x' : 'y';
+    }
+}
+`
+
+var helperSource = `public class Helper {
+    public static String name() {
+        return String.valueOf(1);
+    }
+}
+`
+
+func TestAnalyze(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apex2java")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := []string{filepath.Join(dir, "Unsupported.cls"), filepath.Join(dir, "Helper.cls")}
+	for i, src := range []string{reportSource, helperSource} {
+		if err := ioutil.WriteFile(files[i], []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report := Analyze(files)
+	expected := []string{
+		"2:4 unsupported property",
+		"3:41 unresolved type Blob",
+		"4:36 unsupported SOSL query",
+		"5:37 unsupported map initializer",
+		"6:21 unmapped method UserInfo.getUserId",
+		"7:15 unsupported ternary expression",
+	}
+	actual := []string{}
+	for _, f := range report.Files[0].Findings {
+		actual = append(actual, fmt.Sprintf("%d:%d %s %s", f.Line, f.Column, f.Kind, f.Name))
+	}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("findings differ\n--- expected\n%v\n--- actual\n%v", expected, actual)
+	}
+	if report.CleanFiles != 1 || len(report.Files[1].Findings) != 0 {
+		t.Errorf("expected %s to have no findings, got %v", files[1], report.Files[1].Findings)
+	}
+	if report.Totals[UnsupportedNode] != 4 {
+		t.Errorf("expected 4 unsupported constructs, got %d", report.Totals[UnsupportedNode])
+	}
+}
+
+// TestAnalyzeGolden checks that the report finds nothing in the golden
+// test cases, which convert cleanly, except for the lenient case, which has
+// unsupported Apex on purpose.
+func TestAnalyzeGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.cls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clean := []string{}
+	for _, file := range files {
+		if filepath.Base(file) != "lenient.cls" {
+			clean = append(clean, file)
+		}
+	}
+	for _, f := range Analyze(clean).Files {
+		for _, finding := range f.Findings {
+			t.Errorf("%s:%d: %s %s", f.File, finding.Line, finding.Kind, finding.Name)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/tzmfreedom/land/ast"
+	"github.com/tzmfreedom/land/parser"
+)
+
+// RunAs is a System.runAs(user) { ... } statement. land's AST builder keeps
+// only its block, so the user is read from the parse tree.
+type RunAs struct {
+	User     ast.Node
+	Block    *ast.Block
+	Location *ast.Location
+	Parent   ast.Node
+}
+
+// RunAsVisitor is implemented by visitors that handle RunAs. Visitors
+// without it skip runAs statements.
+type RunAsVisitor interface {
+	VisitRunAs(n *RunAs) (interface{}, error)
+}
+
+func (n *RunAs) Accept(v ast.Visitor) (interface{}, error) {
+	if rv, ok := v.(RunAsVisitor); ok {
+		return rv.VisitRunAs(n)
+	}
+	return nil, nil
+}
+
+func (n *RunAs) GetChildren() []interface{} {
+	return []interface{}{
+		n.User,
+		n.Block,
+	}
+}
+
+func (n *RunAs) GetType() string {
+	return "RunAs"
+}
+
+func (n *RunAs) GetParent() ast.Node {
+	return n.Parent
+}
+
+func (n *RunAs) SetParent(parent ast.Node) {
+	n.Parent = parent
+}
+
+func (n *RunAs) GetLocation() *ast.Location {
+	return n.Location
+}
+
+// readRunAs reads the users of the runAs statements in the parse tree,
+// keyed by the location of their block.
+func readRunAs(tree antlr.Tree, src string) map[ast.Location]*RunAs {
+	statements := map[ast.Location]*RunAs{}
+	builder := &ast.Builder{Source: src}
+	var walk func(t antlr.Tree)
+	walk = func(t antlr.Tree) {
+		if s, ok := t.(*parser.StatementContext); ok && s.RUNAS() != nil {
+			n := &RunAs{
+				User:     s.Expression().Accept(builder).(ast.Node),
+				Location: newLocation(s, src),
+			}
+			n.User.SetParent(n)
+			statements[*newLocation(s.Block().(*parser.BlockContext), src)] = n
+		}
+		for _, child := range t.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(tree)
+	return statements
+}
+
+// replaceRunAs replaces the blocks of the runAs statements in n with the
+// statements returned by readRunAs.
+func replaceRunAs(n ast.Node, statements map[ast.Location]*RunAs) {
+	replaceNodes(reflect.ValueOf(n), func(n ast.Node) ast.Node {
+		if block, ok := n.(*ast.Block); ok {
+			if r, ok := statements[*block.Location]; ok {
+				r.Block = block
+				r.Parent = block.Parent
+				block.Parent = r
+				return r
+			}
+		}
+		return n
+	})
+}
+
+// VisitRunAs emits the block in a try statement that ends the runAs
+// context, rather than as a lambda, so that the block can assign local
+// variables and return like the Apex block does.
+func (v *Generator) VisitRunAs(n *RunAs) (interface{}, error) {
+	user, err := n.User.Accept(v)
+	if err != nil {
+		return nil, err
+	}
+	try := newCompound("try", v.blockBody(n.Block))
+	try.Clauses = append(try.Clauses, &javaClause{Header: "finally", Body: newBlock("System.endRunAs()")})
+	return javaNodes{&javaStatement{Code: fmt.Sprintf("System.runAs(%s)", user.(string))}, try}, nil
+}
+
+func (v *ImportTypeResolver) VisitRunAs(n *RunAs) (interface{}, error) {
+	v.importClasses[ImportClasses["system"]] = struct{}{}
+	if _, err := n.User.Accept(v); err != nil {
+		return nil, err
+	}
+	return n.Block.Accept(v)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var runtimeVersionPattern = regexp.MustCompile(`VERSION = "([^"]*)"`)
+
+// runtimeVersion returns the version of the embedded runtime.
+func runtimeVersion() string {
+	src, err := runtimeSources.ReadFile("com/freedom_man/system/RuntimeVersion.java")
+	if err != nil {
+		panic(err)
+	}
+	m := runtimeVersionPattern.FindSubmatch(src)
+	if m == nil {
+		panic("RuntimeVersion.java has no VERSION")
+	}
+	return string(m[1])
+}
+
+// runtimeVersionCheck returns the static initializer of a top level type,
+// which fails loading the type on a runtime of another version.
+func (v *Generator) runtimeVersionCheck() javaNode {
+	return &javaInitializer{
+		Static: true,
+		Body:   newBlock(fmt.Sprintf("RuntimeVersion.check(%s)", javaString(v.RuntimeVersion))),
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lookJDK returns the path of the JDK tool name. Tests needing it are
+// skipped without a JDK, except on CI, which must run them.
+func lookJDK(t *testing.T, name string) string {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		if os.Getenv("CI") != "" {
+			t.Fatalf("%s not found: CI requires a JDK", name)
+		}
+		t.Skipf("%s not found", name)
+	}
+	return path
+}
+
+// TestRuntimeJava compiles the runtime with the tests of testdata/runtime and
+// the converted tests among the golden files, and runs them on the JUnit
+// platform, which JUNIT_CLASSPATH must point to with its console launcher,
+// such as junit-platform-console-standalone.jar. Without it the test is
+// skipped, except on CI.
+func TestRuntimeJava(t *testing.T) {
+	javac := lookJDK(t, "javac")
+	java := lookJDK(t, "java")
+	junit := os.Getenv("JUNIT_CLASSPATH")
+	if junit == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("JUNIT_CLASSPATH is not set: CI requires the JUnit platform")
+		}
+		t.Skip("JUNIT_CLASSPATH is not set")
+	}
+	dir, err := ioutil.TempDir("", "apex2java")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	runtime, err := filepath.Glob(filepath.Join("com", "freedom_man", "system", "*.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests, err := filepath.Glob(filepath.Join("testdata", "runtime", "com", "freedom_man", "system", "*.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := append(runtime, tests...)
+	for _, c := range convertGoldenCases(t) {
+		if !strings.Contains(c.actual, "org.junit") {
+			continue
+		}
+		file := filepath.Join(dir, className(c.node)+".java")
+		if err := ioutil.WriteFile(file, []byte(c.actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, file)
+	}
+	classes := filepath.Join(dir, "classes")
+	out, err := exec.Command(javac, append([]string{"-d", classes, "-cp", junit}, sources...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("javac failed: %s\n%s", err, out)
+	}
+
+	out, err = exec.Command(
+		java,
+		"-cp", classes+string(filepath.ListSeparator)+junit,
+		"org.junit.platform.console.ConsoleLauncher", "execute",
+		"--scan-class-path", "--include-classname", ".*",
+		"--fail-if-no-tests", "--disable-banner", "--details", "tree",
+	).CombinedOutput()
+	if err != nil {
+		t.Fatalf("runtime tests failed: %s\n%s", err, out)
+	}
+	t.Logf("%s", out)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// schemaToken returns the expression a name starting with an sObject token
+// is emitted as, with the rest of the name after it:
+//
+//	Account.SObjectType              Schema.getSObjectType(Account.class)
+//	Account.Name                     Schema.getSObjectField(Account.class, "Name")
+//	Schema.SObjectType.Account       Schema.getSObjectType(Account.class).getDescribe()
+//	Schema.SObjectType.Account.fields.Name
+//	                                 Schema.getSObjectField(Account.class, "Name").getDescribe()
+//
+// Names starting with a variable are not tokens, as in Apex.
+func (v *Generator) schemaToken(name []string) (string, bool) {
+	if len(name) < 2 {
+		return "", false
+	}
+	if _, ok := v.env.Get(name[0]); ok {
+		return "", false
+	}
+	var sobject *SObjectMeta
+	var expr string
+	var rest []string
+	if strings.EqualFold(name[0], "schema") {
+		if len(name) < 3 || !strings.EqualFold(name[1], "sobjecttype") {
+			return "", false
+		}
+		if sobject = findSObject(name[2]); sobject == nil {
+			return "", false
+		}
+		expr = fmt.Sprintf("Schema.getSObjectType(%s.class).getDescribe()", sobject.Name)
+		rest = name[3:]
+		if len(rest) >= 2 && strings.EqualFold(rest[0], "fields") {
+			if f := sobject.findField(rest[1]); f != nil {
+				expr = fmt.Sprintf("Schema.getSObjectField(%s.class, %s).getDescribe()", sobject.Name, javaString(f.Name))
+				rest = rest[2:]
+			}
+		}
+	} else {
+		if sobject = findSObject(name[0]); sobject == nil {
+			return "", false
+		}
+		if strings.EqualFold(name[1], "sobjecttype") {
+			expr = fmt.Sprintf("Schema.getSObjectType(%s.class)", sobject.Name)
+		} else if f := sobject.findField(name[1]); f != nil {
+			expr = fmt.Sprintf("Schema.getSObjectField(%s.class, %s)", sobject.Name, javaString(f.Name))
+		} else {
+			return "", false
+		}
+		rest = name[2:]
+	}
+	v.importClass("schema")
+	v.importClass(strings.ToLower(sobject.Name))
+	return strings.Join(append([]string{expr}, rest...), "."), true
+}
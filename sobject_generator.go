@@ -2,69 +2,265 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 )
 
+// SObjectGenerator generates the runtime's SObject classes and the describe
+// metadata of its Schema API from the sObject metadata.
 type SObjectGenerator struct{}
 
 type SObjectMeta struct {
-	Name   string
-	Fields []*Field
+	Name        string
+	Label       string
+	LabelPlural string
+	KeyPrefix   string
+	Fields      []*Field
+	RecordTypes []*RecordType
 }
 
+// Field is a field of an sObject. Type is the Schema.DisplayType of the
+// field.
 type Field struct {
-	Name    string
-	Type    string
-	Default string
+	Name           string
+	Label          string
+	Type           string
+	Length         int
+	Nillable       bool
+	Default        string
+	PicklistValues []string
+}
+
+type RecordType struct {
+	Name          string
+	DeveloperName string
+	Id            string
+	Default       bool
+}
+
+// masterRecordType is the record type of sObjects without others.
+var masterRecordType = &RecordType{Name: "Master", DeveloperName: "Master", Id: "012000000000000AAA", Default: true}
+
+// systemFields are the fields every sObject has, which SObject declares.
+var systemFields = []*Field{
+	{Name: "CreatedDate", Label: "Created Date", Type: "DATETIME"},
+	{Name: "LastModifiedDate", Label: "Last Modified Date", Type: "DATETIME"},
+}
+
+// javaFieldTypes maps display types to the Java types of the fields.
+var javaFieldTypes = map[string]string{
+	"BOOLEAN":   "Boolean",
+	"DATE":      "Date",
+	"DATETIME":  "Datetime",
+	"EMAIL":     "String",
+	"ID":        "String",
+	"INTEGER":   "Integer",
+	"PHONE":     "String",
+	"PICKLIST":  "String",
+	"REFERENCE": "String",
+	"STRING":    "String",
+	"TEXTAREA":  "String",
+	"TIME":      "Time",
+	"URL":       "String",
 }
 
 func (m *SObjectMeta) GetFileName() string {
-	return m.Name
+	return m.Name + ".java"
 }
 
 func parseMetadata() []*SObjectMeta {
 	sobjects := []*SObjectMeta{
 		{
-			Name: "Account",
+			Name:        "Account",
+			Label:       "Account",
+			LabelPlural: "Accounts",
+			KeyPrefix:   "001",
 			Fields: []*Field{
-				{
-					Name:    "Name",
-					Type:    "String",
-					Default: "hoge",
-				},
+				{Name: "Name", Label: "Account Name", Type: "STRING", Length: 255},
+				{Name: "Industry", Label: "Industry", Type: "PICKLIST", Length: 255, Nillable: true, PicklistValues: []string{
+					"Agriculture", "Banking", "Education", "Technology",
+				}},
+			},
+		},
+		{
+			Name:        "User",
+			Label:       "User",
+			LabelPlural: "Users",
+			KeyPrefix:   "005",
+			Fields: []*Field{
+				{Name: "Username", Label: "Username", Type: "STRING", Length: 80},
+				{Name: "LastName", Label: "Last Name", Type: "STRING", Length: 80},
+				{Name: "FirstName", Label: "First Name", Type: "STRING", Length: 40, Nillable: true},
+				{Name: "Alias", Label: "Alias", Type: "STRING", Length: 8},
+				{Name: "Email", Label: "Email", Type: "EMAIL", Length: 128},
+				{Name: "ProfileId", Label: "Profile ID", Type: "REFERENCE", Length: 18},
+				{Name: "TimeZoneSidKey", Label: "Time Zone", Type: "PICKLIST", Length: 40},
+				{Name: "LocaleSidKey", Label: "Locale", Type: "PICKLIST", Length: 40},
+				{Name: "LanguageLocaleKey", Label: "Language", Type: "PICKLIST", Length: 40},
+				{Name: "EmailEncodingKey", Label: "Email Encoding", Type: "PICKLIST", Length: 40},
+			},
+		},
+		{
+			Name:        "AsyncApexJob",
+			Label:       "Apex Job",
+			LabelPlural: "Apex Jobs",
+			KeyPrefix:   "707",
+			Fields: []*Field{
+				{Name: "JobType", Label: "Job Type", Type: "PICKLIST", Length: 40, Nillable: true, PicklistValues: []string{
+					"Future", "ScheduledApex", "BatchApex", "BatchApexWorker", "Queueable",
+				}},
+				{Name: "MethodName", Label: "Apex Method", Type: "STRING", Length: 255, Nillable: true},
+				{Name: "Status", Label: "Status", Type: "PICKLIST", Length: 40, PicklistValues: []string{
+					"Queued", "Preparing", "Processing", "Aborted", "Completed", "Failed", "Holding",
+				}},
+				{Name: "ExtendedStatus", Label: "Status Detail", Type: "STRING", Length: 255, Nillable: true},
+				{Name: "JobItemsProcessed", Label: "Batches Processed", Type: "INTEGER"},
+				{Name: "TotalJobItems", Label: "Total Batches", Type: "INTEGER", Nillable: true},
+				{Name: "NumberOfErrors", Label: "Failures", Type: "INTEGER", Nillable: true},
+				{Name: "CompletedDate", Label: "Completion Date", Type: "DATETIME", Nillable: true},
+			},
+		},
+		{
+			Name:        "CronTrigger",
+			Label:       "Scheduled Jobs",
+			LabelPlural: "Scheduled Jobs",
+			KeyPrefix:   "08e",
+			Fields: []*Field{
+				{Name: "CronExpression", Label: "Cron Expression", Type: "STRING", Length: 255, Nillable: true},
+				{Name: "State", Label: "State", Type: "PICKLIST", Length: 40, Nillable: true, PicklistValues: []string{
+					"WAITING", "ACQUIRED", "EXECUTING", "COMPLETE", "ERROR", "DELETED", "PAUSED", "BLOCKED", "PAUSED_BLOCKED",
+				}},
+				{Name: "TimesTriggered", Label: "Times Triggered", Type: "INTEGER", Nillable: true},
+				{Name: "StartTime", Label: "Start Time", Type: "DATETIME", Nillable: true},
+				{Name: "NextFireTime", Label: "Next Fire Time", Type: "DATETIME", Nillable: true},
+				{Name: "PreviousFireTime", Label: "Previous Fire Time", Type: "DATETIME", Nillable: true},
 			},
 		},
 	}
 	return sobjects
 }
 
-func (g *SObjectGenerator) generate() error {
-	sobjects := parseMetadata()
-	for _, sobject := range sobjects {
-		err := g.generateSObjectFile(sobject)
-		if err != nil {
-			return err
+// sobjectMetadata is the metadata findSObject looks sObjects up in, parsed
+// once.
+var sobjectMetadata = parseMetadata()
+
+// findSObject returns the metadata of the sObject named name, ignoring
+// case, or nil.
+func findSObject(name string) *SObjectMeta {
+	for _, sobject := range sobjectMetadata {
+		if strings.EqualFold(sobject.Name, name) {
+			return sobject
+		}
+	}
+	return nil
+}
+
+// findField returns the field of m named name, ignoring case, or nil.
+func (m *SObjectMeta) findField(name string) *Field {
+	for _, f := range append([]*Field{{Name: "Id"}}, append(m.Fields, systemFields...)...) {
+		if strings.EqualFold(f.Name, name) {
+			return f
 		}
 	}
 	return nil
 }
 
-func (g *SObjectGenerator) generateSObjectFile(meta *SObjectMeta) error {
-	fields := make([]string, len(meta.Fields))
-	for i, f := range meta.Fields {
-		if f.Type == "String" {
-			fields[i] = fmt.Sprintf("public %s %s = \"%s\";", f.Type, f.Name, f.Default)
-		} else {
-			fields[i] = fmt.Sprintf("public %s %s = %s;", f.Type, f.Name, f.Default)
+// generate writes the sources to the runtime package under dir.
+func (g *SObjectGenerator) generate(dir string) error {
+	for name, src := range g.sources() {
+		if err := writeFile(filepath.Join(dir, "com", "freedom_man", "system", name), src); err != nil {
+			return err
 		}
 	}
-	body := fmt.Sprintf(`public class %s {
-%s
-}`, meta.Name, strings.Join(fields, "\n"))
-	fmt.Println(body)
-	//err := ioutil.WriteFile(meta.GetFileName(), []byte(body), 0777)
-	//if err != nil {
-	//	return err
-	//}
 	return nil
 }
+
+// sources returns the generated runtime sources keyed by file name.
+func (g *SObjectGenerator) sources() map[string]string {
+	sobjects := parseMetadata()
+	sources := map[string]string{
+		"SchemaMetadata.java": g.schemaMetadataSource(sobjects),
+	}
+	for _, sobject := range sobjects {
+		sources[sobject.GetFileName()] = g.sobjectSource(sobject)
+	}
+	return sources
+}
+
+const generatedHeader = "// Code generated by apex2java from sobject_generator.go. DO NOT EDIT.\n"
+
+// sobjectSource returns the class of an sObject: its typed fields and the
+// overloads of clone returning the class.
+func (g *SObjectGenerator) sobjectSource(meta *SObjectMeta) string {
+	var b strings.Builder
+	b.WriteString(generatedHeader)
+	fmt.Fprintf(&b, "\npackage com.freedom_man.system;\n\npublic class %s extends SObject {\n", meta.Name)
+	for _, f := range meta.Fields {
+		fmt.Fprintf(&b, "    public %s %s;\n", javaFieldTypes[f.Type], f.Name)
+	}
+	params := []string{"Boolean preserveId", "Boolean isDeepClone", "Boolean preserveReadonlyTimestamps", "Boolean preserveAutonumber"}
+	for i := 0; i <= len(params); i++ {
+		args := make([]string, i)
+		for j, p := range params[:i] {
+			args[j] = strings.Fields(p)[1]
+		}
+		fmt.Fprintf(&b, `
+    @Override
+    public %s clone(%s) {
+        return (%s) super.clone(%s);
+    }
+`, meta.Name, strings.Join(params[:i], ", "), meta.Name, strings.Join(args, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// schemaMetadataSource returns SchemaMetadata, which defines the describes
+// of the sObjects for Schema.
+func (g *SObjectGenerator) schemaMetadataSource(sobjects []*SObjectMeta) string {
+	var b strings.Builder
+	b.WriteString(generatedHeader)
+	b.WriteString(`
+package com.freedom_man.system;
+
+final class SchemaMetadata {
+    private SchemaMetadata() {
+    }
+
+    static void load() {
+`)
+	for i, sobject := range sobjects {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "        Schema.define(%s.class, %s, %s, %s)", sobject.Name, javaString(sobject.Label), javaString(sobject.LabelPlural), javaString(sobject.KeyPrefix))
+		id := &Field{Name: "Id", Label: sobject.Label + " ID", Type: "ID", Length: 18}
+		for _, f := range append([]*Field{id}, append(sobject.Fields, systemFields...)...) {
+			args := []string{
+				javaString(f.Name),
+				javaString(f.Label),
+				"Schema.DisplayType." + f.Type,
+				fmt.Sprint(f.Length),
+				fmt.Sprint(f.Nillable),
+				"null",
+			}
+			if f.Default != "" {
+				args[5] = javaString(f.Default)
+			}
+			for _, v := range f.PicklistValues {
+				args = append(args, javaString(v))
+			}
+			fmt.Fprintf(&b, "\n                .field(%s)", strings.Join(args, ", "))
+		}
+		recordTypes := sobject.RecordTypes
+		if len(recordTypes) == 0 {
+			recordTypes = []*RecordType{masterRecordType}
+		}
+		for _, r := range recordTypes {
+			fmt.Fprintf(&b, "\n                .recordType(%s, %s, %s, %t)", javaString(r.Name), javaString(r.DeveloperName), javaString(r.Id), r.Default)
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("    }\n}\n")
+	return b.String()
+}
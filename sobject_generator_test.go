@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// TestSObjectSources checks that the runtime's SObject classes and
+// SchemaMetadata are generated from the current metadata. go test -update
+// regenerates them.
+func TestSObjectSources(t *testing.T) {
+	g := &SObjectGenerator{}
+	if *update {
+		if err := g.generate("."); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	for name, src := range g.sources() {
+		file := filepath.Join("com", "freedom_man", "system", name)
+		actual, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != src {
+			t.Errorf("%s is not generated from the sObject metadata (run go test -update)", file)
+		}
+	}
+}
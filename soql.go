@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/tzmfreedom/land/ast"
+	"github.com/tzmfreedom/land/parser"
+)
+
+// Query is an Apex SOQL query. land's AST builder reads only part of SOQL
+// and fails on parenthesized conditions, so queries are read from the parse
+// tree as text and replace the Soql nodes the builder makes. The runtime
+// evaluates the text.
+type Query struct {
+	// Object is the queried SObject type.
+	Object string
+	// Text is the query with its bind variables written as ?.
+	Text string
+	// Binds are the bind variable expressions, in the order of the ?.
+	Binds    []ast.Node
+	Location *ast.Location
+	Parent   ast.Node
+}
+
+// QueryVisitor is implemented by visitors that handle Query. Visitors
+// without it skip queries.
+type QueryVisitor interface {
+	VisitQuery(n *Query) (interface{}, error)
+}
+
+func (n *Query) Accept(v ast.Visitor) (interface{}, error) {
+	if qv, ok := v.(QueryVisitor); ok {
+		return qv.VisitQuery(n)
+	}
+	return nil, nil
+}
+
+func (n *Query) GetChildren() []interface{} {
+	return []interface{}{
+		n.Object,
+		n.Text,
+		n.Binds,
+	}
+}
+
+func (n *Query) GetType() string {
+	return "Query"
+}
+
+func (n *Query) GetParent() ast.Node {
+	return n.Parent
+}
+
+func (n *Query) SetParent(parent ast.Node) {
+	n.Parent = parent
+}
+
+func (n *Query) GetLocation() *ast.Location {
+	return n.Location
+}
+
+// readQueries reads the SOQL queries in the parse tree, keyed by location,
+// and hides their clauses after FROM from the AST builder. code is the
+// source the tree was parsed from.
+func readQueries(tree antlr.Tree, code, src string) map[ast.Location]*Query {
+	queries := map[ast.Location]*Query{}
+	runes := []rune(code)
+	builder := &ast.Builder{Source: src}
+	var walk func(t antlr.Tree)
+	walk = func(t antlr.Tree) {
+		if l, ok := t.(*parser.SoqlLiteralContext); ok {
+			q := l.Query().(*parser.QueryContext)
+			queries[*newLocation(q, src)] = newQuery(q, runes, builder, src)
+		}
+		for _, child := range t.GetChildren() {
+			walk(child)
+		}
+		if q, ok := t.(*parser.QueryContext); ok {
+			hideQueryClauses(q)
+		}
+	}
+	walk(tree)
+	return queries
+}
+
+func newQuery(ctx *parser.QueryContext, code []rune, builder *ast.Builder, src string) *Query {
+	n := &Query{
+		Object:   ctx.FromClause().(*parser.FromClauseContext).ApexIdentifier().GetText(),
+		Binds:    []ast.Node{},
+		Location: newLocation(ctx, src),
+	}
+	text := ""
+	pos := ctx.GetStart().GetStart()
+	var walk func(t antlr.Tree)
+	walk = func(t antlr.Tree) {
+		if b, ok := t.(*parser.BindVariableContext); ok {
+			text += string(code[pos:b.GetStart().GetStart()]) + "?"
+			pos = b.GetStop().GetStop() + 1
+			bind := b.Expression().Accept(builder).(ast.Node)
+			bind.SetParent(n)
+			n.Binds = append(n.Binds, bind)
+			return
+		}
+		for _, child := range t.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(ctx)
+	text += string(code[pos : ctx.GetStop().GetStop()+1])
+	n.Text = collapseSpaces(text)
+	return n
+}
+
+var (
+	queryPattern       = regexp.MustCompile(`(?i)^\[\s*select\b`)
+	countPattern       = regexp.MustCompile(`(?i)^SELECT COUNT\(\s*\) FROM\b`)
+	dateLiteralPattern = regexp.MustCompile(`(?i)(?:=|<|>)\s*(\d{4}-\d{2}-\d{2}(?:T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2}))?|(?:today|yesterday|tomorrow|(?:this|last|next)_(?:week|month|year))\b)`)
+)
+
+// rewriteDateLiterals replaces the SOQL date literals the parser does not
+// know, such as TODAY and 2024-01-02, with string literals of the same
+// length. Queries are read from the source before the rewrite, so they
+// keep the date literals.
+func rewriteDateLiterals(src string) string {
+	blanked := blankNonCode(src)
+	b := []byte(src)
+	for _, q := range bracketQueries(blanked, queryPattern) {
+		for _, m := range dateLiteralPattern.FindAllStringSubmatchIndex(blanked[q[0]:q[1]], -1) {
+			start, end := q[0]+m[2], q[0]+m[3]
+			copy(b[start:end], "'"+strings.Repeat(" ", end-start-2)+"'")
+		}
+	}
+	return string(b)
+}
+
+// hideQueryClauses replaces the clauses of a query the AST builder cannot
+// read with plain tokens, which it skips.
+func hideQueryClauses(ctx *parser.QueryContext) {
+	children := ctx.GetChildren()
+	for i, child := range children {
+		switch c := child.(type) {
+		case *parser.SelectClauseContext, *parser.FromClauseContext:
+			continue
+		case antlr.ParserRuleContext:
+			children[i] = antlr.NewTerminalNodeImpl(c.GetStart())
+		}
+	}
+}
+
+// collapseSpaces replaces the runs of white space outside string literals
+// in a query with a single space.
+func collapseSpaces(text string) string {
+	var b strings.Builder
+	quoted, space := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '\r') {
+			space = true
+			continue
+		}
+		if space && b.Len() != 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteByte(c)
+		switch {
+		case quoted && c == '\\' && i+1 < len(text):
+			i++
+			b.WriteByte(text[i])
+		case c == '\'':
+			quoted = !quoted
+		}
+	}
+	return b.String()
+}
+
+// replaceQueries replaces the Soql nodes in n with the queries returned by
+// readQueries.
+func replaceQueries(n ast.Node, queries map[ast.Location]*Query) {
+	replaceNodes(reflect.ValueOf(n), func(n ast.Node) ast.Node {
+		if soql, ok := n.(*ast.Soql); ok {
+			if q, ok := queries[*soql.Location]; ok {
+				q.Parent = soql.Parent
+				return q
+			}
+		}
+		return n
+	})
+}
+
+// replaceNodes walks the nodes reachable from the node v and replaces those
+// held in fields or slices of type ast.Node with the result of f, then walks
+// the replacements.
+func replaceNodes(v reflect.Value, f func(ast.Node) ast.Node) {
+	nodeType := reflect.TypeOf((*ast.Node)(nil)).Elem()
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Interface:
+			if v.IsNil() {
+				return
+			}
+			if v.Type() == nodeType && v.CanSet() {
+				if r := f(v.Interface().(ast.Node)); r != v.Interface() {
+					v.Set(reflect.ValueOf(r))
+				}
+			}
+			walk(v.Elem())
+		case reflect.Ptr:
+			if !v.IsNil() {
+				walk(v.Elem())
+			}
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				walk(v.Index(i))
+			}
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if v.Type().Field(i).Name != "Parent" && v.Type().Field(i).IsExported() {
+					walk(v.Field(i))
+				}
+			}
+		}
+	}
+	walk(v)
+}
+
+// javaString returns s as a Java string literal.
+func javaString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func (v *Generator) VisitQuery(n *Query) (interface{}, error) {
+	args := []string{javaString(n.Text)}
+	for _, b := range n.Binds {
+		r, err := b.Accept(v)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, r.(string))
+	}
+	// A query assigned to a record returns its only row, and a COUNT()
+	// query assigned to an Integer the number of rows.
+	t := v.targets[n]
+	count := countPattern.MatchString(n.Text)
+	switch {
+	case count && typeName(t) == "integer":
+		return fmt.Sprintf("Database.countQuery(%s)", strings.Join(args, ", ")), nil
+	case count:
+		return v.unsupportedConstruct(n, "COUNT() query not assigned to an Integer")
+	case t == nil || typeName(t) == "list":
+		return fmt.Sprintf("Database.<%s>query(%s)", n.Object, strings.Join(args, ", ")), nil
+	case typeName(t) == "sobject" || strings.EqualFold(typeName(t), n.Object):
+		return fmt.Sprintf("Database.<%s>queryRow(%s)", n.Object, strings.Join(args, ", ")), nil
+	}
+	return v.unsupportedConstruct(n, "query assigned to "+strings.Join(t.Name, "."))
+}
+
+func (v *ImportTypeResolver) VisitQuery(n *Query) (interface{}, error) {
+	if packageName, ok := ImportClasses[strings.ToLower(n.Object)]; ok {
+		v.importClasses[packageName] = struct{}{}
+	}
+	v.importClasses[ImportClasses["database"]] = struct{}{}
+	for _, b := range n.Binds {
+		if _, err := b.Accept(v); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// queryBinds returns the argument of a dynamic query passing the local
+// variables and parameters in scope by name, which Database.query looks up
+// the :name bind variables of the query in at run time, since Java code
+// cannot look up variables by name. It reports false if there are none.
+// Binds of fields are not supported.
+func (v *Generator) queryBinds() (string, bool) {
+	args := []string{}
+	for _, name := range v.env.Locals() {
+		args = append(args, javaString(name), name)
+	}
+	if len(args) == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("Database.binds(%s)", strings.Join(args, ", ")), true
+}
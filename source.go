@@ -0,0 +1,226 @@
+package main
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/tzmfreedom/land/ast"
+	"github.com/tzmfreedom/land/parser"
+)
+
+// blankNonCode returns src with the contents of string literals and comments
+// replaced by spaces, keeping newlines, so that byte offsets and line numbers
+// in the result match src.
+func blankNonCode(src string) string {
+	b := []byte(src)
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '\'':
+			for i++; i < len(b) && b[i] != '\'' && b[i] != '\n'; i++ {
+				if b[i] == '\\' && i+1 < len(b) {
+					b[i] = ' '
+					i++
+				}
+				b[i] = ' '
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '/':
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(b)
+			} else {
+				end += i + 4
+			}
+			for ; i < end; i++ {
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+	return string(b)
+}
+
+// sourceLocation returns the location of the byte offset off in src, with
+// the column counted in characters from 0 like the parser does.
+func sourceLocation(src string, off int, fileName string) ast.Location {
+	lineStart := strings.LastIndex(src[:off], "\n") + 1
+	return ast.Location{
+		FileName: fileName,
+		Line:     strings.Count(src[:off], "\n") + 1,
+		Column:   utf8.RuneCountInString(src[lineStart:off]),
+	}
+}
+
+var inheritedSharingPattern = regexp.MustCompile(`(?i)\binherited sharing\b`)
+
+// rewriteInheritedSharing replaces the inherited sharing modifier, which the
+// parser does not know, with a without sharing modifier of the same length,
+// and returns the locations of the replaced modifiers.
+func rewriteInheritedSharing(src, fileName string) (string, map[ast.Location]struct{}) {
+	locations := map[ast.Location]struct{}{}
+	matches := inheritedSharingPattern.FindAllStringIndex(blankNonCode(src), -1)
+	if len(matches) == 0 {
+		return src, locations
+	}
+	b := []byte(src)
+	for _, m := range matches {
+		locations[sourceLocation(src, m[0], fileName)] = struct{}{}
+		copy(b[m[0]:m[1]], "without sharing  ")
+	}
+	return string(b), locations
+}
+
+// restoreInheritedSharing renames the modifiers rewritten by
+// rewriteInheritedSharing back to inherited sharing.
+func restoreInheritedSharing(n ast.Node, locations map[ast.Location]struct{}) {
+	class, ok := n.(*ast.ClassDeclaration)
+	if !ok {
+		return
+	}
+	for _, m := range class.Modifiers {
+		if _, ok := locations[*m.Location]; ok {
+			m.Name = "inherited sharing"
+		}
+	}
+	for _, d := range class.Declarations {
+		restoreInheritedSharing(d, locations)
+	}
+}
+
+var exactInequalityPattern = regexp.MustCompile(`!==`)
+
+// rewriteExactInequalities replaces the !== operators, which the parser does
+// not know, with === operators and returns the locations of the replaced
+// operators.
+func rewriteExactInequalities(src, fileName string) (string, map[ast.Location]struct{}) {
+	locations := map[ast.Location]struct{}{}
+	matches := exactInequalityPattern.FindAllStringIndex(blankNonCode(src), -1)
+	if len(matches) == 0 {
+		return src, locations
+	}
+	b := []byte(src)
+	for _, m := range matches {
+		locations[sourceLocation(src, m[0], fileName)] = struct{}{}
+		b[m[0]] = '='
+	}
+	return string(b), locations
+}
+
+// restoreExactInequalities renames the operators rewritten by
+// rewriteExactInequalities in the parse tree back to !==.
+func restoreExactInequalities(tree antlr.Tree, locations map[ast.Location]struct{}, fileName string) {
+	if len(locations) == 0 {
+		return
+	}
+	var walk func(t antlr.Tree)
+	walk = func(t antlr.Tree) {
+		if e, ok := t.(*parser.OpExpressionContext); ok {
+			op := e.GetOp()
+			location := ast.Location{FileName: fileName, Line: op.GetLine(), Column: op.GetColumn()}
+			if _, ok := locations[location]; ok {
+				op.SetText("!==")
+			}
+		}
+		for _, child := range t.GetChildren() {
+			walk(child)
+		}
+	}
+	walk(tree)
+}
+
+var (
+	searchPattern        = regexp.MustCompile(`(?i)^\[\s*find\b`)
+	returnKeywordPattern = regexp.MustCompile(`(?i)(?:^|[^\w])return$`)
+)
+
+// bracketQueries returns the start and end offsets of the queries in the
+// source src, blanked by blankNonCode, whose text after the opening bracket
+// matches start. Brackets count as queries only where an expression starts,
+// so an array index such as m[select] is not one, and the brackets nested
+// in a query, such as in the bind :ids[0], are part of it.
+func bracketQueries(src string, start *regexp.Regexp) [][]int {
+	matches := [][]int{}
+	for i := 0; i < len(src); i++ {
+		if src[i] != '[' || !startsExpression(src[:i]) || !start.MatchString(src[i:]) {
+			continue
+		}
+		depth := 0
+		for j := i; j < len(src); j++ {
+			switch src[j] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+			}
+			if depth == 0 {
+				matches = append(matches, []int{i, j + 1})
+				i = j
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// startsExpression reports whether an expression can start after before,
+// the source up to a bracket: a bracket after a name, a call or an index
+// is an array index, except after return.
+func startsExpression(before string) bool {
+	before = strings.TrimRight(before, " \t\r\n")
+	if before == "" {
+		return true
+	}
+	switch c := before[len(before)-1]; {
+	case c == ')' || c == ']':
+		return false
+	case c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return returnKeywordPattern.MatchString(before)
+	}
+	return true
+}
+
+// rewriteSearches replaces SOSL queries, on which the AST builder fails,
+// with null literals padded to the same length, and returns the locations
+// of the replaced queries.
+func rewriteSearches(src, fileName string) (string, map[ast.Location]struct{}) {
+	locations := map[ast.Location]struct{}{}
+	matches := bracketQueries(blankNonCode(src), searchPattern)
+	if len(matches) == 0 {
+		return src, locations
+	}
+	b := []byte(src)
+	for _, m := range matches {
+		locations[sourceLocation(src, m[0], fileName)] = struct{}{}
+		for i := m[0]; i < m[1]; i++ {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+		copy(b[m[0]:], "null")
+	}
+	return string(b), locations
+}
+
+// restoreSearches replaces the null literals written by rewriteSearches
+// with Sosl nodes.
+func restoreSearches(n ast.Node, locations map[ast.Location]struct{}) {
+	if len(locations) == 0 {
+		return
+	}
+	replaceNodes(reflect.ValueOf(n), func(n ast.Node) ast.Node {
+		if l, ok := n.(*ast.NullLiteral); ok && l.Location != nil {
+			if _, ok := locations[*l.Location]; ok {
+				return &ast.Sosl{Location: l.Location, Parent: l.Parent}
+			}
+		}
+		return n
+	})
+}
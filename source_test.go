@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBracketQueries(t *testing.T) {
+	cases := []struct {
+		src      string
+		expected []string
+	}{
+		{"x = [SELECT Id FROM Account];", []string{"[SELECT Id FROM Account]"}},
+		{"return [select Id FROM Account];", []string{"[select Id FROM Account]"}},
+		{"f(a, [SELECT Id FROM Account WHERE Id = :ids[0] AND CreatedDate < TODAY]);", []string{"[SELECT Id FROM Account WHERE Id = :ids[0] AND CreatedDate < TODAY]"}},
+		{"x = m[select]; y = values[ select ];", []string{}},
+		{"x = f()[select]; y = xs[0][select];", []string{}},
+	}
+	for _, c := range cases {
+		actual := []string{}
+		for _, m := range bracketQueries(c.src, queryPattern) {
+			actual = append(actual, c.src[m[0]:m[1]])
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: expected %q, got %q", c.src, c.expected, actual)
+		}
+	}
+}
+
+func TestRewriteSearchesSkipsIndexes(t *testing.T) {
+	query := "[FIND 'a' IN ALL FIELDS RETURNING Account]"
+	src := "Integer n = values[find]; List<List<SObject>> found = " + query + ";"
+	actual, locations := rewriteSearches(src, "a.cls")
+	expected := "Integer n = values[find]; List<List<SObject>> found = null" + strings.Repeat(" ", len(query)-len("null")) + ";"
+	if actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	if len(locations) != 1 {
+		t.Errorf("expected 1 search, got %d", len(locations))
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+)
+
+// sourceMapVersion is the version of the source map format, which the
+// runtime's SourceMap reads.
+const sourceMapVersion = 1
+
+// SourceMap maps the lines of a converted Java file to the lines of its
+// Apex source. Lines[i] is the Apex line of Java line i+1, or 0 for lines,
+// such as imports, that come from no Apex line.
+type SourceMap struct {
+	Version int    `json:"version"`
+	File    string `json:"file"`
+	Source  string `json:"source"`
+	Lines   []int  `json:"lines"`
+}
+
+// JSON returns the source map as the JSON the runtime reads.
+func (m *SourceMap) JSON() string {
+	b, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	return string(b) + "\n"
+}
+
+// SourceMap returns the source map of the Java file converted last from the
+// Apex file source.
+func (v *Generator) SourceMap(source string) *SourceMap {
+	return &SourceMap{
+		Version: sourceMapVersion,
+		File:    className(v.root) + ".java",
+		Source:  filepath.Base(source),
+		Lines:   v.lines,
+	}
+}
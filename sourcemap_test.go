@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+var sourceMapSource = `public class Mapped {
+    public Integer twice(Integer n) {
+        Integer m = n;
+
+        if (m == null) {
+            m = 0;
+        }
+        return m * 2;
+    }
+}
+`
+
+func TestSourceMap(t *testing.T) {
+	node := parse(sourceMapSource, "Mapped.cls")
+	generator := &Generator{Source: sourceMapSource}
+	src, err := Convert(node, generator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := generator.SourceMap("classes/Mapped.cls")
+	if m.File != "Mapped.java" || m.Source != "Mapped.cls" {
+		t.Errorf("unexpected files %s and %s", m.File, m.Source)
+	}
+	expected := map[string]int{
+		"public class Mapped": 1,
+		"Integer twice(":      2,
+		"Integer m = n;":      3,
+		"if (m == null)":      5,
+		"m = 0;":              6,
+		"return m * 2;":       8,
+	}
+	lines := strings.Split(strings.TrimSuffix(src, "\n"), "\n")
+	if len(lines) != len(m.Lines) {
+		t.Fatalf("source map has %d lines, source has %d", len(m.Lines), len(lines))
+	}
+	for code, line := range expected {
+		found := false
+		for i, l := range lines {
+			if strings.Contains(l, code) {
+				found = true
+				if m.Lines[i] != line {
+					t.Errorf("%q maps to line %d, expected %d", code, m.Lines[i], line)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%q not generated:\n%s", code, src)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// StringMethod is a method of the Apex String class implemented by the
+// runtime's ApexString, with the Apex type it returns.
+type StringMethod struct {
+	Name    string
+	Returns string
+}
+
+// StringMethods maps the lowercased instance methods of Apex String to
+// ApexString, which takes the receiver as the first argument.
+var StringMethods = map[string]StringMethod{
+	"abbreviate":                    {"abbreviate", "String"},
+	"capitalize":                    {"capitalize", "String"},
+	"center":                        {"center", "String"},
+	"charat":                        {"charAt", "Integer"},
+	"compareto":                     {"compareTo", "Integer"},
+	"contains":                      {"contains", "Boolean"},
+	"containsany":                   {"containsAny", "Boolean"},
+	"containsignorecase":            {"containsIgnoreCase", "Boolean"},
+	"containsnone":                  {"containsNone", "Boolean"},
+	"containsonly":                  {"containsOnly", "Boolean"},
+	"containswhitespace":            {"containsWhitespace", "Boolean"},
+	"countmatches":                  {"countMatches", "Integer"},
+	"deletewhitespace":              {"deleteWhitespace", "String"},
+	"difference":                    {"difference", "String"},
+	"endswith":                      {"endsWith", "Boolean"},
+	"endswithignorecase":            {"endsWithIgnoreCase", "Boolean"},
+	"equals":                        {"equals", "Boolean"},
+	"equalsignorecase":              {"equalsIgnoreCase", "Boolean"},
+	"getchars":                      {"getChars", "List<Integer>"},
+	"hashcode":                      {"hashCode", "Integer"},
+	"indexof":                       {"indexOf", "Integer"},
+	"indexofignorecase":             {"indexOfIgnoreCase", "Integer"},
+	"isalllowercase":                {"isAllLowerCase", "Boolean"},
+	"isalluppercase":                {"isAllUpperCase", "Boolean"},
+	"isalpha":                       {"isAlpha", "Boolean"},
+	"isalphaspace":                  {"isAlphaSpace", "Boolean"},
+	"isalphanumeric":                {"isAlphanumeric", "Boolean"},
+	"isalphanumericspace":           {"isAlphanumericSpace", "Boolean"},
+	"isnumeric":                     {"isNumeric", "Boolean"},
+	"isnumericspace":                {"isNumericSpace", "Boolean"},
+	"iswhitespace":                  {"isWhitespace", "Boolean"},
+	"lastindexof":                   {"lastIndexOf", "Integer"},
+	"lastindexofignorecase":         {"lastIndexOfIgnoreCase", "Integer"},
+	"left":                          {"left", "String"},
+	"leftpad":                       {"leftPad", "String"},
+	"length":                        {"length", "Integer"},
+	"mid":                           {"mid", "String"},
+	"normalizespace":                {"normalizeSpace", "String"},
+	"remove":                        {"remove", "String"},
+	"removeend":                     {"removeEnd", "String"},
+	"removeendignorecase":           {"removeEndIgnoreCase", "String"},
+	"removestart":                   {"removeStart", "String"},
+	"removestartignorecase":         {"removeStartIgnoreCase", "String"},
+	"repeat":                        {"repeat", "String"},
+	"replace":                       {"replace", "String"},
+	"replaceall":                    {"replaceAll", "String"},
+	"replacefirst":                  {"replaceFirst", "String"},
+	"reverse":                       {"reverse", "String"},
+	"right":                         {"right", "String"},
+	"rightpad":                      {"rightPad", "String"},
+	"split":                         {"split", "List<String>"},
+	"splitbycharactertype":          {"splitByCharacterType", "List<String>"},
+	"splitbycharactertypecamelcase": {"splitByCharacterTypeCamelCase", "List<String>"},
+	"startswith":                    {"startsWith", "Boolean"},
+	"startswithignorecase":          {"startsWithIgnoreCase", "Boolean"},
+	"striphtmltags":                 {"stripHtmlTags", "String"},
+	"substring":                     {"substring", "String"},
+	"substringafter":                {"substringAfter", "String"},
+	"substringafterlast":            {"substringAfterLast", "String"},
+	"substringbefore":               {"substringBefore", "String"},
+	"substringbeforelast":           {"substringBeforeLast", "String"},
+	"substringbetween":              {"substringBetween", "String"},
+	"swapcase":                      {"swapCase", "String"},
+	"tolowercase":                   {"toLowerCase", "String"},
+	"touppercase":                   {"toUpperCase", "String"},
+	"trim":                          {"trim", "String"},
+	"uncapitalize":                  {"uncapitalize", "String"},
+}
+
+// StringStaticMethods maps the lowercased static methods of Apex String to
+// ApexString.
+var StringStaticMethods = map[string]StringMethod{
+	"escapesinglequotes": {"escapeSingleQuotes", "String"},
+	"format":             {"format", "String"},
+	"fromchararray":      {"fromCharArray", "String"},
+	"isblank":            {"isBlank", "Boolean"},
+	"isempty":            {"isEmpty", "Boolean"},
+	"isnotblank":         {"isNotBlank", "Boolean"},
+	"isnotempty":         {"isNotEmpty", "Boolean"},
+	"join":               {"join", "String"},
+	"valueof":            {"valueOf", "String"},
+	"valueofgmt":         {"valueOfGmt", "String"},
+}
+
+// stringMethod returns the String method called by n and its receiver,
+// which is nil for static methods. Instance methods are recognized by the
+// inferred type of the receiver.
+func (v *Generator) stringMethod(n *ast.MethodInvocation) (ast.Node, StringMethod, bool) {
+	switch e := n.NameOrExpression.(type) {
+	case *ast.Name:
+		if len(e.Value) < 2 {
+			return nil, StringMethod{}, false
+		}
+		receiver := e.Value[:len(e.Value)-1]
+		method := strings.ToLower(e.Value[len(e.Value)-1])
+		if qualified := apexTypeName(receiver); len(qualified) == 1 && strings.ToLower(qualified[0]) == "string" {
+			m, ok := StringStaticMethods[method]
+			return nil, m, ok
+		}
+		if m, ok := StringMethods[method]; ok && isStringType(v.typeOfName(receiver)) {
+			return &ast.Name{Value: receiver, Location: e.Location, Parent: n}, m, true
+		}
+	case *ast.FieldAccess:
+		if m, ok := StringMethods[strings.ToLower(e.FieldName)]; ok && isStringType(v.typeOf(e.Expression)) {
+			return e.Expression, m, true
+		}
+	}
+	return nil, StringMethod{}, false
+}
+
+// stringMethodType returns the Apex type a String method returns.
+func stringMethodType(m StringMethod) *ast.TypeRef {
+	if strings.HasPrefix(m.Returns, "List<") {
+		return newTypeRef("List", newTypeRef(strings.TrimSuffix(strings.TrimPrefix(m.Returns, "List<"), ">")))
+	}
+	return newTypeRef(m.Returns)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// StaticMethods maps lowercased, qualified Apex static methods to the
+// runtime method they are emitted as. Apex method names are case-insensitive,
+// so calls are emitted with the runtime's spelling; a few are renamed where
+// the Apex name is a Java keyword.
+var StaticMethods = map[string]string{
+	"date.daysinmonth":                "Date.daysInMonth",
+	"date.isleapyear":                 "Date.isLeapYear",
+	"date.newinstance":                "Date.newInstance",
+	"date.parse":                      "Date.parse",
+	"date.today":                      "Date.today",
+	"date.valueof":                    "Date.valueOf",
+	"database.executebatch":           "Database.executeBatch",
+	"database.getquerylocator":        "Database.getQueryLocator",
+	"database.query":                  "Database.query",
+	"database.querywithbinds":         "Database.queryWithBinds",
+	"datetime.newinstance":            "Datetime.newInstance",
+	"datetime.newinstancegmt":         "Datetime.newInstanceGmt",
+	"datetime.now":                    "Datetime.now",
+	"datetime.parse":                  "Datetime.parse",
+	"datetime.valueof":                "Datetime.valueOf",
+	"datetime.valueofgmt":             "Datetime.valueOfGmt",
+	"json.creategenerator":            "JSON.createGenerator",
+	"json.createparser":               "JSON.createParser",
+	"json.deserialize":                "JSON.deserialize",
+	"json.deserializestrict":          "JSON.deserializeStrict",
+	"json.deserializeuntyped":         "JSON.deserializeUntyped",
+	"json.serialize":                  "JSON.serialize",
+	"json.serializepretty":            "JSON.serializePretty",
+	"limits.getqueries":               "Limits.getQueries",
+	"limits.getlimitqueries":          "Limits.getLimitQueries",
+	"limits.getqueryrows":             "Limits.getQueryRows",
+	"limits.getlimitqueryrows":        "Limits.getLimitQueryRows",
+	"limits.getquerylocatorrows":      "Limits.getQueryLocatorRows",
+	"limits.getlimitquerylocatorrows": "Limits.getLimitQueryLocatorRows",
+	"limits.getdmlstatements":         "Limits.getDmlStatements",
+	"limits.getlimitdmlstatements":    "Limits.getLimitDmlStatements",
+	"limits.getdmlrows":               "Limits.getDmlRows",
+	"limits.getlimitdmlrows":          "Limits.getLimitDmlRows",
+	"limits.getcallouts":              "Limits.getCallouts",
+	"limits.getlimitcallouts":         "Limits.getLimitCallouts",
+	"limits.getfuturecalls":           "Limits.getFutureCalls",
+	"limits.getlimitfuturecalls":      "Limits.getLimitFutureCalls",
+	"limits.getqueueablejobs":         "Limits.getQueueableJobs",
+	"limits.getlimitqueueablejobs":    "Limits.getLimitQueueableJobs",
+	"limits.getcputime":               "Limits.getCpuTime",
+	"limits.getlimitcputime":          "Limits.getLimitCpuTime",
+	"limits.getheapsize":              "Limits.getHeapSize",
+	"limits.getlimitheapsize":         "Limits.getLimitHeapSize",
+	"schema.describesobjects":         "Schema.describeSObjects",
+	"schema.getglobaldescribe":        "Schema.getGlobalDescribe",
+	"system.abortjob":                 "System.abortJob",
+	"system.assert":                   "System.assertTrue",
+	"system.assertequals":             "System.assertEquals",
+	"system.assertnotequals":          "System.assertNotEquals",
+	"system.currenttimemillis":        "System.currentTimeMillis",
+	"system.debug":                    "System.debug",
+	"system.enqueuejob":               "System.enqueueJob",
+	"system.isbatch":                  "System.isBatch",
+	"system.isfuture":                 "System.isFuture",
+	"system.isqueueable":              "System.isQueueable",
+	"system.isscheduled":              "System.isScheduled",
+	"system.now":                      "System.now",
+	"system.schedule":                 "System.schedule",
+	"system.today":                    "System.today",
+	"test.starttest":                  "Test.startTest",
+	"test.stoptest":                   "Test.stopTest",
+	"test.isrunningtest":              "Test.isRunningTest",
+	"test.setmock":                    "Test.setMock",
+	"time.newinstance":                "Time.newInstance",
+	"type.forname":                    "Type.forName",
+}
+
+// StaticMethodTypes maps the StaticMethods returning a Date, Datetime or
+// Time to their return type, which comparisons of them depend on.
+var StaticMethodTypes = map[string]string{
+	"date.newinstance":        "Date",
+	"date.parse":              "Date",
+	"date.today":              "Date",
+	"date.valueof":            "Date",
+	"datetime.newinstance":    "Datetime",
+	"datetime.newinstancegmt": "Datetime",
+	"datetime.now":            "Datetime",
+	"datetime.parse":          "Datetime",
+	"datetime.valueof":        "Datetime",
+	"datetime.valueofgmt":     "Datetime",
+	"system.now":              "Datetime",
+	"system.today":            "Date",
+	"time.newinstance":        "Time",
+}
+
+// staticMethod returns the runtime method called by n if it is one of
+// StaticMethods.
+func staticMethod(n *ast.MethodInvocation) (string, bool) {
+	name, ok := n.NameOrExpression.(*ast.Name)
+	if !ok {
+		return "", false
+	}
+	method, ok := StaticMethods[strings.ToLower(strings.Join(name.Value, "."))]
+	return method, ok
+}
+
+// staticMethodType returns the return type of n if it is one of
+// StaticMethodTypes.
+func staticMethodType(n *ast.MethodInvocation) (*ast.TypeRef, bool) {
+	name, ok := n.NameOrExpression.(*ast.Name)
+	if !ok {
+		return nil, false
+	}
+	t, ok := StaticMethodTypes[strings.ToLower(strings.Join(name.Value, "."))]
+	if !ok {
+		return nil, false
+	}
+	return newTypeRef(t), true
+}
+
+// loggingLevel returns the runtime constant for the Apex LoggingLevel n,
+// such as LoggingLevel.Debug, whose names are case-insensitive.
+func loggingLevel(n *ast.Name) (string, bool) {
+	name := apexTypeName(n.Value)
+	if len(name) != 2 || strings.ToLower(name[0]) != "logginglevel" {
+		return "", false
+	}
+	return "LoggingLevel." + strings.ToUpper(name[1]), true
+}
@@ -0,0 +1,46 @@
+import com.freedom_man.system.AsyncApex;
+import com.freedom_man.system.AuraEnabled;
+import com.freedom_man.system.Future;
+import com.freedom_man.system.InvocableMethod;
+import com.freedom_man.system.InvocableVariable;
+import com.freedom_man.system.ReadOnly;
+import com.freedom_man.system.RemoteAction;
+import com.freedom_man.system.TestVisible;
+
+public class Annotated {
+    @TestVisible
+    Integer count;
+    @InvocableVariable(label = "Record Id", required = true)
+    public String recordId;
+
+    @Future(callout = true)
+    public static void sync() {
+        AsyncApex.future("sync", () -> syncFuture());
+    }
+
+    private static void syncFuture() {
+        Integer a = 1;
+    }
+
+    @AuraEnabled(cacheable = true)
+    public static String load() {
+        return "ok";
+    }
+
+    @InvocableMethod(label = "Do It", description = "Runs the job")
+    public static void run() {
+        Integer a = 1;
+    }
+
+    @Deprecated
+    @ReadOnly
+    @RemoteAction
+    public static Integer old() {
+        return 1;
+    }
+
+    // @SuppressWarnings("PMD.AvoidGlobalModifier")
+    public final Integer kept() {
+        return 2;
+    }
+}
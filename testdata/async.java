@@ -0,0 +1,57 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.AsyncApex;
+import com.freedom_man.system.Database;
+import com.freedom_man.system.Future;
+import com.freedom_man.system.List;
+import com.freedom_man.system.Queueable;
+import com.freedom_man.system.QueueableContext;
+import com.freedom_man.system.SObject;
+import com.freedom_man.system.Schedulable;
+import com.freedom_man.system.SchedulableContext;
+import com.freedom_man.system.System;
+
+public class AsyncJobs {
+    public static class Cleanup implements Database.Batchable<Account>, Database.Stateful {
+        public Integer processed = 0;
+
+        public final Database.QueryLocator start(Database.BatchableContext bc) {
+            return Database.getQueryLocator(Database.<Account>query("SELECT Id, Name FROM Account"));
+        }
+
+        public final void execute(Database.BatchableContext bc, List<Account> scope) {
+            processed += scope.size();
+        }
+
+        public final void finish(Database.BatchableContext bc) {
+            System.debug(processed);
+        }
+    }
+
+    public static class Notify implements Queueable {
+        public final void execute(QueueableContext context) {
+            System.debug(context.getJobId());
+        }
+    }
+
+    public static class Nightly implements Schedulable {
+        public final void execute(SchedulableContext context) {
+            Database.executeBatch(new Cleanup(), 50);
+        }
+    }
+
+    @Future
+    public static void send(String message, Integer count) {
+        AsyncApex.future("send", () -> sendFuture(message, count));
+    }
+
+    private static void sendFuture(String message, Integer count) {
+        System.debug(message + count);
+    }
+
+    public static void run() {
+        send("hello", 1);
+        String jobId = System.enqueueJob(new Notify());
+        System.schedule("nightly", "0 0 2 * * ?", new Nightly());
+        Database.executeBatch(new Cleanup());
+    }
+}
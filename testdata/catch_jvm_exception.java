@@ -0,0 +1,21 @@
+import com.freedom_man.system.Exception;
+import com.freedom_man.system.List;
+import com.freedom_man.system.ListException;
+import com.freedom_man.system.NullPointerException;
+
+public class SafeAccess {
+    public static String firstName(List<String> names) {
+        String jvmException = "shadowed";
+        try {
+            try {
+                return names.get(0).toUpperCase();
+            } catch (RuntimeException jvmException2) {
+                throw Exception.translate(jvmException2);
+            }
+        } catch (ListException e) {
+            return "none";
+        } catch (NullPointerException e) {
+            return jvmException;
+        }
+    }
+}
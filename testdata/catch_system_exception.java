@@ -0,0 +1,20 @@
+import com.freedom_man.system.DmlException;
+import com.freedom_man.system.Exception;
+import com.freedom_man.system.QueryException;
+import com.freedom_man.system.System;
+
+public class Importer {
+    public final void run() {
+        try {
+            try {
+                System.debug("start");
+            } catch (RuntimeException jvmException) {
+                throw Exception.translate(jvmException);
+            }
+        } catch (DmlException e) {
+            throw new InvalidInputException("failed", e);
+        } catch (QueryException e) {
+            System.debug(e.getMessage());
+        }
+    }
+}
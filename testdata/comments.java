@@ -0,0 +1,45 @@
+import com.freedom_man.system.AuraEnabled;
+import com.freedom_man.system.Sharing;
+
+/**
+ * Greets people.
+ *
+ * @author someone
+ */
+@Sharing(Sharing.Mode.WITH)
+public class Comments {
+    // the greeting
+    private String greeting = "hi"; // trailing field
+
+    /* block
+       comment */
+    /**
+     * Says hello.
+     *
+     * @param name the name
+     * @return the message
+     */
+    @AuraEnabled
+    public static String hello(String name) {
+        // build it
+        String s = "Hello " + name; // concat
+        if (s != null) { // check
+            /* inside */
+            s += "!";
+            // end of if
+        }
+        // before return
+        return s;
+        // dangling
+    }
+
+    public Comments() {
+    }
+
+    public static class Inner {
+        // inner field
+        Integer x;
+        // end of inner
+    }
+}
+// after
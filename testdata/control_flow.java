@@ -0,0 +1,49 @@
+import com.freedom_man.system.ApexOperator;
+import com.freedom_man.system.DmlException;
+import com.freedom_man.system.Exception;
+
+public class ControlFlow {
+    public final Integer run(Integer a) {
+        if (a > 1) {
+            return 1;
+        }
+        if (ApexOperator.equals(a, 2)) {
+            a = 3;
+        } else if (ApexOperator.equals(a, 4)) {
+            a = 5;
+        } else {
+            a = 6;
+        }
+        try {
+            try {
+                a = 1;
+            } catch (RuntimeException jvmException) {
+                throw Exception.translate(jvmException);
+            }
+        } catch (DmlException e) {
+            a = 2;
+        } catch (Exception e) {
+            a = 3;
+        }
+        try {
+            a = 1;
+        } finally {
+            a = 0;
+        }
+        while (a < 10) {
+            a++;
+        }
+        do {
+            a--;
+        } while (a > 0);
+        for (Integer i = 0; i < 10; i++) {
+            {
+                a = i;
+            }
+        }
+        for (;;) {
+            break;
+        }
+        return a;
+    }
+}
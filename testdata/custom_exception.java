@@ -0,0 +1,19 @@
+import com.freedom_man.system.Exception;
+
+public class InvalidInputException extends Exception {
+    public InvalidInputException(String message) {
+        this.setMessage(message);
+    }
+
+    public InvalidInputException() {
+        super();
+    }
+
+    public InvalidInputException(Exception cause) {
+        super(cause);
+    }
+
+    public InvalidInputException(String message, Exception cause) {
+        super(message, cause);
+    }
+}
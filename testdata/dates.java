@@ -0,0 +1,32 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.ApexOperator;
+import com.freedom_man.system.ApexString;
+import com.freedom_man.system.Database;
+import com.freedom_man.system.Date;
+import com.freedom_man.system.Datetime;
+import com.freedom_man.system.List;
+import com.freedom_man.system.System;
+import com.freedom_man.system.Time;
+
+public class Dates {
+    public static Boolean isOverdue(Date due) {
+        Date today = Date.today();
+        return ApexOperator.compare(due, today) < 0;
+    }
+
+    public static String describe() {
+        Date d = Date.newInstance(2024, 1, 31);
+        Datetime dt = Datetime.newInstanceGmt(2024, 1, 31, 9, 30, 0);
+        Time t = Time.newInstance(9, 30, 0, 0);
+        Datetime local = Datetime.newInstance(d, t);
+        Integer days = d.daysBetween(Date.valueOf("2024-03-01"));
+        if (ApexOperator.compare(Datetime.now(), local) >= 0) {
+            System.debug(days);
+        }
+        return ApexString.valueOf(dt) + " " + ApexString.valueOfGmt(dt) + " " + dt.formatGmt("yyyy-MM-dd");
+    }
+
+    public static List<Account> recent() {
+        return Database.<Account>query("SELECT Id FROM Account WHERE CreatedDate = LAST_N_DAYS:7 AND LastModifiedDate < TODAY AND CreatedDate >= 2024-01-01T00:00:00Z");
+    }
+}
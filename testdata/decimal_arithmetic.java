@@ -0,0 +1,15 @@
+import com.freedom_man.system.ApexOperator;
+import java.math.BigDecimal;
+
+public class Invoice {
+    public final BigDecimal total(BigDecimal price, Integer qty) {
+        BigDecimal t = (BigDecimal) ApexOperator.multiply(price, qty);
+        t = ApexOperator.add(t, new BigDecimal("1.5"));
+        t = ApexOperator.subtract(t, (BigDecimal) ApexOperator.divide(price, 2));
+        t = ApexOperator.increment(t);
+        Integer count = qty * 2;
+        Double ratio = 0.5;
+        ratio = ratio * 1.5;
+        return ApexOperator.negate(t);
+    }
+}
@@ -0,0 +1,17 @@
+import java.math.BigDecimal;
+
+public class Pricing {
+    public BigDecimal rate = new BigDecimal("0.25");
+    public Double ratio = 1.5;
+
+    public final BigDecimal discount(BigDecimal price) {
+        BigDecimal minimum = new BigDecimal("10"), floor = new BigDecimal("-2.5");
+        price = new BigDecimal("99.99");
+        Boolean negative = !isPositive(price);
+        return price;
+    }
+
+    public final Boolean isPositive(BigDecimal price) {
+        return price.intValue() > 0;
+    }
+}
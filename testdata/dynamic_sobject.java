@@ -0,0 +1,33 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.Database;
+import com.freedom_man.system.List;
+import com.freedom_man.system.Map;
+import com.freedom_man.system.SObject;
+import com.freedom_man.system.Schema;
+
+public class DynamicAccess {
+    public static Object read(SObject rec, String fieldName) {
+        Object previous = rec.put(fieldName, "value");
+        Account parent = (Account)rec.getSObject("Account");
+        List<SObject> children = rec.getSObjects("Contacts");
+        Map<String, Object> populated = rec.getPopulatedFieldsAsMap();
+        return rec.get(fieldName);
+    }
+
+    public static List<Account> search(String name, Integer max) {
+        String soql = "SELECT Id, Name FROM Account WHERE Name = :name";
+        soql += " LIMIT :max";
+        return Database.query(soql, Database.binds("name", name, "max", max, "soql", soql));
+    }
+
+    public static List<SObject> run(String query, String ownerId) {
+        String status = null;
+        return Database.query(query, Database.binds("query", query, "ownerId", ownerId, "status", status));
+    }
+
+    public static Account copy(Account acc) {
+        Account c = acc.clone(false, true);
+        c.put(Schema.getSObjectField(Account.class, "Industry"), "Banking");
+        return c;
+    }
+}
@@ -0,0 +1,6 @@
+
+public enum Priority {
+    LOW,
+    MEDIUM,
+    HIGH
+}
@@ -0,0 +1,20 @@
+import com.freedom_man.system.Enums;
+import com.freedom_man.system.Map;
+
+public class Triage {
+    public final Integer levels() {
+        return Enums.values(Priority.class).size();
+    }
+
+    public final Priority parse(String name) {
+        return Enums.valueOf(Priority.class, name);
+    }
+
+    public final Integer total(Map<String, Integer> priority) {
+        Integer sum = 0;
+        for (Integer n : priority.values()) {
+            sum += n;
+        }
+        return sum;
+    }
+}
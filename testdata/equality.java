@@ -0,0 +1,24 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.ApexOperator;
+import com.freedom_man.system.SObject;
+
+public class Equality {
+    public String name;
+
+    public final Boolean compare(String other, Integer n, Boolean flag) {
+        Boolean sameName = ApexOperator.equals(name, other);
+        Boolean notTen = !ApexOperator.equals(n, 10);
+        Boolean sameRef = name == other;
+        Boolean otherRef = name != other;
+        Boolean isTrue = ApexOperator.equals(flag, true);
+        Boolean sortsFirst = ApexOperator.compare(other, "abc") < 0;
+        return name == null;
+    }
+
+    public final Boolean sameRecord(Account a, Account b, String ownerId, SObject rec) {
+        Boolean sameId = ApexOperator.equalsId(a.Id, b.Id);
+        Boolean otherOwner = !ApexOperator.equalsId(ownerId, rec.Id);
+        Boolean sameName = ApexOperator.equals(a.Name, b.Name);
+        return ApexOperator.equals(a, b);
+    }
+}
@@ -0,0 +1,38 @@
+import com.freedom_man.system.Http;
+import com.freedom_man.system.HttpCalloutMock;
+import com.freedom_man.system.HttpRequest;
+import com.freedom_man.system.HttpResponse;
+import com.freedom_man.system.IsTest;
+import com.freedom_man.system.System;
+import com.freedom_man.system.Test;
+import com.freedom_man.system.Type;
+
+@IsTest
+class CalloutTest {
+    private static class StubMock implements HttpCalloutMock {
+        public final HttpResponse respond(HttpRequest req) {
+            HttpResponse res = new HttpResponse();
+            res.setHeader("Content-Type", "application/json");
+            res.setBody("{\"ok\":true}");
+            res.setStatusCode(200);
+            return res;
+        }
+    }
+
+    @org.junit.jupiter.api.Test
+    void sendsRequest() {
+        Test.setMock(Type.of(HttpCalloutMock.class), new StubMock());
+        HttpRequest req = new HttpRequest();
+        req.setEndpoint("callout:Stub/items?limit=1");
+        req.setMethod("GET");
+        req.setTimeout(5000);
+        HttpResponse res = new Http().send(req);
+        System.assertEquals(200, res.getStatusCode());
+        System.assertEquals("application/json", res.getHeader("Content-Type"));
+    }
+
+    @org.junit.jupiter.api.BeforeEach
+    public void resetTestContext() {
+        Test.reset();
+    }
+}
@@ -0,0 +1,35 @@
+import com.freedom_man.system.Enums;
+
+public class Scheduler {
+    public enum Season {
+        WINTER,
+        SPRING,
+        SUMMER,
+        FALL
+    }
+
+    public interface Job {
+        Integer run(Season season);
+    }
+
+    public static class CountingJob implements Job {
+        private Integer runs = 0;
+
+        public final Integer run(Season season) {
+            runs = runs + season.ordinal();
+            return runs;
+        }
+    }
+
+    public final Integer runAll(Job job) {
+        Integer total = 0;
+        for (Season s : Enums.values(Season.class)) {
+            total = total + job.run(s);
+        }
+        return total;
+    }
+
+    public final Season parse(String name) {
+        return Enums.valueOf(Season.class, name);
+    }
+}
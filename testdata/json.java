@@ -0,0 +1,35 @@
+import com.freedom_man.system.ApexOperator;
+import com.freedom_man.system.JSON;
+import com.freedom_man.system.JSONGenerator;
+import com.freedom_man.system.JSONParser;
+import com.freedom_man.system.JSONToken;
+import com.freedom_man.system.List;
+import com.freedom_man.system.Map;
+import com.freedom_man.system.System;
+import com.freedom_man.system.Type;
+
+public class Payloads {
+    public static class Item {
+        public String name;
+        public Integer count;
+    }
+
+    public static void run(String body) {
+        Item i = (Item)JSON.deserialize(body, Type.of(Item.class));
+        List<Item> items = (List<Item>)JSON.deserialize(body, Type.of(List.class, Type.of(Item.class)));
+        Map<String, Object> m = (Map<String, Object>)JSON.deserializeUntyped(body);
+        Type t = Type.forName("Payloads.Item");
+        String s = JSON.serialize(i);
+        String p = JSON.serializePretty(items, true);
+        JSONGenerator gen = JSON.createGenerator(true);
+        gen.writeStartObject();
+        gen.writeStringField("a", "b");
+        gen.writeEndObject();
+        JSONParser parser = JSON.createParser(body);
+        while (parser.nextToken() != null) {
+            if (ApexOperator.equals(parser.getCurrentToken(), JSONToken.FIELD_NAME)) {
+                System.debug(parser.getText());
+            }
+        }
+    }
+}
@@ -0,0 +1,37 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.Database;
+import com.freedom_man.system.IsTest;
+import com.freedom_man.system.System;
+import com.freedom_man.system.Test;
+
+@IsTest(seeAllData = false, isParallel = true)
+class AccountServiceTest {
+    @org.junit.jupiter.api.BeforeEach
+    void setup() {
+        Test.reset();
+        Account acc = new Account();
+        Database.insert(acc);
+    }
+
+    @org.junit.jupiter.api.Test
+    void assertsValues() {
+        Test.startTest();
+        Integer count = 2;
+        Test.stopTest();
+        System.assertTrue(count > 1);
+        System.assertEquals(2, count, "count");
+        System.assertNotEquals(3, count);
+    }
+
+    @org.junit.jupiter.api.Test
+    void deletes() {
+        Account acc = new Account();
+        Database.insert(acc);
+        Database.delete(acc);
+        System.assertTrue(Test.isRunningTest());
+    }
+
+    static Integer helper() {
+        return 1;
+    }
+}
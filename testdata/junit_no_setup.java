@@ -0,0 +1,21 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.Database;
+import com.freedom_man.system.IsTest;
+import com.freedom_man.system.List;
+import com.freedom_man.system.System;
+import com.freedom_man.system.Test;
+
+@IsTest
+public class NoSetupTest {
+    @org.junit.jupiter.api.Test
+    void runs() {
+        List<Account> accounts = new List<Account>();
+        Database.upsert(accounts);
+        System.assertEquals(0, accounts.size());
+    }
+
+    @org.junit.jupiter.api.BeforeEach
+    public void resetTestContext() {
+        Test.reset();
+    }
+}
@@ -0,0 +1,17 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.List;
+import com.freedom_man.system.System;
+import com.freedom_man.system.Test;
+
+public class LegacyTest {
+    @org.junit.jupiter.api.Test
+    void countsAccounts() {
+        List<Account> accounts = new List<Account>();
+        System.assertEquals(0, accounts.size());
+    }
+
+    @org.junit.jupiter.api.BeforeEach
+    public void resetTestContext() {
+        Test.reset();
+    }
+}
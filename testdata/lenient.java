@@ -0,0 +1,40 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.ApexOperator;
+import com.freedom_man.system.AuraEnabled;
+import com.freedom_man.system.Database;
+import com.freedom_man.system.List;
+import com.freedom_man.system.SObject;
+import com.freedom_man.system.Unsupported;
+
+public class Lenient {
+    // TODO(apex2java): property at lenient.cls:2: public Integer count { get; set; }
+    public Integer count;
+    // TODO(apex2java): ternary expression at lenient.cls:3: private String label = count > 0 ? 'many' : 'none';
+    private String label = Unsupported.<String>raise("ternary expression at lenient.cls:3");
+
+    public final List<Account> search(String term) {
+        List<Account> accounts = new List<Account>();
+        // TODO(apex2java): ternary expression at lenient.cls:6: if (String.isBlank(term) ? false : true) {
+        if (Unsupported.<Boolean>raise("ternary expression at lenient.cls:6")) {
+            // TODO(apex2java): SOSL query at lenient.cls:7: List<List<SObject>> found = [FIND :term IN ALL FIELDS RETURNING Account(Id, Name)];
+            List<List<SObject>> found = Unsupported.<List<List<SObject>>>raise("SOSL query at lenient.cls:7");
+            accounts = (List<Account>)found.get(0);
+        }
+        return accounts;
+    }
+
+    public final List<Account> searchAll() {
+        // TODO(apex2java): SOSL query at lenient.cls:13: List<Account> xs = [FIND 'x' IN ALL FIELDS RETURNING Account];
+        List<Account> xs = Unsupported.<List<Account>>raise("SOSL query at lenient.cls:13");
+        // TODO(apex2java): query assigned to String at lenient.cls:14: String name = [SELECT Name FROM Account LIMIT 1];
+        String name = Unsupported.<String>raise("query assigned to String at lenient.cls:14");
+        return xs;
+    }
+
+    // TODO(apex2java): parameter scope of @AuraEnabled at lenient.cls:17: @AuraEnabled(cacheable=true scope='global')
+    @AuraEnabled(cacheable = true)
+    public final String describe(Integer n) {
+        // TODO(apex2java): ternary expression at lenient.cls:19: return n == 1 ? 'one' : 'other';
+        return Unsupported.<String>raise("ternary expression at lenient.cls:19");
+    }
+}
@@ -0,0 +1,25 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.Database;
+import com.freedom_man.system.LimitException;
+import com.freedom_man.system.Limits;
+import com.freedom_man.system.List;
+import com.freedom_man.system.System;
+
+public class LimitAware {
+    public static List<Account> load(String name) {
+        if (Limits.getQueries() >= Limits.getLimitQueries()) {
+            return new List<Account>();
+        }
+        return Database.<Account>query("SELECT Id, Name FROM Account WHERE Name = ?", name);
+    }
+
+    public static void save(List<Account> accounts) {
+        Integer remaining = Limits.getLimitDmlRows() - Limits.getDmlRows();
+        if (accounts.size() > remaining) {
+            throw new LimitException("Too many DML rows: " + accounts.size());
+        }
+        Database.insert(accounts);
+        System.debug(Limits.getDmlStatements() + " of " + Limits.getLimitDmlStatements());
+        System.debug(Limits.getCpuTime() + "ms, " + Limits.getHeapSize() + " bytes");
+    }
+}
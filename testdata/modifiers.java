@@ -0,0 +1,45 @@
+import com.freedom_man.system.Sharing;
+
+@Sharing(Sharing.Mode.WITH)
+public class Shape {
+    public Integer sides;
+
+    public Integer area() {
+        return 0;
+    }
+
+    public final Integer perimeter() {
+        return sides;
+    }
+
+    private Integer scale() {
+        return 1;
+    }
+
+    public static Integer count() {
+        return 0;
+    }
+
+    public static String describe() {
+        return "shape";
+    }
+
+    public abstract static class Base {
+        public abstract Integer size();
+    }
+
+    @Sharing(Sharing.Mode.WITHOUT)
+    public static class Square extends Shape {
+        @Override
+        public Integer area() {
+            return sides * sides;
+        }
+    }
+
+    @Sharing(Sharing.Mode.INHERITED)
+    public static class Probe {
+        public final Integer run() {
+            return 1;
+        }
+    }
+}
@@ -0,0 +1,26 @@
+import com.freedom_man.system.ApexOperator;
+import com.freedom_man.system.Limits;
+import java.math.BigDecimal;
+
+public class Counter {
+    public Integer count;
+    public BigDecimal amount;
+
+    public final String calc(Integer a, Long b, String s) {
+        count++;
+        Integer c = ApexOperator.add(count++, a);
+        Long d = (Long) ApexOperator.add(a, b);
+        amount = ApexOperator.add(amount, new BigDecimal("1.5"));
+        for (Integer i = 0; i < a; i++) {
+            a = ApexOperator.subtract(a, 1);
+        }
+        return ApexOperator.concat(s, a);
+    }
+
+    public final BigDecimal total(BigDecimal price) {
+        amount = ApexOperator.increment(amount);
+        BigDecimal previous = ApexOperator.decrement(amount = ApexOperator.increment(amount));
+        Integer sum = ApexOperator.plus(Limits.getQueries(), Limits.getDmlRows());
+        return ApexOperator.negate(price);
+    }
+}
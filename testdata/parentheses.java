@@ -0,0 +1,12 @@
+
+public class Geometry {
+    public final Integer area(Integer width, Integer height, Integer margin) {
+        Integer total = (width + margin) * (height + margin);
+        Integer rest = total - (width - margin);
+        Boolean empty = !(total > 0);
+        Long wide = (width + height).longValue();
+        Object shape = width;
+        Integer side = (Integer)shape + 1;
+        return total / (height * 2);
+    }
+}
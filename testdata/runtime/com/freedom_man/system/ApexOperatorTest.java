@@ -0,0 +1,134 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+import java.math.BigDecimal;
+import java.util.Arrays;
+
+public class ApexOperatorTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testEqualsStringsIgnoringCase() {
+        assertTrue(ApexOperator.equals("Hello", "hELLO"), "strings compare ignoring case");
+        assertTrue(!ApexOperator.equals("Hello", "World"), "different strings");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testEqualsNull() {
+        assertTrue(ApexOperator.equals(null, null), "null == null");
+        assertTrue(!ApexOperator.equals("a", null), "a != null");
+        assertTrue(!ApexOperator.equals(null, Integer.valueOf(1)), "null != 1");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testEqualsNumbersAcrossTypes() {
+        assertTrue(ApexOperator.equals(Integer.valueOf(1), Long.valueOf(1)), "1 == 1L");
+        assertTrue(ApexOperator.equals(Double.valueOf(1.5), new BigDecimal("1.50")), "1.5 == 1.50");
+        assertTrue(!ApexOperator.equals(Integer.valueOf(1), Integer.valueOf(2)), "1 != 2");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testEqualsBooleans() {
+        assertTrue(!ApexOperator.equals(null, Boolean.TRUE), "null != true");
+        assertTrue(ApexOperator.equals(Boolean.FALSE, Boolean.FALSE), "false == false");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testEqualsId() {
+        assertTrue(ApexOperator.equalsId("001000000000001AAA", "001000000000001AAA"), "same Id");
+        assertTrue(!ApexOperator.equalsId("001000000000001AAA", "001000000000001aaa"), "Ids are case-sensitive");
+        assertTrue(ApexOperator.equalsId("001000000000001", "001000000000001AAA"), "15 and 18 character forms");
+        assertTrue(!ApexOperator.equalsId("001000000000001", "001000000000002AAA"), "different Ids");
+        assertTrue(ApexOperator.equalsId(null, null), "null == null");
+        assertTrue(!ApexOperator.equalsId("001000000000001", null), "Id != null");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testEqualsSObjects() {
+        Account a = new Account();
+        Account b = new Account();
+        assertTrue(ApexOperator.equals(a, b), "records without fields");
+        a.Name = "Acme";
+        assertTrue(!ApexOperator.equals(a, b), "different names");
+        b.Name = "Acme";
+        assertTrue(ApexOperator.equals(a, b), "same names");
+        assertEquals(a.hashCode(), b.hashCode());
+        b.put("Rating__c", "Hot");
+        assertTrue(!ApexOperator.equals(a, b), "different undeclared fields");
+        assertTrue(!ApexOperator.equals(new Account(), new User()), "different types");
+        java.util.Set<Account> set = new java.util.HashSet<Account>();
+        set.add(a);
+        assertTrue(set.contains(a.clone(true)), "sets compare records field by field");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testEqualsCollections() {
+        java.util.List<Object> l = Arrays.<Object>asList("A", Integer.valueOf(1));
+        java.util.List<Object> r = Arrays.<Object>asList("a", Long.valueOf(1));
+        assertTrue(ApexOperator.equals(l, r), "lists compare element by element");
+        java.util.Map<String, Object> lm = new java.util.HashMap<String, Object>();
+        java.util.Map<String, Object> rm = new java.util.HashMap<String, Object>();
+        lm.put("k", "V");
+        rm.put("k", "v");
+        assertTrue(ApexOperator.equals(lm, rm), "maps compare value by value");
+        rm.put("other", "v");
+        assertTrue(!ApexOperator.equals(lm, rm), "maps of different sizes");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testCompareStrings() {
+        assertTrue(ApexOperator.compare("apple", "BANANA") < 0, "apple < BANANA");
+        assertEquals(0, ApexOperator.compare("a", "A"));
+        assertTrue(ApexOperator.compare(null, "a") < 0, "null sorts first");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testArithmetic() {
+        assertEquals(Integer.valueOf(5), ApexOperator.add(Integer.valueOf(2), Integer.valueOf(3)));
+        assertEquals(Long.valueOf(-1), ApexOperator.subtract(Long.valueOf(2), Long.valueOf(3)));
+        assertEquals(new BigDecimal("0.5"), ApexOperator.divide(new BigDecimal("1"), new BigDecimal("2")));
+        assertEquals(Integer.valueOf(1), ApexOperator.mod(Integer.valueOf(7), Integer.valueOf(3)));
+        assertEquals(Long.valueOf(3), ApexOperator.add((Number) Integer.valueOf(1), (Number) Long.valueOf(2)));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testPlus() {
+        Integer sum = ApexOperator.plus(Integer.valueOf(1), Integer.valueOf(2));
+        assertEquals(Integer.valueOf(3), sum);
+        String text = ApexOperator.plus("a", Integer.valueOf(1));
+        assertEquals("a1", text);
+        assertThrows(ClassCastException.class, () -> {
+            Integer wrong = ApexOperator.plus("a", Integer.valueOf(1));
+        });
+        assertThrows(TypeException.class, () -> ApexOperator.plus(Integer.valueOf(1), Boolean.TRUE));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testIncrementDecimal() {
+        assertEquals(new BigDecimal("2.5"), ApexOperator.increment(new BigDecimal("1.5")));
+        assertEquals(new BigDecimal("0.5"), ApexOperator.decrement(new BigDecimal("1.5")));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testArithmeticOnNull() {
+        assertThrows(NullPointerException.class, () -> ApexOperator.add(Integer.valueOf(1), (Integer) null));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testDivideByZero() {
+        MathException e = assertThrows(MathException.class, () -> ApexOperator.divide(Integer.valueOf(1), Integer.valueOf(0)));
+        assertEquals("Divide by 0", e.getMessage());
+    }
+
+    @org.junit.jupiter.api.Test
+    void testConcat() {
+        assertEquals("anull", ApexOperator.concat("a", null));
+        assertEquals("1.50", ApexOperator.concat("", new BigDecimal("1.50")));
+    }
+}
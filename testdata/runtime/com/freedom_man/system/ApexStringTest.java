@@ -0,0 +1,81 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+public class ApexStringTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testBlankAndEmpty() {
+        assertTrue(ApexString.isBlank(null), "null is blank");
+        assertTrue(ApexString.isBlank(" \t"), "whitespace is blank");
+        assertTrue(!ApexString.isEmpty(" "), "a space is not empty");
+        assertTrue(ApexString.isNotEmpty("a"), "a is not empty");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testInstanceMethodOnNull() {
+        assertThrows(NullPointerException.class, () -> ApexString.left(null, 1));
+        assertThrows(NullPointerException.class, () -> ApexString.split(null, ","));
+        assertThrows(NullPointerException.class, () -> ApexString.substringBetween(null, "a", "b"));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testNullArguments() {
+        assertTrue(ApexString.isEmpty(null), "null is empty");
+        assertTrue(!ApexString.equalsIgnoreCase("a", null), "a does not equal null");
+        assertEquals(0, ApexString.countMatches("aaa", null));
+        assertEquals("a-b", ApexString.substringBefore("a-b", null));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testAbbreviate() {
+        assertEquals("abc", ApexString.abbreviate("abc", 8));
+        assertEquals("Hello...", ApexString.abbreviate("Hello World", 8));
+        assertEquals("...fghi...", ApexString.abbreviate("abcdefghijklmno", 10, 5));
+        assertEquals("...ijklmno", ApexString.abbreviate("abcdefghijklmno", 10, 12));
+        assertThrows(StringException.class, () -> ApexString.abbreviate("abcdef", 3));
+        assertThrows(StringException.class, () -> ApexString.abbreviate("abcdefghij", 6, 5));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testSplitWithRegex() {
+        assertEquals(java.util.Arrays.asList("a", "b", "c"), ApexString.split("a1b22c", "[0-9]+"));
+        assertEquals(java.util.Arrays.asList("a", "b"), ApexString.split("a.b", "\\."));
+        assertEquals(0, ApexString.split("a.b", ".").size());
+        assertEquals(java.util.Arrays.asList("a", "b"), ApexString.split("a,b,,", ","));
+        assertEquals(java.util.Arrays.asList("a", "b,c"), ApexString.split("a,b,c", ",", 2));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testSubstringBetween() {
+        assertEquals("x", ApexString.substringBetween("<a>x</a>", "<a>", "</a>"));
+        assertEquals("x", ApexString.substringBetween("*x*", "*"));
+        assertEquals(null, ApexString.substringBetween("<a>x", "<a>", "</a>"));
+        assertEquals(null, ApexString.substringBetween("x", "<a>", "</a>"));
+        assertEquals(null, ApexString.substringBetween("x", null, "</a>"));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testLeftRightMid() {
+        assertEquals("ab", ApexString.left("abc", 2));
+        assertEquals("bc", ApexString.right("abc", 2));
+        assertEquals("b", ApexString.mid("abc", 1, 1));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testPad() {
+        assertEquals("  a", ApexString.leftPad("a", 3));
+        assertEquals("a--", ApexString.rightPad("a", 3, "-"));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testJoin() {
+        assertEquals("a,,1", ApexString.join(java.util.Arrays.asList("a", null, 1), ","));
+    }
+}
@@ -0,0 +1,147 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertFalse;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+public class DataStoreTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testInsert() {
+        Account a = new Account();
+        a.Name = "Acme";
+        DataStore.insert(a);
+        assertTrue(a.Id != null && a.Id.startsWith("001") && a.Id.length() == 18, "insert assigns an Account Id");
+        assertTrue(a.CreatedDate != null, "insert sets CreatedDate");
+        java.util.List<SObject> stored = DataStore.records("Account");
+        assertEquals(1, stored.size());
+        assertEquals("Acme", ((Account) stored.get(0)).Name);
+    }
+
+    @org.junit.jupiter.api.Test
+    void testInsertWithId() {
+        Account a = new Account();
+        DataStore.insert(a);
+        assertThrows(DmlException.class, () -> DataStore.insert(a));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testRecordsAreCopies() {
+        Account a = new Account();
+        a.Name = "Acme";
+        DataStore.insert(a);
+        a.Name = "Changed";
+        assertEquals("Acme", ((Account) DataStore.records("Account").get(0)).Name);
+        ((Account) DataStore.records("Account").get(0)).Name = "Changed";
+        assertEquals("Acme", ((Account) DataStore.records("Account").get(0)).Name);
+    }
+
+    @org.junit.jupiter.api.Test
+    void testUpdate() {
+        Account a = new Account();
+        a.Name = "Acme";
+        DataStore.insert(a);
+        a.Name = "Acme Corp";
+        DataStore.update(a);
+        assertEquals("Acme Corp", ((Account) DataStore.records("Account").get(0)).Name);
+    }
+
+    @org.junit.jupiter.api.Test
+    void testUpdateKeepsUnsetFields() {
+        Account a = new Account();
+        a.Name = "Acme";
+        a.Industry = "Banking";
+        DataStore.insert(a);
+        Account b = new Account();
+        b.Id = a.Id;
+        b.Name = "Acme Corp";
+        DataStore.update(b);
+        Account stored = (Account) DataStore.records("Account").get(0);
+        assertEquals("Acme Corp", stored.Name);
+        assertEquals("Banking", stored.Industry);
+    }
+
+    @org.junit.jupiter.api.Test
+    void testUpdateSetsNull() {
+        Account a = new Account();
+        a.Name = "Acme";
+        a.Industry = "Banking";
+        DataStore.insert(a);
+        a.Industry = null;
+        DataStore.update(a);
+        Account queried = (Account) DataStore.records("Account").get(0);
+        assertEquals(null, queried.Industry);
+        queried.Name = null;
+        DataStore.update(queried);
+        assertEquals(null, ((Account) DataStore.records("Account").get(0)).Name);
+        a.Industry = "Retail";
+        DataStore.update(a);
+        assertEquals("Retail", ((Account) DataStore.records("Account").get(0)).Industry);
+        Account b = new Account();
+        b.Id = a.Id;
+        b.put("Industry", null);
+        DataStore.update(b);
+        assertEquals(null, ((Account) DataStore.records("Account").get(0)).Industry);
+    }
+
+    @org.junit.jupiter.api.Test
+    void testUpdateSkipsRelatedRecords() {
+        Account a = new Account();
+        DataStore.insert(a);
+        List<SObject> contacts = new List<SObject>();
+        contacts.add(new Account());
+        a.putSObjects("Contacts", contacts);
+        a.putSObject("Parent", new Account());
+        a.Name = "Acme";
+        DataStore.update(a);
+        Account stored = (Account) DataStore.records("Account").get(0);
+        assertEquals("Acme", stored.Name);
+        assertEquals(null, stored.getSObjects("Contacts"));
+        assertEquals(null, stored.getSObject("Parent"));
+        assertFalse(stored.getPopulatedFieldsAsMap().containsKey("Contacts"));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testUpdateWithoutId() {
+        assertThrows(DmlException.class, () -> DataStore.update(new Account()));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testUpsertByExternalId() {
+        Account a = new Account();
+        a.Name = "Acme";
+        DataStore.insert(a);
+        Account b = new Account();
+        b.Name = "ACME";
+        b.Industry = "Banking";
+        DataStore.upsert(b, "Name");
+        assertEquals(a.Id, b.Id);
+        assertEquals(1, DataStore.records("Account").size());
+    }
+
+    @org.junit.jupiter.api.Test
+    void testDeleteAndUndelete() {
+        Account a = new Account();
+        DataStore.insert(a);
+        DataStore.delete(a);
+        assertEquals(0, DataStore.records("Account").size());
+        assertThrows(DmlException.class, () -> DataStore.delete(a));
+        DataStore.undelete(a);
+        assertEquals(1, DataStore.records("Account").size());
+    }
+
+    @org.junit.jupiter.api.Test
+    void testReset() {
+        DataStore.insert(new Account());
+        Test.reset();
+        assertEquals(0, DataStore.records("Account").size());
+        Account a = new Account();
+        DataStore.insert(a);
+        assertEquals("001000000000000001", a.Id);
+    }
+}
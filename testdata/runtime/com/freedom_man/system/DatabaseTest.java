@@ -0,0 +1,34 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+public class DatabaseTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testQueryRow() {
+        assertThrows(QueryException.class, () -> Database.<Account>queryRow("SELECT Id FROM Account"));
+        Account a = new Account();
+        a.Name = "Acme";
+        DataStore.insert(a);
+        Account row = Database.<Account>queryRow("SELECT Id, Name FROM Account WHERE Name = ?", "Acme");
+        assertEquals(a.Id, row.Id);
+        DataStore.insert(new Account());
+        assertThrows(QueryException.class, () -> Database.<Account>queryRow("SELECT Id FROM Account"));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testCountQuery() {
+        assertEquals(0, Database.countQuery("SELECT COUNT() FROM Account"));
+        Account a = new Account();
+        a.Name = "Acme";
+        DataStore.insert(a);
+        DataStore.insert(new Account());
+        assertEquals(2, Database.countQuery("SELECT COUNT() FROM Account"));
+        assertEquals(1, Database.countQuery("SELECT COUNT() FROM Account WHERE Name = ?", "Acme"));
+    }
+}
@@ -0,0 +1,61 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+public class ExceptionTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testTranslateNullPointerException() {
+        java.lang.NullPointerException jvm = new java.lang.NullPointerException();
+        RuntimeException e = Exception.translate(jvm);
+        assertTrue(e instanceof NullPointerException, "java.lang.NullPointerException becomes System.NullPointerException");
+        assertEquals("System.NullPointerException: Attempt to de-reference a null object", e.toString());
+        assertEquals(jvm.getStackTrace().length, e.getStackTrace().length);
+    }
+
+    @org.junit.jupiter.api.Test
+    void testTranslateJVMExceptions() {
+        assertTrue(Exception.translate(new IndexOutOfBoundsException("5")) instanceof ListException, "IndexOutOfBoundsException");
+        assertTrue(Exception.translate(new ClassCastException()) instanceof TypeException, "ClassCastException");
+        assertTrue(Exception.translate(new NumberFormatException()) instanceof TypeException, "NumberFormatException");
+        assertTrue(Exception.translate(new ArithmeticException()) instanceof MathException, "ArithmeticException");
+        assertTrue(Exception.translate(new java.util.ConcurrentModificationException()) instanceof FinalException, "ConcurrentModificationException");
+        assertTrue(Exception.translate(new java.util.NoSuchElementException()) instanceof NoSuchElementException, "NoSuchElementException");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testTranslateKeepsApexExceptions() {
+        DmlException e = new DmlException("failed");
+        assertTrue(Exception.translate(e) == e, "Apex exceptions are not translated");
+        UnsupportedOperationException other = new UnsupportedOperationException();
+        assertTrue(Exception.translate(other) == other, "unknown exceptions are not translated");
+    }
+
+    @org.junit.jupiter.api.Test
+    void testCauseIsHiddenFromApex() {
+        Exception e = (Exception) Exception.translate(new ClassCastException());
+        assertEquals(null, e.getCause());
+    }
+
+    // testCatch is what a converted try statement does.
+    @org.junit.jupiter.api.Test
+    void testCatch() {
+        String caught = null;
+        try {
+            try {
+                Object value = "a";
+                Integer i = (Integer) value;
+            } catch (RuntimeException jvmException) {
+                throw Exception.translate(jvmException);
+            }
+        } catch (TypeException e) {
+            caught = e.getTypeName();
+        }
+        assertEquals("System.TypeException", caught);
+    }
+}
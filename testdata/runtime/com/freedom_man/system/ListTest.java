@@ -0,0 +1,40 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+public class ListTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testIndexOutOfBounds() {
+        List<String> values = new List<String>();
+        values.add("a");
+        assertEquals("List index out of bounds: 1", assertThrows(ListException.class, () -> values.get(1)).getMessage());
+        assertEquals("List index out of bounds: -1", assertThrows(ListException.class, () -> values.set(-1, "b")).getMessage());
+        assertThrows(ListException.class, () -> values.remove(1));
+        assertThrows(ListException.class, () -> values.add(2, "b"));
+        values.add(1, "b");
+        assertEquals("b", values.get(1));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testModifyWhileIterating() {
+        List<String> values = new List<String>();
+        values.add("a");
+        values.add("b");
+        assertThrows(FinalException.class, () -> {
+            for (String value : values) {
+                values.add(value);
+            }
+        });
+    }
+
+    @org.junit.jupiter.api.Test
+    void testIteratorExhausted() {
+        assertThrows(NoSuchElementException.class, () -> new List<String>().iterator().next());
+    }
+}
@@ -0,0 +1,31 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+public class MapTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testModifyWhileIteratingKeys() {
+        Map<String, Integer> values = new Map<String, Integer>();
+        values.put("a", 1);
+        values.put("b", 2);
+        for (String key : values.keySet()) {
+            values.remove(key);
+        }
+        assertEquals(0, values.size());
+    }
+
+    @org.junit.jupiter.api.Test
+    void testValuesIsList() {
+        Map<String, Integer> values = new Map<String, Integer>();
+        values.put("a", 1);
+        List<Integer> list = values.values();
+        list.add(2);
+        assertEquals(1, values.size());
+        assertEquals(2, list.size());
+    }
+}
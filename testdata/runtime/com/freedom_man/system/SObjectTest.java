@@ -0,0 +1,75 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+public class SObjectTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testGetAndPutTypedFields() {
+        Account a = new Account();
+        assertEquals(null, a.put("name", "Acme"));
+        assertEquals("Acme", a.Name);
+        assertEquals("Acme", a.get("NAME"));
+        assertEquals("Acme", a.put(Schema.getSObjectField(Account.class, "Name"), "Acme Corp"));
+        assertEquals("Acme Corp", a.Name);
+    }
+
+    @org.junit.jupiter.api.Test
+    void testIllegalAssignment() {
+        SObjectException e = assertThrows(SObjectException.class, () -> new Account().put("Name", Integer.valueOf(1)));
+        assertEquals("Illegal assignment from Integer to field Name", e.getMessage());
+    }
+
+    @org.junit.jupiter.api.Test
+    void testUndeclaredFields() {
+        Account a = new Account();
+        assertEquals(null, a.get("Rating__c"));
+        a.put("Rating__c", "Hot");
+        assertEquals("Hot", a.get("rating__c"));
+        assertEquals("Hot", a.getPopulatedFieldsAsMap().get("Rating__c"));
+        Account c = a.clone();
+        c.put("Rating__c", "Cold");
+        assertEquals("Hot", a.get("Rating__c"));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testUndeclaredFieldsInJSON() {
+        Account a = new Account();
+        a.Name = "Acme";
+        a.put("Rating__c", "Hot");
+        String json = JSON.serialize(a);
+        assertTrue(json.contains("\"Rating__c\":\"Hot\""), json);
+        Account b = (Account) JSON.deserialize(json, Type.of(Account.class));
+        assertEquals("Acme", b.Name);
+        assertEquals("Hot", b.get("Rating__c"));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testPopulatedFields() {
+        Account a = new Account();
+        a.Name = "Acme";
+        java.util.Map<String, Object> populated = a.getPopulatedFieldsAsMap();
+        assertEquals(1, populated.size());
+        assertEquals("Acme", populated.get("Name"));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testQueryNamedBinds() {
+        Account a = new Account();
+        a.Name = "Acme";
+        a.put("Rating__c", "Hot");
+        DataStore.insert(a);
+        DataStore.insert(new Account());
+        List<Account> found = Database.query("SELECT Id FROM Account WHERE Rating__c = :rating", Database.binds("rating", "Hot"));
+        assertEquals(1, found.size());
+        assertEquals(a.Id, found.get(0).Id);
+        QueryException e = assertThrows(QueryException.class, () -> Database.query("SELECT Id FROM Account WHERE Name = :missing", Database.binds("rating", "Hot")));
+        assertEquals("Variable does not exist: missing", e.getMessage());
+    }
+}
@@ -0,0 +1,81 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+public class SharingTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @Sharing(Sharing.Mode.WITH)
+    static class WithSharing {
+        static Integer count() {
+            return Database.query("SELECT Id FROM Account").size();
+        }
+
+        static Integer inherited() {
+            return InheritedSharing.count();
+        }
+    }
+
+    @Sharing(Sharing.Mode.WITHOUT)
+    static class WithoutSharing {
+        static Integer count() {
+            return Database.query("SELECT Id FROM Account").size();
+        }
+
+        static Integer inherited() {
+            return InheritedSharing.count();
+        }
+    }
+
+    @Sharing(Sharing.Mode.INHERITED)
+    static class InheritedSharing {
+        static Integer count() {
+            return Database.query("SELECT Id FROM Account").size();
+        }
+    }
+
+    private static User owner() {
+        User user = new User();
+        user.Id = "005000000000001AAA";
+        return user;
+    }
+
+    private static void insertAccounts() {
+        Account owned = new Account();
+        owned.put("OwnerId", "005000000000001");
+        DataStore.insert(owned);
+        Account other = new Account();
+        other.put("OwnerId", "005000000000002AAA");
+        DataStore.insert(other);
+        DataStore.insert(new Account());
+    }
+
+    @org.junit.jupiter.api.Test
+    void testWithSharing() {
+        insertAccounts();
+        assertEquals(3, WithSharing.count());
+        System.runAs(owner());
+        try {
+            assertEquals(2, WithSharing.count());
+            assertEquals(3, WithoutSharing.count());
+        } finally {
+            System.endRunAs();
+        }
+    }
+
+    @org.junit.jupiter.api.Test
+    void testInheritedSharing() {
+        insertAccounts();
+        System.runAs(owner());
+        try {
+            assertEquals(2, InheritedSharing.count());
+            assertEquals(2, WithSharing.inherited());
+            assertEquals(3, WithoutSharing.inherited());
+        } finally {
+            System.endRunAs();
+        }
+    }
+}
@@ -0,0 +1,52 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertTrue;
+
+import java.io.ByteArrayOutputStream;
+import java.io.PrintStream;
+import java.net.URL;
+import java.net.URLClassLoader;
+import java.nio.charset.StandardCharsets;
+import java.nio.file.Files;
+import java.nio.file.Path;
+
+public class SourceMapTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    static class Mapped {
+        static void log() {
+            System.debug("mapped");
+        }
+    }
+
+    // testDebugReportsApexLine maps every line of this file to Apex line 42.
+    @org.junit.jupiter.api.Test
+    void testDebugReportsApexLine() throws java.io.IOException {
+        Path dir = Files.createTempDirectory("apex2java");
+        Path map = dir.resolve("apex2java/com/freedom_man/system/SourceMapTest.map.json");
+        Files.createDirectories(map.getParent());
+        StringBuilder lines = new StringBuilder();
+        for (int i = 0; i < 1000; i++) {
+            lines.append(i == 0 ? "" : ",").append(42);
+        }
+        Files.write(map, ("{\"version\":1,\"file\":\"SourceMapTest.java\",\"source\":\"Mapped.cls\",\"lines\":[" + lines + "]}").getBytes(StandardCharsets.UTF_8));
+
+        Thread thread = Thread.currentThread();
+        ClassLoader loader = thread.getContextClassLoader();
+        PrintStream out = java.lang.System.out;
+        ByteArrayOutputStream captured = new ByteArrayOutputStream();
+        try (URLClassLoader maps = new URLClassLoader(new URL[] {dir.toUri().toURL()}, loader)) {
+            thread.setContextClassLoader(maps);
+            java.lang.System.setOut(new PrintStream(captured, true, "UTF-8"));
+            Mapped.log();
+        } finally {
+            java.lang.System.setOut(out);
+            thread.setContextClassLoader(loader);
+        }
+        String log = new String(captured.toByteArray(), StandardCharsets.UTF_8);
+        assertTrue(log.contains("|USER_DEBUG|[42]|DEBUG|mapped"), log);
+    }
+}
@@ -0,0 +1,26 @@
+package com.freedom_man.system;
+
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+public class SystemTest {
+    @org.junit.jupiter.api.BeforeEach
+    void resetTestContext() {
+        Test.reset();
+    }
+
+    @org.junit.jupiter.api.Test
+    void testAssertEqualsIsCaseSensitive() {
+        System.assertEquals("Acme", "Acme");
+        System.assertEquals(1, 1L);
+        assertThrows(AssertException.class, () -> System.assertEquals("Acme", "ACME"));
+        java.util.List<String> expected = new List<String>(java.util.Arrays.asList("a"));
+        java.util.List<String> actual = new List<String>(java.util.Arrays.asList("A"));
+        assertThrows(AssertException.class, () -> System.assertEquals(expected, actual));
+    }
+
+    @org.junit.jupiter.api.Test
+    void testAssertNotEqualsIsCaseSensitive() {
+        System.assertNotEquals("Acme", "ACME");
+        assertThrows(AssertException.class, () -> System.assertNotEquals("Acme", "Acme"));
+    }
+}
@@ -0,0 +1,14 @@
+import com.freedom_man.system.RuntimeVersion;
+
+public class Versioned {
+    static {
+        RuntimeVersion.check("0.1.0");
+    }
+
+    public static class Inner {
+    }
+
+    public final String name() {
+        return "versioned";
+    }
+}
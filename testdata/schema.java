@@ -0,0 +1,29 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.ApexOperator;
+import com.freedom_man.system.List;
+import com.freedom_man.system.Map;
+import com.freedom_man.system.Schema;
+import com.freedom_man.system.System;
+
+public class Describer {
+    public static List<String> industries() {
+        List<String> values = new List<String>();
+        for (Schema.PicklistEntry e : Schema.getSObjectField(Account.class, "Industry").getDescribe().getPicklistValues()) {
+            values.add(e.getValue());
+        }
+        return values;
+    }
+
+    public static void run(Account acc) {
+        Map<String, Schema.SObjectType> gd = Schema.getGlobalDescribe();
+        Map<String, Schema.SObjectField> fields = gd.get("Account").getDescribe().fields.getMap();
+        Schema.SObjectType t = Schema.getSObjectType(Account.class);
+        Schema.SObjectField f = Schema.getSObjectField(Account.class, "Name");
+        Schema.DescribeFieldResult name = Schema.getSObjectField(Account.class, "Name").getDescribe();
+        String label = Schema.getSObjectType(Account.class).getDescribe().getLabel();
+        String prefix = Schema.getSObjectType(Account.class).getDescribe().getKeyPrefix();
+        Account created = (Account)t.newSObject();
+        System.debug(acc.Name);
+        System.debug(ApexOperator.equals(t, acc.getSObjectType()));
+    }
+}
@@ -0,0 +1,34 @@
+import com.freedom_man.system.Account;
+import com.freedom_man.system.Database;
+import com.freedom_man.system.List;
+import com.freedom_man.system.SObject;
+
+public class AccountQueries {
+    public final List<Account> byName(String name) {
+        return Database.<Account>query("SELECT Id, Name FROM Account WHERE Name = ?", name);
+    }
+
+    public final List<Account> search(List<String> ids, Integer max) {
+        return Database.<Account>query("SELECT Id, Name FROM Account WHERE (Id IN ? OR Name LIKE 'Acme%') AND NOT Name = 'O\\'Brien' ORDER BY Name DESC NULLS LAST LIMIT ?", ids, max);
+    }
+
+    public final Account firstNamed(String name) {
+        Account a = Database.<Account>queryRow("SELECT Id, Name FROM Account WHERE Name = ? LIMIT 1", name);
+        SObject record = null;
+        record = Database.<Account>queryRow("SELECT Id FROM Account LIMIT 1");
+        return a;
+    }
+
+    public final Integer countAll() {
+        Integer total = Database.countQuery("SELECT COUNT() FROM Account WHERE Name != null");
+        return total;
+    }
+
+    public final Integer countNamed() {
+        Integer count = 0;
+        for (Account a : Database.<Account>query("SELECT Id FROM Account WHERE Name != null")) {
+            count++;
+        }
+        return count;
+    }
+}
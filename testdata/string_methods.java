@@ -0,0 +1,22 @@
+import com.freedom_man.system.ApexString;
+import com.freedom_man.system.List;
+
+public class Names {
+    private String prefix = "Mr";
+
+    public final String shorten(String name, List<String> parts) {
+        if (ApexString.isBlank(name)) {
+            return ApexString.join(parts, ", ");
+        }
+        String trimmed = ApexString.left(ApexString.trim(name), 10);
+        Integer length = ApexString.length(trimmed);
+        if (ApexString.containsIgnoreCase(prefix, "m") && ApexString.equals(ApexString.mid("abc", 1, 1), "b")) {
+            List<Object> arguments = new List<Object>();
+            arguments.add(prefix);
+            arguments.add(ApexString.capitalize(trimmed));
+            return ApexString.format("{0} {1}", arguments);
+        }
+        List<String> words = ApexString.splitByCharacterType(name);
+        return ApexString.valueOf(length) + words.size() + ApexString.escapeSingleQuotes(ApexString.substringAfter(name, " "));
+    }
+}
@@ -0,0 +1,30 @@
+import com.freedom_man.system.Date;
+import com.freedom_man.system.Datetime;
+import com.freedom_man.system.LoggingLevel;
+import com.freedom_man.system.Queueable;
+import com.freedom_man.system.QueueableContext;
+import com.freedom_man.system.System;
+import com.freedom_man.system.User;
+
+public class Jobs implements Queueable {
+    public final void execute(QueueableContext context) {
+        System.debug(LoggingLevel.INFO, "job " + context.getJobId());
+        System.debug(System.isQueueable());
+    }
+
+    public static String start(User u) {
+        Datetime started = System.now();
+        Date day = System.today();
+        Long millis = System.currentTimeMillis();
+        String jobId = null;
+        System.runAs(u);
+        try {
+            System.debug(LoggingLevel.WARN, started);
+            jobId = System.enqueueJob(new Jobs());
+        } finally {
+            System.endRunAs();
+        }
+        System.abortJob(jobId);
+        return jobId;
+    }
+}
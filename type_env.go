@@ -0,0 +1,269 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// typeEnv holds the declared types of the variables visible from the
+// statement being generated. Apex identifiers are case-insensitive, so
+// names are stored lowercased.
+type typeEnv struct {
+	parent *typeEnv
+	types  map[string]*ast.TypeRef
+	// names are the names of types as declared, in order.
+	names []string
+	// members is set on the scope of the members of a class.
+	members bool
+}
+
+func newTypeEnv(parent *typeEnv) *typeEnv {
+	return &typeEnv{
+		parent: parent,
+		types:  map[string]*ast.TypeRef{},
+	}
+}
+
+func (e *typeEnv) Get(name string) (*ast.TypeRef, bool) {
+	for env := e; env != nil; env = env.parent {
+		if t, ok := env.types[strings.ToLower(name)]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func (e *typeEnv) Set(name string, t *ast.TypeRef) {
+	if _, ok := e.types[strings.ToLower(name)]; !ok {
+		e.names = append(e.names, name)
+	}
+	e.types[strings.ToLower(name)] = t
+}
+
+// Locals returns the names of the local variables and parameters in scope,
+// those of outer scopes first.
+func (e *typeEnv) Locals() []string {
+	if e == nil || e.members {
+		return nil
+	}
+	return append(e.parent.Locals(), e.names...)
+}
+
+func newTypeRef(name string, parameters ...*ast.TypeRef) *ast.TypeRef {
+	return &ast.TypeRef{
+		Name:       []string{name},
+		Parameters: parameters,
+	}
+}
+
+// typeName returns the lowercased, unqualified Apex type name, or "" if the
+// type could not be inferred.
+func typeName(t *ast.TypeRef) string {
+	if t == nil || len(t.Name) == 0 {
+		return ""
+	}
+	if t.Dimmension > 0 {
+		return "list"
+	}
+	return strings.ToLower(t.Name[len(t.Name)-1])
+}
+
+// typeOf infers the Apex type of the expression n from literals, declared
+// variables and the members of the class being generated. It returns nil
+// when the type is unknown.
+func (v *Generator) typeOf(n ast.Node) *ast.TypeRef {
+	switch e := n.(type) {
+	case *ast.StringLiteral:
+		return newTypeRef("String")
+	case *ast.IntegerLiteral:
+		return newTypeRef("Integer")
+	case *ast.DoubleLiteral:
+		return newTypeRef("Decimal")
+	case *ast.BooleanLiteral:
+		return newTypeRef("Boolean")
+	case *ast.Name:
+		return v.typeOfName(e.Value)
+	case *ast.CastExpression:
+		return e.CastTypeRef
+	case *ast.New:
+		return e.TypeRef
+	case *TypeLiteral:
+		return newTypeRef("Type")
+	case *Query:
+		return newTypeRef("List", newTypeRef(e.Object))
+	case *ast.Sosl:
+		return newTypeRef("List", newTypeRef("List", newTypeRef("SObject")))
+	case *ast.InstanceofOperator:
+		return newTypeRef("Boolean")
+	case *ast.TernalyExpression:
+		if t := v.typeOf(e.TrueExpression); t != nil {
+			return t
+		}
+		return v.typeOf(e.FalseExpression)
+	case *ast.ArrayAccess:
+		t := v.typeOf(e.Receiver)
+		if t == nil {
+			return nil
+		}
+		if t.Dimmension > 0 {
+			return &ast.TypeRef{Name: t.Name, Parameters: t.Parameters, Dimmension: t.Dimmension - 1}
+		}
+		switch typeName(t) {
+		case "list":
+			if len(t.Parameters) == 1 {
+				return t.Parameters[0]
+			}
+		case "map":
+			if len(t.Parameters) == 2 {
+				return t.Parameters[1]
+			}
+		}
+	case *ast.UnaryOperator:
+		if e.Op == "!" {
+			return newTypeRef("Boolean")
+		}
+		return v.typeOf(e.Expression)
+	case *ast.BinaryOperator:
+		return v.typeOfBinaryOperator(e)
+	case *ast.MethodInvocation:
+		if _, m, ok := v.stringMethod(e); ok {
+			return stringMethodType(m)
+		}
+		if t, ok := staticMethodType(e); ok {
+			return t
+		}
+		if name, ok := e.NameOrExpression.(*ast.Name); ok && len(name.Value) == 1 {
+			if t, ok := v.methods[strings.ToLower(name.Value[0])]; ok {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Generator) typeOfName(value []string) *ast.TypeRef {
+	if v.env == nil {
+		return nil
+	}
+	if len(value) == 2 && strings.ToLower(value[0]) == "this" {
+		value = value[1:]
+	}
+	if len(value) == 2 {
+		if t, ok := v.env.Get(value[0]); ok {
+			return sobjectFieldType(t, value[1])
+		}
+	}
+	if len(value) != 1 {
+		return nil
+	}
+	t, _ := v.env.Get(value[0])
+	return t
+}
+
+// apexFieldTypes maps the display types of sObject fields to Apex types
+// other than String.
+var apexFieldTypes = map[string]string{
+	"BOOLEAN":   "Boolean",
+	"DATE":      "Date",
+	"DATETIME":  "Datetime",
+	"ID":        "Id",
+	"INTEGER":   "Integer",
+	"REFERENCE": "Id",
+	"TIME":      "Time",
+}
+
+// sobjectFieldType returns the type of the field name of a record of type
+// t, or nil if t is not an sObject type of the metadata or lacks the field.
+// The Id of any record is known.
+func sobjectFieldType(t *ast.TypeRef, name string) *ast.TypeRef {
+	if typeName(t) == "sobject" && strings.EqualFold(name, "Id") {
+		return newTypeRef("Id")
+	}
+	sobject := findSObject(typeName(t))
+	if sobject == nil {
+		return nil
+	}
+	f := sobject.findField(name)
+	if f == nil {
+		return nil
+	}
+	if f.Type == "" {
+		return newTypeRef("Id")
+	}
+	if apexType, ok := apexFieldTypes[f.Type]; ok {
+		return newTypeRef(apexType)
+	}
+	return newTypeRef("String")
+}
+
+func (v *Generator) typeOfBinaryOperator(n *ast.BinaryOperator) *ast.TypeRef {
+	switch n.Op {
+	case "==", "!=", "===", "!==", "<", ">", "<=", ">=", "&&", "||":
+		return newTypeRef("Boolean")
+	case "=", "+=", "-=", "*=", "/=":
+		return v.typeOf(n.Left)
+	}
+	l := v.typeOf(n.Left)
+	r := v.typeOf(n.Right)
+	if n.Op == "+" && (isStringType(l) || isStringType(r)) {
+		return newTypeRef("String")
+	}
+	if isNumericType(l) && isNumericType(r) {
+		return widerNumericType(l, r)
+	}
+	if l != nil {
+		return l
+	}
+	return r
+}
+
+var numericTypes = map[string]int{
+	"integer": 1,
+	"long":    2,
+	"double":  3,
+	"decimal": 4,
+}
+
+func isNumericType(t *ast.TypeRef) bool {
+	_, ok := numericTypes[typeName(t)]
+	return ok
+}
+
+func widerNumericType(l, r *ast.TypeRef) *ast.TypeRef {
+	if numericTypes[typeName(l)] >= numericTypes[typeName(r)] {
+		return l
+	}
+	return r
+}
+
+func isStringType(t *ast.TypeRef) bool {
+	switch typeName(t) {
+	case "string", "id":
+		return true
+	}
+	return false
+}
+
+// isTemporalType reports whether t is a Date, Datetime or Time, which the
+// runtime compares with compareTo.
+func isTemporalType(t *ast.TypeRef) bool {
+	switch typeName(t) {
+	case "date", "datetime", "time":
+		return true
+	}
+	return false
+}
+
+func isIdType(t *ast.TypeRef) bool {
+	return typeName(t) == "id"
+}
+
+func isBooleanType(t *ast.TypeRef) bool {
+	return typeName(t) == "boolean"
+}
+
+func isNullLiteral(n ast.Node) bool {
+	_, ok := n.(*ast.NullLiteral)
+	return ok
+}
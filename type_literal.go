@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tzmfreedom/land/ast"
+)
+
+// TypeLiteral is a type literal such as Foo.class or List<Foo>.class. land's
+// AST builder returns the bare TypeRef for them, which would be emitted as
+// the type name, so they are wrapped after the build and emitted as the
+// runtime's Type tokens, which keep the type parameters Java erases.
+type TypeLiteral struct {
+	TypeRef  *ast.TypeRef
+	Location *ast.Location
+	Parent   ast.Node
+}
+
+// TypeLiteralVisitor is implemented by visitors that handle TypeLiteral.
+// Visitors without it skip type literals.
+type TypeLiteralVisitor interface {
+	VisitTypeLiteral(n *TypeLiteral) (interface{}, error)
+}
+
+func (n *TypeLiteral) Accept(v ast.Visitor) (interface{}, error) {
+	if tv, ok := v.(TypeLiteralVisitor); ok {
+		return tv.VisitTypeLiteral(n)
+	}
+	return nil, nil
+}
+
+func (n *TypeLiteral) GetChildren() []interface{} {
+	return []interface{}{
+		n.TypeRef,
+	}
+}
+
+func (n *TypeLiteral) GetType() string {
+	return "TypeLiteral"
+}
+
+func (n *TypeLiteral) GetParent() ast.Node {
+	return n.Parent
+}
+
+func (n *TypeLiteral) SetParent(parent ast.Node) {
+	n.Parent = parent
+}
+
+func (n *TypeLiteral) GetLocation() *ast.Location {
+	return n.Location
+}
+
+// replaceTypeLiterals wraps the TypeRefs in n that are expressions, which
+// are held in fields of type ast.Node unlike declared types, in
+// TypeLiterals.
+func replaceTypeLiterals(n ast.Node) {
+	replaceNodes(reflect.ValueOf(n), func(n ast.Node) ast.Node {
+		if t, ok := n.(*ast.TypeRef); ok {
+			literal := &TypeLiteral{TypeRef: t, Location: t.Location, Parent: t.Parent}
+			t.Parent = literal
+			return literal
+		}
+		return n
+	})
+}
+
+// typeToken returns the expression creating the runtime Type of t, such as
+// Type.of(List.class, Type.of(Account.class)).
+func typeToken(t *ast.TypeRef) string {
+	args := []string{javaTypeName(&ast.TypeRef{Name: t.Name, Dimmension: t.Dimmension}) + ".class"}
+	for _, p := range t.Parameters {
+		args = append(args, typeToken(p))
+	}
+	return fmt.Sprintf("Type.of(%s)", strings.Join(args, ", "))
+}
+
+func (v *Generator) VisitTypeLiteral(n *TypeLiteral) (interface{}, error) {
+	return typeToken(n.TypeRef), nil
+}
+
+func (v *ImportTypeResolver) VisitTypeLiteral(n *TypeLiteral) (interface{}, error) {
+	v.importClasses[ImportClasses["type"]] = struct{}{}
+	return n.TypeRef.Accept(v)
+}